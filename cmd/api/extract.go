@@ -0,0 +1,99 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/gustavoflandal/gmail-scanner/internal/imap"
+	"github.com/sirupsen/logrus"
+)
+
+// extractedLink acrescenta ao link a decisão da política de score/classe
+type extractedLink struct {
+	imap.EmailLink
+	Keep bool   `json:"keep"`
+	Tag  string `json:"tag,omitempty"`
+}
+
+// runExtract implementa o subcomando "extract": imprime em JSON os links que a
+// varredura extrairia de cada arquivo .eml, para depurar layouts de newsletters
+func runExtract(args []string) int {
+	flags := flag.NewFlagSet("extract", flag.ContinueOnError)
+	profilesFile := flags.String("profiles", os.Getenv("LINK_PROFILES_FILE"), "JSON file with link extraction profiles")
+	threshold := flags.String("threshold", os.Getenv("LINK_SCORE_THRESHOLD"), "minimum link score (0 to 1)")
+	actions := flags.String("actions", os.Getenv("LINK_CLASS_ACTIONS"), "per-class actions, e.g. sponsor=drop,job=tag")
+	verbose := flags.Bool("v", false, "show extraction logs")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "usage: gmail-scanner extract [flags] file.eml [file.eml...]")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return 2
+	}
+
+	if !*verbose {
+		imap.SetLogLevel(logrus.WarnLevel)
+	}
+
+	var userProfiles []imap.Profile
+	if *profilesFile != "" {
+		var err error
+		userProfiles, err = imap.LoadProfiles(*profilesFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to load link profiles: %v\n", err)
+			return 1
+		}
+	}
+	profiles := imap.NewRegistry(userProfiles)
+
+	policy, err := imap.ParseLinkPolicy(*threshold, *actions)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid link policy: %v\n", err)
+		return 1
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+
+	status := 0
+	for _, path := range flags.Args() {
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", path, err)
+			status = 1
+			continue
+		}
+
+		msg, err := imap.ParseEML(raw, profiles)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", path, err)
+			status = 1
+			continue
+		}
+
+		output := struct {
+			File string `json:"file"`
+			imap.Extraction
+			Links []extractedLink `json:"links"`
+		}{File: path, Extraction: msg.Extraction(), Links: []extractedLink{}}
+
+		for _, link := range msg.Links {
+			keep, tag := policy.Decide(link)
+			output.Links = append(output.Links, extractedLink{EmailLink: link, Keep: keep, Tag: tag})
+		}
+
+		if err := encoder.Encode(output); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", path, err)
+			status = 1
+		}
+	}
+
+	return status
+}
@@ -2,18 +2,31 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"mime"
 	"net/http"
+	"net/url"
 	"os"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/gustavoflandal/gmail-scanner/internal/auth"
 	"github.com/gustavoflandal/gmail-scanner/internal/database"
+	"github.com/gustavoflandal/gmail-scanner/internal/export"
+	"github.com/gustavoflandal/gmail-scanner/internal/imap"
+	"github.com/gustavoflandal/gmail-scanner/internal/importer"
 	"github.com/gustavoflandal/gmail-scanner/internal/nosql"
+	"github.com/gustavoflandal/gmail-scanner/internal/resolver"
+	"github.com/gustavoflandal/gmail-scanner/internal/rules"
 	"github.com/gustavoflandal/gmail-scanner/internal/scraper"
+	"github.com/gustavoflandal/gmail-scanner/internal/thumbnail"
+	"github.com/gustavoflandal/gmail-scanner/internal/unsubscribe"
+	"github.com/gustavoflandal/gmail-scanner/internal/urlnorm"
 	"github.com/sirupsen/logrus"
 )
 
@@ -21,6 +34,10 @@ var (
 	log          *logrus.Logger
 	db           *database.Database
 	nosqlDB      *nosql.NoSQLDB
+	linkResolver *resolver.Resolver
+	linkProfiles *imap.Registry
+	linkPolicy   imap.LinkPolicy
+	thumbnails   *thumbnail.Cache // nil = cache de miniaturas desativado
 	scanMutex    sync.Mutex
 	scanStatus   *ScanStatus
 	isScanning   bool
@@ -44,6 +61,8 @@ type ScanProgress struct {
 	EmailsTotal      int    `json:"emails_total"`
 	EmailsProcessed  int    `json:"emails_processed"`
 	ArticlesFound    int    `json:"articles_found"`
+	ArticlesSkipped  int    `json:"articles_skipped"`  // Descartados por regras
+	ArticlesImported int    `json:"articles_imported"` // Importados automaticamente por regras
 	PercentComplete  int    `json:"percent_complete"`
 	Status           string `json:"status"`
 }
@@ -69,6 +88,11 @@ func init() {
 }
 
 func main() {
+	// Subcomando de depuração da extração: gmail-scanner extract arquivo.eml
+	if len(os.Args) > 1 && os.Args[1] == "extract" {
+		os.Exit(runExtract(os.Args[2:]))
+	}
+
 	// Create data directory if needed
 	if _, err := os.Stat("./data"); os.IsNotExist(err) {
 		os.Mkdir("./data", 0755)
@@ -92,6 +116,79 @@ func main() {
 	}
 	defer nosqlDB.Close()
 
+	// Fundir artigos que ficaram duplicados sob a chave canônica de URL
+	if merges, err := db.MergeAllDuplicates(); err != nil {
+		log.Warnf("Failed to merge duplicate articles: %v", err)
+	} else if len(merges) > 0 {
+		moveReadingListEntries(merges)
+		log.Infof("Merged %d groups of duplicate articles", len(merges))
+	}
+
+	// Resolver de links de rastreamento (RESOLVER_ONLINE=false desativa o acesso à rede)
+	linkResolver = resolver.New(db, os.Getenv("RESOLVER_ONLINE") != "false")
+
+	// Perfis de extração de links por newsletter (LINK_PROFILES_FILE complementa os embutidos)
+	var userProfiles []imap.Profile
+	if profilesFile := os.Getenv("LINK_PROFILES_FILE"); profilesFile != "" {
+		userProfiles, err = imap.LoadProfiles(profilesFile)
+		if err != nil {
+			log.Warnf("Failed to load link profiles: %v", err)
+		} else {
+			log.Infof("Loaded %d link extraction profiles from %s", len(userProfiles), profilesFile)
+		}
+	}
+	linkProfiles = imap.NewRegistry(userProfiles)
+
+	// Política de score/classe dos links (LINK_SCORE_THRESHOLD e LINK_CLASS_ACTIONS)
+	linkPolicy, err = imap.ParseLinkPolicy(os.Getenv("LINK_SCORE_THRESHOLD"), os.Getenv("LINK_CLASS_ACTIONS"))
+	if err != nil {
+		log.Warnf("Invalid link policy, using defaults: %v", err)
+		linkPolicy = imap.DefaultLinkPolicy()
+	}
+
+	// Requisições do scraper: User-Agent, limite por host e robots.txt
+	rateLimit, _ := strconv.ParseFloat(os.Getenv("SCRAPER_RATE_LIMIT"), 64)
+	rateBurst, _ := strconv.Atoi(os.Getenv("SCRAPER_RATE_BURST"))
+	fetcherConfig := scraper.FetcherConfig{
+		UserAgent:         os.Getenv("SCRAPER_USER_AGENT"),
+		RequestsPerSecond: rateLimit,
+		Burst:             rateBurst,
+		IgnoreRobots:      os.Getenv("SCRAPER_RESPECT_ROBOTS") == "false",
+	}
+
+	// Cache das páginas baixadas (HTTP_CACHE_DIR vazio desativa)
+	if cacheDir := os.Getenv("HTTP_CACHE_DIR"); cacheDir != "" {
+		maxMB, err := strconv.ParseInt(os.Getenv("HTTP_CACHE_MAX_MB"), 10, 64)
+		if err != nil || maxMB <= 0 {
+			maxMB = 500
+		}
+		fetcherConfig.Cache, err = scraper.NewResponseCache(cacheDir, maxMB<<20)
+		if err != nil {
+			log.Warnf("HTTP cache disabled: %v", err)
+		}
+	}
+	scraper.SetDefaultFetcher(scraper.NewFetcher(fetcherConfig))
+
+	// API do GitHub (vazio usa api.github.com; GitHub Enterprise usa <host>/api/v3)
+	scraper.SetGitHubAPIURL(os.Getenv("GITHUB_API_URL"))
+
+	// Espelhos e cadeias de estratégias do Medium (MEDIUM_MIRRORS e MEDIUM_STRATEGIES)
+	mediumConfig, err := scraper.ParseMediumConfig(os.Getenv("MEDIUM_MIRRORS"), os.Getenv("MEDIUM_STRATEGIES"))
+	if err != nil {
+		log.Warnf("Invalid Medium strategies, using defaults: %v", err)
+		mediumConfig = scraper.DefaultMediumConfig()
+	}
+	scraper.ConfigureMedium(mediumConfig)
+
+	// Cache local de miniaturas (THUMBNAIL_CACHE_DIR vazio desativa)
+	if thumbnailDir := os.Getenv("THUMBNAIL_CACHE_DIR"); thumbnailDir != "" {
+		width, _ := strconv.Atoi(os.Getenv("THUMBNAIL_WIDTH"))
+		thumbnails, err = thumbnail.New(thumbnailDir, width)
+		if err != nil {
+			log.Warnf("Thumbnail cache disabled: %v", err)
+		}
+	}
+
 	router := mux.NewRouter()
 	router.Use(corsMiddleware)
 
@@ -101,9 +198,34 @@ func main() {
 
 	// API routes (requerem autenticação)
 	router.HandleFunc("/api/articles", authMiddleware(getAllArticles)).Methods("GET", "OPTIONS")
+	router.HandleFunc("/api/articles/export", authMiddleware(exportArticles)).Methods("GET", "OPTIONS")
+	router.HandleFunc("/api/articles/import", authMiddleware(importArticles)).Methods("POST", "OPTIONS")
+	router.HandleFunc("/api/articles/status", authMiddleware(updateArticlesStatus)).Methods("POST", "OPTIONS")
+	router.HandleFunc("/api/articles/star", authMiddleware(updateArticlesStarred)).Methods("POST", "OPTIONS")
+	router.HandleFunc("/api/articles/tags", authMiddleware(updateArticlesTags)).Methods("POST", "OPTIONS")
+	router.HandleFunc("/api/articles/merge", authMiddleware(mergeArticles)).Methods("POST", "OPTIONS")
+	router.HandleFunc("/api/articles/dedupe", authMiddleware(dedupeArticles)).Methods("POST", "OPTIONS")
+	router.HandleFunc("/api/articles/{id}/mentions", authMiddleware(getArticleMentions)).Methods("GET", "OPTIONS")
+	router.HandleFunc("/api/articles/{id}/thumbnail", authMiddleware(getArticleThumbnail)).Methods("GET", "OPTIONS")
 	router.HandleFunc("/api/articles/{id}", authMiddleware(deleteArticle)).Methods("DELETE", "OPTIONS")
 	router.HandleFunc("/api/articles/stats", authMiddleware(getArticleStats)).Methods("GET", "OPTIONS")
 	router.HandleFunc("/api/newsletters", authMiddleware(getNewsletters)).Methods("GET", "OPTIONS")
+	router.HandleFunc("/api/newsletters", authMiddleware(createNewsletter)).Methods("POST", "OPTIONS")
+	router.HandleFunc("/api/newsletters/ranking", authMiddleware(rankNewsletters)).Methods("GET", "OPTIONS")
+	router.HandleFunc("/api/newsletters/{id}", authMiddleware(getNewsletter)).Methods("GET", "OPTIONS")
+	router.HandleFunc("/api/newsletters/{id}", authMiddleware(updateNewsletter)).Methods("PUT", "OPTIONS")
+	router.HandleFunc("/api/newsletters/{id}", authMiddleware(deleteNewsletter)).Methods("DELETE", "OPTIONS")
+	router.HandleFunc("/api/newsletters/{id}/merge", authMiddleware(mergeNewsletters)).Methods("POST", "OPTIONS")
+	router.HandleFunc("/api/newsletters/{id}/unsubscribe", authMiddleware(unsubscribeNewsletter)).Methods("POST", "OPTIONS")
+	router.HandleFunc("/api/tags", authMiddleware(getTags)).Methods("GET", "OPTIONS")
+	router.HandleFunc("/api/tags/{name}", authMiddleware(deleteTag)).Methods("DELETE", "OPTIONS")
+
+	// Regras de auto-tag/auto-ocultar/auto-importar
+	router.HandleFunc("/api/rules", authMiddleware(getRules)).Methods("GET", "OPTIONS")
+	router.HandleFunc("/api/rules", authMiddleware(createRule)).Methods("POST", "OPTIONS")
+	router.HandleFunc("/api/rules/preview", authMiddleware(previewRule)).Methods("POST", "OPTIONS")
+	router.HandleFunc("/api/rules/{id}", authMiddleware(updateRule)).Methods("PUT", "OPTIONS")
+	router.HandleFunc("/api/rules/{id}", authMiddleware(deleteRule)).Methods("DELETE", "OPTIONS")
 
 	// Rotas legadas para compatibilidade com frontend
 	router.HandleFunc("/api/links", authMiddleware(getAllArticles)).Methods("GET", "OPTIONS")
@@ -113,9 +235,13 @@ func main() {
 	// Rotas NoSQL - Lista de Leitura (rotas específicas ANTES das rotas com parâmetros)
 	router.HandleFunc("/api/reading-list/import", authMiddleware(importToReadingList)).Methods("POST", "OPTIONS")
 	router.HandleFunc("/api/reading-list/imported-ids", authMiddleware(getImportedIDs)).Methods("GET", "OPTIONS")
+	router.HandleFunc("/api/reading-list/export", authMiddleware(exportReadingList)).Methods("GET", "OPTIONS")
+	router.HandleFunc("/api/reading-list/reextract", authMiddleware(reextractReadingList)).Methods("POST", "OPTIONS")
 	router.HandleFunc("/api/reading-list", authMiddleware(getAllFromReadingList)).Methods("GET", "OPTIONS")
 	router.HandleFunc("/api/reading-list/{id}", authMiddleware(getFromReadingList)).Methods("GET", "OPTIONS")
 	router.HandleFunc("/api/reading-list/{id}", authMiddleware(deleteFromReadingList)).Methods("DELETE", "OPTIONS")
+	router.HandleFunc("/api/reading-list/{id}/reextract", authMiddleware(reextractReadingListArticle)).Methods("POST", "OPTIONS")
+	router.HandleFunc("/api/reading-list/{id}/attachment", authMiddleware(getReadingListAttachment)).Methods("GET", "OPTIONS")
 
 	router.HandleFunc("/api/scan", authMiddleware(startScan)).Methods("POST", "OPTIONS")
 	router.HandleFunc("/api/scan-status", authMiddleware(getScanStatus)).Methods("GET", "OPTIONS")
@@ -123,6 +249,7 @@ func main() {
 	router.HandleFunc("/api/scan-cancel", authMiddleware(cancelScanHandler)).Methods("POST", "OPTIONS")
 	router.HandleFunc("/api/folders", authMiddleware(getFolders)).Methods("GET", "OPTIONS")
 	router.HandleFunc("/api/stats", authMiddleware(getStats)).Methods("GET", "OPTIONS")
+	router.HandleFunc("/api/scraper/medium-strategies", authMiddleware(getMediumStrategyStats)).Methods("GET", "OPTIONS")
 
 	// API routes públicas
 	router.HandleFunc("/api/health", getHealth).Methods("GET", "OPTIONS")
@@ -150,7 +277,7 @@ func main() {
 func corsMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, DELETE, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
 		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
 		w.Header().Set("Access-Control-Allow-Credentials", "true")
 		if r.Method == "OPTIONS" {
@@ -240,6 +367,13 @@ func getStats(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(stats)
 }
 
+// getMediumStrategyStats retorna o sucesso e a falha de cada estratégia do Medium
+// (espelhos e acesso direto) desde o início do servidor
+func getMediumStrategyStats(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(scraper.MediumStrategyStats())
+}
+
 // startScan inicia uma varredura manual de emails
 func startScan(w http.ResponseWriter, r *http.Request) {
 	scanMutex.Lock()
@@ -329,10 +463,23 @@ func performScan(session *auth.Session, folders []string) {
 	scanProgress.EmailsTotal = 0
 	scanProgress.EmailsProcessed = 0
 	scanProgress.ArticlesFound = 0
+	scanProgress.ArticlesSkipped = 0
+	scanProgress.ArticlesImported = 0
 	scanProgress.PercentComplete = 0
 	scanProgress.Status = "connecting"
 	scanMutex.Unlock()
 
+	// Carregar regras do usuário
+	ruleEngine, err := loadRuleEngine()
+	if err != nil {
+		scanMutex.Lock()
+		scanStatus.LastError = fmt.Sprintf("Falha ao carregar regras: %v", err)
+		scanProgress.Status = "error"
+		scanMutex.Unlock()
+		log.Errorf("Failed to load rules: %v", err)
+		return
+	}
+
 	// Conectar IMAP
 	imapClient, err := session.GetIMAPClient()
 	if err != nil {
@@ -344,12 +491,15 @@ func performScan(session *auth.Session, folders []string) {
 		return
 	}
 	defer imapClient.Close()
+	imapClient.UseProfiles(linkProfiles)
 
 	scanMutex.Lock()
 	scanProgress.Status = "scanning"
 	scanMutex.Unlock()
 
 	totalArticleCount := 0
+	skippedCount := 0
+	var autoImport []database.Article
 
 	// Processar cada pasta
 	for i, folder := range folders {
@@ -402,16 +552,64 @@ func performScan(session *auth.Session, folders []string) {
 				}
 			}
 
+			// Identificar a newsletter pelo List-Id (ou remetente); silenciadas são ignoradas
+			var newsletterID int64
+			newsletterName := msg.From
+			if len(msg.Links) > 0 || msg.ListUnsubscribe != "" {
+				if newsletter, err := db.ResolveNewsletter(msg.From, msg.ListID); err != nil {
+					log.Warnf("Failed to resolve newsletter for %s: %v", msg.From, err)
+				} else {
+					if msg.ListUnsubscribe != "" {
+						if err := db.SetNewsletterUnsubscribe(newsletter.ID, msg.ListUnsubscribe, msg.ListUnsubscribePost); err != nil {
+							log.Warnf("Failed to save unsubscribe headers for newsletter %d: %v", newsletter.ID, err)
+						}
+					}
+
+					if newsletter.Muted {
+						skippedCount += len(msg.Links)
+						msg.Links = nil
+					} else {
+						newsletterID = newsletter.ID
+						newsletterName = newsletter.Name
+					}
+				}
+			}
+
 			// Salvar cada link como um artigo
 			for _, link := range msg.Links {
+				// Resolver antes de decidir: a classe depende do domínio de destino,
+				// não do rastreador que envolve o link
+				articleURL, domain, sourceURL := resolveLink(link)
+				if sourceURL != "" {
+					link.Retarget(articleURL, domain)
+				}
+
+				keep, classTag := linkPolicy.Decide(link)
+				if !keep {
+					skippedCount++
+					continue
+				}
+
 				article := &database.Article{
-					URL:         link.URL,
-					Title:       link.Title,
-					Description: link.Description,
-					Domain:      link.Domain,
-					Newsletter:  msg.From,
-					EmailDate:   msg.Date.Format(time.RFC3339),
-					Folder:      msg.Folder,
+					URL:          articleURL,
+					Title:        link.Title,
+					Description:  link.Description,
+					Domain:       domain,
+					Newsletter:   newsletterName,
+					NewsletterID: newsletterID,
+					EmailDate:    msg.Date.Format(time.RFC3339),
+					Folder:       msg.Folder,
+					SourceURL:    sourceURL,
+					Score:        link.Score,
+					Class:        link.Class,
+					ImageURL:     link.ImageURL,
+					ImageAlt:     link.ImageAlt,
+				}
+
+				result := ruleEngine.Evaluate(rules.CandidateFromArticle(*article))
+				if result.Skip {
+					skippedCount++
+					continue
 				}
 
 				if err := db.IndexArticle(article); err != nil {
@@ -420,11 +618,25 @@ func performScan(session *auth.Session, folders []string) {
 				}
 
 				totalArticleCount++
+
+				// Ações só valem para artigos novos, para não desfazer mudanças do usuário
+				if article.ID != 0 && classTag != "" {
+					if err := db.AddTags([]int64{article.ID}, []string{classTag}); err != nil {
+						log.Warnf("Failed to tag article %d as %s: %v", article.ID, classTag, err)
+					}
+				}
+				if article.ID != 0 && result.Matched() {
+					applyRuleResult(article, result)
+					if result.AutoImport {
+						autoImport = append(autoImport, *article)
+					}
+				}
 			}
 
 			scanMutex.Lock()
 			scanProgress.EmailsProcessed++
 			scanProgress.ArticlesFound = totalArticleCount
+			scanProgress.ArticlesSkipped = skippedCount
 			scanMutex.Unlock()
 
 			// Log a cada 50 emails processados
@@ -434,6 +646,35 @@ func performScan(session *auth.Session, folders []string) {
 		}
 	}
 
+	// Importar para a lista de leitura os artigos marcados pelas regras
+	if len(autoImport) > 0 {
+		scanMutex.Lock()
+		scanProgress.Status = "importing"
+		scanMutex.Unlock()
+
+		for _, article := range autoImport {
+			select {
+			case <-cancelScan:
+				log.Infof("Scan cancelled by user")
+				scanMutex.Lock()
+				scanStatus.LastError = "Varredura cancelada pelo usuário"
+				scanProgress.Status = "cancelled"
+				scanMutex.Unlock()
+				return
+			default:
+			}
+
+			if _, err := importArticle(importRequestFromArticle(article)); err != nil {
+				log.Warnf("Auto-import failed for article %d: %v", article.ID, err)
+				continue
+			}
+
+			scanMutex.Lock()
+			scanProgress.ArticlesImported++
+			scanMutex.Unlock()
+		}
+	}
+
 	scanMutex.Lock()
 	scanStatus.LastEmailsScanned = scanProgress.EmailsProcessed
 	scanStatus.LastError = ""
@@ -447,6 +688,22 @@ func performScan(session *auth.Session, folders []string) {
 		totalArticleCount, scanProgress.EmailsProcessed, len(folders))
 }
 
+// resolveLink troca links de rastreamento pela URL final do artigo.
+// O link original é devolvido em sourceURL como procedência.
+func resolveLink(link imap.EmailLink) (articleURL, domain, sourceURL string) {
+	finalURL, resolved := linkResolver.Resolve(link.URL)
+	if !resolved {
+		return link.URL, link.Domain, ""
+	}
+
+	parsedURL, err := url.Parse(finalURL)
+	if err != nil || parsedURL.Host == "" {
+		return link.URL, link.Domain, ""
+	}
+
+	return urlnorm.Normalize(parsedURL), parsedURL.Hostname(), link.URL
+}
+
 // getScanStatus retorna o status da varredura
 func getScanStatus(w http.ResponseWriter, r *http.Request) {
 	scanMutex.Lock()
@@ -603,11 +860,14 @@ func getAllArticles(w http.ResponseWriter, r *http.Request) {
 
 	log.Infof("GetAllArticles: page=%d, pageSize=%d (requested: %s)", page, pageSize, pageSizeStr)
 
-	domain := r.URL.Query().Get("domain")
-	search := r.URL.Query().Get("q")
-	newsletter := r.URL.Query().Get("newsletter")
+	filter, err := parseArticleFilter(r)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
 
-	articles, total, err := db.GetAllArticles(page, pageSize, domain, search, newsletter)
+	articles, total, err := db.GetAllArticles(page, pageSize, filter)
 	if err != nil {
 		log.Errorf("Failed to get articles: %v", err)
 		w.WriteHeader(http.StatusInternalServerError)
@@ -625,224 +885,1557 @@ func getAllArticles(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-// deleteArticle deleta um artigo específico
-func deleteArticle(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	idStr := vars["id"]
+// parseArticleFilter lê os filtros de listagem da query string
+// (domain, q, newsletter, newsletter_id, class, min_score, status=unread,read,
+// starred=true|false e tags=a,b&tag_mode=and|or)
+func parseArticleFilter(r *http.Request) (database.ArticleFilter, error) {
+	query := r.URL.Query()
+	filter := database.ArticleFilter{
+		Domain:     query.Get("domain"),
+		Search:     query.Get("q"),
+		Newsletter: query.Get("newsletter"),
+	}
 
-	id, err := strconv.ParseInt(idStr, 10, 64)
+	if idStr := query.Get("newsletter_id"); idStr != "" {
+		id, err := strconv.ParseInt(idStr, 10, 64)
+		if err != nil {
+			return filter, fmt.Errorf("valor inválido para newsletter_id: %s", idStr)
+		}
+		filter.NewsletterID = id
+	}
+
+	if class := query.Get("class"); class != "" {
+		if !imap.IsValidClass(class) {
+			return filter, fmt.Errorf("classe inválida: %s", class)
+		}
+		filter.Class = class
+	}
+
+	if scoreStr := query.Get("min_score"); scoreStr != "" {
+		score, err := strconv.ParseFloat(scoreStr, 64)
+		if err != nil {
+			return filter, fmt.Errorf("valor inválido para min_score: %s", scoreStr)
+		}
+		filter.MinScore = score
+	}
+
+	if statusStr := query.Get("status"); statusStr != "" {
+		for _, status := range strings.Split(statusStr, ",") {
+			status = strings.TrimSpace(status)
+			if !database.IsValidStatus(status) {
+				return filter, fmt.Errorf("status inválido: %s", status)
+			}
+			filter.Statuses = append(filter.Statuses, status)
+		}
+	}
+
+	if starredStr := query.Get("starred"); starredStr != "" {
+		starred, err := strconv.ParseBool(starredStr)
+		if err != nil {
+			return filter, fmt.Errorf("valor inválido para starred: %s", starredStr)
+		}
+		filter.Starred = &starred
+	}
+
+	tags, matchAll, err := parseTagFilter(r)
 	if err != nil {
-		log.Warnf("Invalid article ID: %s", idStr)
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]string{"error": "ID inválido"})
-		return
+		return filter, err
 	}
+	filter.Tags = tags
+	filter.TagsAll = matchAll
 
-	log.Infof("Attempting to delete article with ID: %d", id)
+	return filter, nil
+}
 
-	if err := db.DeleteArticle(id); err != nil {
-		log.Errorf("Failed to delete article %d: %v", id, err)
-		if err.Error() == "article not found" {
-			w.WriteHeader(http.StatusNotFound)
-			json.NewEncoder(w).Encode(map[string]string{"error": "artigo não encontrado"})
-			return
+// parseTagFilter lê tags=a,b e tag_mode=and|or (padrão: or)
+func parseTagFilter(r *http.Request) ([]string, bool, error) {
+	query := r.URL.Query()
+
+	var tags []string
+	for _, tag := range strings.Split(query.Get("tags"), ",") {
+		if tag = database.NormalizeTag(tag); tag != "" {
+			tags = append(tags, tag)
 		}
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]string{"error": "falha ao deletar artigo"})
-		return
 	}
 
-	log.Infof("Successfully deleted article with ID: %d", id)
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]string{"message": "artigo deletado com sucesso"})
+	switch mode := strings.ToLower(query.Get("tag_mode")); mode {
+	case "", "or":
+		return tags, false, nil
+	case "and":
+		return tags, true, nil
+	default:
+		return nil, false, fmt.Errorf("tag_mode inválido: %s", mode)
+	}
 }
 
-// getArticleStats retorna estatísticas sobre os artigos
-func getArticleStats(w http.ResponseWriter, r *http.Request) {
-	stats, err := db.GetArticleStats()
+// BulkStatusRequest representa a alteração de estado em lote
+type BulkStatusRequest struct {
+	IDs    []int64 `json:"ids"`
+	Status string  `json:"status"`
+}
+
+// BulkStarRequest representa a marcação de favoritos em lote
+type BulkStarRequest struct {
+	IDs     []int64 `json:"ids"`
+	Starred bool    `json:"starred"`
+}
+
+// updateArticlesStatus altera o estado (unread/read/archived) de vários artigos
+func updateArticlesStatus(w http.ResponseWriter, r *http.Request) {
+	var req BulkStatusRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || len(req.IDs) == 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "dados inválidos"})
+		return
+	}
+
+	if !database.IsValidStatus(req.Status) {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "status inválido"})
+		return
+	}
+
+	updated, err := db.SetArticlesStatus(req.IDs, req.Status)
 	if err != nil {
-		log.Errorf("Failed to get article stats: %v", err)
+		log.Errorf("Failed to update article status: %v", err)
 		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]string{"error": "falha ao buscar estatísticas"})
+		json.NewEncoder(w).Encode(map[string]string{"error": "falha ao atualizar artigos"})
 		return
 	}
 
+	log.Infof("Updated status of %d articles to %s", updated, req.Status)
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(stats)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"updated": updated,
+		"status":  req.Status,
+	})
 }
 
-// getNewsletters retorna lista de newsletters únicas
-func getNewsletters(w http.ResponseWriter, r *http.Request) {
-	newsletters, err := db.GetNewsletters()
+// updateArticlesStarred marca ou desmarca vários artigos como favoritos
+func updateArticlesStarred(w http.ResponseWriter, r *http.Request) {
+	var req BulkStarRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || len(req.IDs) == 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "dados inválidos"})
+		return
+	}
+
+	updated, err := db.SetArticlesStarred(req.IDs, req.Starred)
 	if err != nil {
-		log.Errorf("Failed to get newsletters: %v", err)
+		log.Errorf("Failed to update article starred flag: %v", err)
 		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]string{"error": "falha ao buscar newsletters"})
+		json.NewEncoder(w).Encode(map[string]string{"error": "falha ao atualizar artigos"})
 		return
 	}
 
+	log.Infof("Updated starred flag of %d articles to %v", updated, req.Starred)
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]interface{}{
-		"newsletters": newsletters,
+		"updated": updated,
+		"starred": req.Starred,
 	})
 }
 
-// ==================== NoSQL Reading List Handlers ====================
-
-// ImportRequest representa a requisição de importação
-type ImportRequest struct {
-	ID          int64  `json:"id"`
-	URL         string `json:"url"`
-	Title       string `json:"title"`
-	Description string `json:"description"`
-	Domain      string `json:"domain"`
-	Newsletter  string `json:"newsletter"`
-	EmailDate   string `json:"email_date"`
-	Folder      string `json:"folder"`
+// BulkTagsRequest representa a adição/remoção de tags em lote
+type BulkTagsRequest struct {
+	IDs    []int64  `json:"ids"`
+	Add    []string `json:"add"`
+	Remove []string `json:"remove"`
 }
 
-// importToReadingList importa um artigo para a lista de leitura (NoSQL)
-func importToReadingList(w http.ResponseWriter, r *http.Request) {
-	var req ImportRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		log.Errorf("Failed to decode import request: %v", err)
+// updateArticlesTags adiciona e remove tags de vários artigos
+func updateArticlesTags(w http.ResponseWriter, r *http.Request) {
+	var req BulkTagsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || len(req.IDs) == 0 {
 		w.WriteHeader(http.StatusBadRequest)
 		json.NewEncoder(w).Encode(map[string]string{"error": "dados inválidos"})
 		return
 	}
 
-	// Buscar conteúdo do artigo
-	log.Infof("Fetching article content from: %s", req.URL)
-	articleContent, err := scraper.FetchArticleContent(req.URL)
+	if len(req.Add) == 0 && len(req.Remove) == 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "informe tags para adicionar ou remover"})
+		return
+	}
 
-	var content string
-	var contentType string
+	if database.ValidateTags(req.Add) != nil || database.ValidateTags(req.Remove) != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "tags não podem conter vírgula"})
+		return
+	}
 
-	if err != nil {
-		log.Warnf("Failed to fetch article content: %v - saving without content", err)
-		content = ""
-		contentType = ""
-	} else {
-		content = articleContent.Content
-		contentType = articleContent.ContentType
-		log.Infof("Successfully fetched article content (%d chars)", len(content))
+	if err := db.AddTags(req.IDs, req.Add); err != nil {
+		log.Errorf("Failed to add tags: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "falha ao adicionar tags"})
+		return
 	}
 
-	article := nosql.Article{
-		ID:          req.ID,
-		URL:         req.URL,
-		Title:       req.Title,
-		Description: req.Description,
-		Domain:      req.Domain,
-		Newsletter:  req.Newsletter,
-		EmailDate:   req.EmailDate,
-		Folder:      req.Folder,
-		Content:     content,
-		ContentType: contentType,
+	if err := db.RemoveTags(req.IDs, req.Remove); err != nil {
+		log.Errorf("Failed to remove tags: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "falha ao remover tags"})
+		return
 	}
 
-	if err := nosqlDB.ImportArticle(article); err != nil {
-		log.Errorf("Failed to import article: %v", err)
+	tagsByArticle, err := db.GetArticleTags(req.IDs)
+	if err != nil {
+		log.Errorf("Failed to get article tags: %v", err)
 		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]string{"error": "falha ao importar artigo"})
+		json.NewEncoder(w).Encode(map[string]string{"error": "falha ao buscar tags"})
 		return
 	}
 
-	log.Infof("Article imported to reading list: ID=%d, Title=%s, ContentSize=%d", req.ID, req.Title, len(content))
+	// Manter as tags da lista de leitura em sincronia
+	for _, id := range req.IDs {
+		if err := nosqlDB.SetArticleTags(id, tagsByArticle[id]); err != nil {
+			log.Warnf("Failed to sync tags to reading list for article %d: %v", id, err)
+		}
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]interface{}{
-		"message":      "artigo importado com sucesso",
-		"id":           req.ID,
-		"has_content":  content != "",
-		"content_size": len(content),
+		"tags": tagsByArticle,
 	})
 }
 
-// getFromReadingList obtém um artigo da lista de leitura
-func getFromReadingList(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	idStr := vars["id"]
-
-	id, err := strconv.ParseInt(idStr, 10, 64)
+// getTags retorna as tags com a contagem de artigos
+func getTags(w http.ResponseWriter, r *http.Request) {
+	tags, err := db.GetTags()
 	if err != nil {
+		log.Errorf("Failed to get tags: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "falha ao buscar tags"})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"tags": tags,
+	})
+}
+
+// deleteTag remove uma tag de todos os artigos
+func deleteTag(w http.ResponseWriter, r *http.Request) {
+	name := mux.Vars(r)["name"]
+	if database.ValidateTags([]string{name}) != nil {
 		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]string{"error": "ID inválido"})
+		json.NewEncoder(w).Encode(map[string]string{"error": "tags não podem conter vírgula"})
 		return
 	}
 
-	article, err := nosqlDB.GetArticle(id)
+	// Descobrir os artigos afetados antes de remover, para sincronizar a lista de leitura
+	filter := database.ArticleFilter{Tags: []string{name}}
+	affected, _, err := db.GetAllArticles(1, -1, filter)
 	if err != nil {
-		log.Errorf("Failed to get article from reading list: %v", err)
+		log.Errorf("Failed to find tagged articles: %v", err)
 		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]string{"error": "falha ao buscar artigo"})
+		json.NewEncoder(w).Encode(map[string]string{"error": "falha ao remover tag"})
 		return
 	}
 
-	if article == nil {
-		w.WriteHeader(http.StatusNotFound)
-		json.NewEncoder(w).Encode(map[string]string{"error": "artigo não encontrado na lista de leitura"})
+	if err := db.DeleteTag(name); err != nil {
+		if err.Error() == "tag not found" {
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(map[string]string{"error": "tag não encontrada"})
+			return
+		}
+		log.Errorf("Failed to delete tag %s: %v", name, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "falha ao remover tag"})
 		return
 	}
 
+	normalized := database.NormalizeTag(name)
+	for _, article := range affected {
+		var remaining []string
+		for _, tag := range article.Tags {
+			if tag != normalized {
+				remaining = append(remaining, tag)
+			}
+		}
+		if err := nosqlDB.SetArticleTags(article.ID, remaining); err != nil {
+			log.Warnf("Failed to sync tags to reading list for article %d: %v", article.ID, err)
+		}
+	}
+
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(article)
+	json.NewEncoder(w).Encode(map[string]string{"message": "tag removida com sucesso"})
 }
 
-// deleteFromReadingList remove um artigo da lista de leitura
-func deleteFromReadingList(w http.ResponseWriter, r *http.Request) {
+// deleteArticle deleta um artigo específico
+func deleteArticle(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	idStr := vars["id"]
 
 	id, err := strconv.ParseInt(idStr, 10, 64)
 	if err != nil {
+		log.Warnf("Invalid article ID: %s", idStr)
 		w.WriteHeader(http.StatusBadRequest)
 		json.NewEncoder(w).Encode(map[string]string{"error": "ID inválido"})
 		return
 	}
 
-	if err := nosqlDB.DeleteArticle(id); err != nil {
-		log.Errorf("Failed to delete article from reading list: %v", err)
+	log.Infof("Attempting to delete article with ID: %d", id)
+
+	if err := db.DeleteArticle(id); err != nil {
+		log.Errorf("Failed to delete article %d: %v", id, err)
+		if err.Error() == "article not found" {
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(map[string]string{"error": "artigo não encontrado"})
+			return
+		}
 		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]string{"error": "falha ao remover artigo"})
+		json.NewEncoder(w).Encode(map[string]string{"error": "falha ao deletar artigo"})
 		return
 	}
 
-	log.Infof("Article removed from reading list: ID=%d", id)
+	log.Infof("Successfully deleted article with ID: %d", id)
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]string{"message": "artigo removido da lista de leitura"})
+	json.NewEncoder(w).Encode(map[string]string{"message": "artigo deletado com sucesso"})
+}
+
+// getArticleStats retorna estatísticas sobre os artigos
+func getArticleStats(w http.ResponseWriter, r *http.Request) {
+	stats, err := db.GetArticleStats()
+	if err != nil {
+		log.Errorf("Failed to get article stats: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "falha ao buscar estatísticas"})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(stats)
+}
+
+// getNewsletters retorna as newsletters com suas estatísticas
+func getNewsletters(w http.ResponseWriter, r *http.Request) {
+	newsletters, err := db.GetNewsletters()
+	if err != nil {
+		log.Errorf("Failed to get newsletters: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "falha ao buscar newsletters"})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"newsletters": newsletters,
+		"total":       len(newsletters),
+	})
+}
+
+// NewsletterUpdate representa a edição parcial de uma newsletter
+type NewsletterUpdate struct {
+	Name    *string `json:"name"`
+	Domain  *string `json:"domain"`
+	IconURL *string `json:"icon_url"`
+	Muted   *bool   `json:"muted"`
+}
+
+// writeNewsletterError traduz os erros de newsletter em respostas HTTP
+func writeNewsletterError(w http.ResponseWriter, err error, action string) {
+	if err.Error() == "newsletter not found" {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{"error": "newsletter não encontrada"})
+		return
+	}
+	log.Errorf("Failed to %s newsletter: %v", action, err)
+	w.WriteHeader(http.StatusInternalServerError)
+	json.NewEncoder(w).Encode(map[string]string{"error": "falha ao processar newsletter"})
+}
+
+// getNewsletter retorna uma newsletter com suas estatísticas
+func getNewsletter(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "ID inválido"})
+		return
+	}
+
+	newsletter, err := db.GetNewsletter(id)
+	if err != nil {
+		writeNewsletterError(w, err, "get")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(newsletter)
+}
+
+// createNewsletter cadastra uma newsletter pelo List-Id e/ou remetente
+func createNewsletter(w http.ResponseWriter, r *http.Request) {
+	var newsletter database.Newsletter
+	if err := json.NewDecoder(r.Body).Decode(&newsletter); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "dados inválidos"})
+		return
+	}
+
+	if err := db.CreateNewsletter(&newsletter); err != nil {
+		switch err.Error() {
+		case "list_id or sender is required":
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "informe list_id ou sender"})
+		case "newsletter already exists":
+			w.WriteHeader(http.StatusConflict)
+			json.NewEncoder(w).Encode(map[string]string{"error": "newsletter já cadastrada"})
+		default:
+			writeNewsletterError(w, err, "create")
+		}
+		return
+	}
+
+	created, err := db.GetNewsletter(newsletter.ID)
+	if err != nil {
+		writeNewsletterError(w, err, "get")
+		return
+	}
+
+	log.Infof("Newsletter created: ID=%d, Name=%s", created.ID, created.Name)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(created)
+}
+
+// updateNewsletter altera nome, domínio, ícone e mute (campos omitidos ficam como estão)
+func updateNewsletter(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "ID inválido"})
+		return
+	}
+
+	var req NewsletterUpdate
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "dados inválidos"})
+		return
+	}
+
+	newsletter, err := db.GetNewsletter(id)
+	if err != nil {
+		writeNewsletterError(w, err, "get")
+		return
+	}
+
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "nome não pode ser vazio"})
+			return
+		}
+		newsletter.Name = name
+	}
+	if req.Domain != nil {
+		newsletter.Domain = strings.ToLower(strings.TrimSpace(*req.Domain))
+	}
+	if req.IconURL != nil {
+		newsletter.IconURL = strings.TrimSpace(*req.IconURL)
+	}
+	if req.Muted != nil {
+		newsletter.Muted = *req.Muted
+	}
+
+	if err := db.UpdateNewsletter(newsletter); err != nil {
+		writeNewsletterError(w, err, "update")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(newsletter)
+}
+
+// deleteNewsletter remove uma newsletter (os artigos são mantidos)
+func deleteNewsletter(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "ID inválido"})
+		return
+	}
+
+	if err := db.DeleteNewsletter(id); err != nil {
+		writeNewsletterError(w, err, "delete")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"message": "newsletter removida com sucesso"})
+}
+
+// mergeNewsletters junta outras newsletters (ex.: endereços alternativos) nesta
+func mergeNewsletters(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "ID inválido"})
+		return
+	}
+
+	var req struct {
+		IDs []int64 `json:"ids"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || len(req.IDs) == 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "dados inválidos"})
+		return
+	}
+
+	if err := db.MergeNewsletters(id, req.IDs); err != nil {
+		writeNewsletterError(w, err, "merge")
+		return
+	}
+
+	newsletter, err := db.GetNewsletter(id)
+	if err != nil {
+		writeNewsletterError(w, err, "get")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(newsletter)
+}
+
+// ==================== NoSQL Reading List Handlers ====================
+
+// ImportRequest representa a requisição de importação
+type ImportRequest struct {
+	ID          int64  `json:"id"`
+	URL         string `json:"url"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Domain      string `json:"domain"`
+	Newsletter  string `json:"newsletter"`
+	EmailDate   string `json:"email_date"`
+	Folder      string `json:"folder"`
+}
+
+// importToReadingList importa um artigo para a lista de leitura (NoSQL)
+func importToReadingList(w http.ResponseWriter, r *http.Request) {
+	var req ImportRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Errorf("Failed to decode import request: %v", err)
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "dados inválidos"})
+		return
+	}
+
+	article, err := importArticle(req)
+	if err != nil {
+		log.Errorf("Failed to import article: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "falha ao importar artigo"})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message":      "artigo importado com sucesso",
+		"id":           req.ID,
+		"has_content":  article.Content != "",
+		"content_size": len(article.Content),
+	})
+}
+
+// optionalTime converte datas zeradas (não declaradas pela página) em nil
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+// importArticle busca o conteúdo do artigo e o salva na lista de leitura.
+// Falhas no scraping não impedem a importação; o artigo é salvo sem conteúdo.
+func importArticle(req ImportRequest) (*nosql.Article, error) {
+	// Buscar conteúdo do artigo
+	log.Infof("Fetching article content from: %s", req.URL)
+	articleContent, err := scraper.FetchArticleContent(req.URL)
+
+	if err != nil {
+		log.Warnf("Failed to fetch article content: %v - saving without content", err)
+	} else {
+		log.Infof("Successfully fetched article content (%d chars)", len(articleContent.Content))
+
+		// A página declarou sua URL canônica: unificar duplicados sob ela
+		if articleContent.CanonicalURL != "" && req.ID != 0 {
+			if canonical, domain, ok := adoptCanonicalURL(req.ID, req.URL, articleContent.CanonicalURL); ok {
+				req.URL = canonical
+				req.Domain = domain
+			}
+		}
+	}
+
+	article := nosql.Article{
+		ID:          req.ID,
+		URL:         req.URL,
+		Title:       req.Title,
+		Description: req.Description,
+		Domain:      req.Domain,
+		Newsletter:  req.Newsletter,
+		EmailDate:   req.EmailDate,
+		Folder:      req.Folder,
+	}
+	if articleContent != nil {
+		setArticleContent(&article, articleContent)
+	}
+
+	// Reimportação sem anexo (a URL agora serve HTML, ou a busca falhou): manter o
+	// PDF de antes, cujos bytes continuam no bucket de anexos
+	if article.Attachment == nil {
+		if existing, err := nosqlDB.GetArticle(req.ID); err == nil && existing.Attachment != nil {
+			article.Attachment = existing.Attachment
+		}
+	}
+
+	// Levar as tags do artigo para a lista de leitura
+	if tagsByArticle, err := db.GetArticleTags([]int64{req.ID}); err != nil {
+		log.Warnf("Failed to get tags for article %d: %v", req.ID, err)
+	} else {
+		article.Tags = tagsByArticle[req.ID]
+	}
+
+	if err := nosqlDB.ImportArticle(article); err != nil {
+		return nil, err
+	}
+	if articleContent != nil {
+		storeAttachment(article.ID, articleContent)
+	}
+
+	log.Infof("Article imported to reading list: ID=%d, Title=%s, ContentSize=%d", req.ID, req.Title, len(article.Content))
+	return &article, nil
+}
+
+// setArticleContent copia o conteúdo extraído e os metadados da página para o artigo
+func setArticleContent(article *nosql.Article, content *scraper.ArticleContent) {
+	metadata := content.Metadata
+	article.Content = content.Content
+	article.ContentType = content.ContentType
+	article.Authors = metadata.Authors
+	article.PublishedAt = optionalTime(metadata.PublishedAt)
+	article.ModifiedAt = optionalTime(metadata.ModifiedAt)
+	article.SiteName = metadata.SiteName
+	article.LeadImage = metadata.LeadImage
+	article.Language = metadata.Language
+	article.WordCount = metadata.WordCount
+	article.ReadingTime = metadata.ReadingTime
+
+	// Páginas em HTML não trazem anexo: o artigo fica com o que já tinha (na
+	// reextração é o próprio registro; na reimportação, ver importArticle)
+	if attachment := content.Attachment; attachment != nil {
+		article.Attachment = &nosql.AttachmentInfo{
+			Filename:    attachment.Filename,
+			ContentType: attachment.ContentType,
+			Size:        int64(len(attachment.Data)),
+		}
+	}
+}
+
+// storeAttachment guarda o arquivo original (PDF) do artigo no bucket de anexos
+func storeAttachment(id int64, content *scraper.ArticleContent) {
+	if content.Attachment == nil {
+		return
+	}
+	if err := nosqlDB.SaveAttachment(id, content.Attachment.Data); err != nil {
+		log.Warnf("Failed to save attachment of article %d: %v", id, err)
+	}
+}
+
+// reextractReadingListArticle extrai de novo o conteúdo de um artigo da lista de
+// leitura a partir da página em cache, sem acessar a rede
+func reextractReadingListArticle(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "ID inválido"})
+		return
+	}
+
+	article, err := nosqlDB.GetArticle(id)
+	if err != nil {
+		log.Errorf("Failed to get article from reading list: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "falha ao buscar artigo"})
+		return
+	}
+	if article == nil {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{"error": "artigo não encontrado na lista de leitura"})
+		return
+	}
+
+	content, err := scraper.ReextractArticleContent(article.URL)
+	if errors.Is(err, scraper.ErrNotCached) {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{"error": "página do artigo não está no cache"})
+		return
+	}
+	if err != nil {
+		log.Warnf("Failed to re-extract article %d: %v", id, err)
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		json.NewEncoder(w).Encode(map[string]string{"error": "falha ao extrair o conteúdo da página em cache"})
+		return
+	}
+
+	if err := nosqlDB.UpdateArticle(id, func(a *nosql.Article) { setArticleContent(a, content) }); err != nil {
+		log.Errorf("Failed to update article %d: %v", id, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "falha ao salvar artigo"})
+		return
+	}
+	storeAttachment(id, content)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message":      "conteúdo extraído novamente",
+		"id":           id,
+		"content_size": len(content.Content),
+	})
+}
+
+// reextractReadingList extrai de novo, a partir do cache, todos os artigos da
+// lista de leitura cujas páginas estão guardadas
+func reextractReadingList(w http.ResponseWriter, r *http.Request) {
+	articles, err := nosqlDB.GetAllImported()
+	if err != nil {
+		log.Errorf("Failed to get reading list: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "falha ao buscar lista de leitura"})
+		return
+	}
+
+	updated, notCached, failed := 0, 0, 0
+	for _, article := range articles {
+		content, err := scraper.ReextractArticleContent(article.URL)
+		if errors.Is(err, scraper.ErrNotCached) {
+			notCached++
+			continue
+		}
+		if err == nil {
+			err = nosqlDB.UpdateArticle(article.ID, func(a *nosql.Article) { setArticleContent(a, content) })
+		}
+		if err != nil {
+			log.Warnf("Failed to re-extract article %d: %v", article.ID, err)
+			failed++
+			continue
+		}
+		storeAttachment(article.ID, content)
+		updated++
+	}
+
+	log.Infof("Re-extracted reading list from cache: %d updated, %d not cached, %d failed", updated, notCached, failed)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"updated":    updated,
+		"not_cached": notCached,
+		"failed":     failed,
+	})
+}
+
+// importRequestFromArticle monta a requisição de importação a partir de um artigo do SQLite
+func importRequestFromArticle(article database.Article) ImportRequest {
+	return ImportRequest{
+		ID:          article.ID,
+		URL:         article.URL,
+		Title:       article.Title,
+		Description: article.Description,
+		Domain:      article.Domain,
+		Newsletter:  article.Newsletter,
+		EmailDate:   article.EmailDate,
+		Folder:      article.Folder,
+	}
+}
+
+// getFromReadingList obtém um artigo da lista de leitura
+func getFromReadingList(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	idStr := vars["id"]
+
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "ID inválido"})
+		return
+	}
+
+	article, err := nosqlDB.GetArticle(id)
+	if err != nil {
+		log.Errorf("Failed to get article from reading list: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "falha ao buscar artigo"})
+		return
+	}
+
+	if article == nil {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{"error": "artigo não encontrado na lista de leitura"})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(article)
+}
+
+// deleteFromReadingList remove um artigo da lista de leitura
+func deleteFromReadingList(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	idStr := vars["id"]
+
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "ID inválido"})
+		return
+	}
+
+	if err := nosqlDB.DeleteArticle(id); err != nil {
+		log.Errorf("Failed to delete article from reading list: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "falha ao remover artigo"})
+		return
+	}
+
+	log.Infof("Article removed from reading list: ID=%d", id)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"message": "artigo removido da lista de leitura"})
 }
 
 // getAllFromReadingList obtém todos os artigos da lista de leitura
+// (aceita tags=a,b&tag_mode=and|or e q=termos, buscados no título, autores e texto)
 func getAllFromReadingList(w http.ResponseWriter, r *http.Request) {
+	tags, matchAll, err := parseTagFilter(r)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
 	articles, err := nosqlDB.GetAllImported()
 	if err != nil {
-		log.Errorf("Failed to get reading list: %v", err)
+		log.Errorf("Failed to get reading list: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "falha ao buscar lista de leitura"})
+		return
+	}
+
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+	if len(tags) > 0 || query != "" {
+		filtered := []nosql.Article{}
+		for _, article := range articles {
+			if (len(tags) == 0 || article.HasTags(tags, matchAll)) && article.Matches(query) {
+				filtered = append(filtered, article)
+			}
+		}
+		articles = filtered
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"articles": articles,
+		"total":    len(articles),
+	})
+}
+
+// getReadingListAttachment serve o arquivo original (PDF) de um artigo da lista de leitura
+func getReadingListAttachment(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "ID inválido"})
+		return
+	}
+
+	article, err := nosqlDB.GetArticle(id)
+	if err != nil {
+		log.Errorf("Failed to get article from reading list: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "falha ao buscar artigo"})
+		return
+	}
+	if article == nil || article.Attachment == nil {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{"error": "artigo sem anexo"})
+		return
+	}
+
+	data, err := nosqlDB.GetAttachment(id)
+	if err != nil {
+		log.Errorf("Failed to get attachment of article %d: %v", id, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "falha ao buscar anexo"})
+		return
+	}
+	if data == nil {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{"error": "artigo sem anexo"})
+		return
+	}
+
+	// inline: o navegador abre o PDF no visualizador em vez de baixar
+	w.Header().Set("Content-Type", article.Attachment.ContentType)
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("inline", map[string]string{"filename": article.Attachment.Filename}))
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Write(data)
+}
+
+// getImportedIDs retorna os IDs de todos os artigos importados
+func getImportedIDs(w http.ResponseWriter, r *http.Request) {
+	ids, err := nosqlDB.GetImportedIDs()
+	if err != nil {
+		log.Errorf("Failed to get imported IDs: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "falha ao buscar IDs importados"})
+		return
+	}
+
+	log.Infof("Returning %d imported IDs", len(ids))
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"imported_ids": ids,
+	})
+}
+
+// ==================== Rule Handlers ====================
+
+// loadRuleEngine carrega e compila as regras habilitadas
+func loadRuleEngine() (*rules.Engine, error) {
+	ruleList, err := db.GetRules()
+	if err != nil {
+		return nil, err
+	}
+
+	engine, err := rules.NewEngine(ruleList)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Infof("Loaded %d active rules", engine.Len())
+	return engine, nil
+}
+
+// applyRuleResult aplica as ações de tag e leitura a um artigo recém-indexado
+func applyRuleResult(article *database.Article, result rules.Result) {
+	if len(result.Tags) > 0 {
+		if err := db.AddTags([]int64{article.ID}, result.Tags); err != nil {
+			log.Warnf("Failed to apply rule tags to article %d: %v", article.ID, err)
+		}
+	}
+
+	if result.MarkRead {
+		if _, err := db.SetArticlesStatus([]int64{article.ID}, database.StatusRead); err != nil {
+			log.Warnf("Failed to mark article %d as read: %v", article.ID, err)
+		}
+	}
+}
+
+// decodeRule lê e valida uma regra do corpo da requisição
+func decodeRule(w http.ResponseWriter, r *http.Request) (*database.Rule, bool) {
+	rule := database.Rule{Enabled: true}
+	if err := json.NewDecoder(r.Body).Decode(&rule); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "dados inválidos"})
+		return nil, false
+	}
+
+	if err := rules.Validate(rule); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": fmt.Sprintf("regra inválida: %v", err)})
+		return nil, false
+	}
+
+	if rule.Name == "" {
+		rule.Name = fmt.Sprintf("%s %s %s", rule.Field, rule.Operator, rule.Value)
+	}
+
+	return &rule, true
+}
+
+// getRules retorna todas as regras
+func getRules(w http.ResponseWriter, r *http.Request) {
+	ruleList, err := db.GetRules()
+	if err != nil {
+		log.Errorf("Failed to get rules: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "falha ao buscar regras"})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"rules": ruleList,
+	})
+}
+
+// createRule cria uma nova regra
+func createRule(w http.ResponseWriter, r *http.Request) {
+	rule, ok := decodeRule(w, r)
+	if !ok {
+		return
+	}
+
+	if err := db.CreateRule(rule); err != nil {
+		log.Errorf("Failed to create rule: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "falha ao criar regra"})
+		return
+	}
+
+	log.Infof("Rule created: ID=%d, Name=%s", rule.ID, rule.Name)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rule)
+}
+
+// updateRule substitui uma regra existente
+func updateRule(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "ID inválido"})
+		return
+	}
+
+	rule, ok := decodeRule(w, r)
+	if !ok {
+		return
+	}
+	rule.ID = id
+
+	if err := db.UpdateRule(rule); err != nil {
+		if err.Error() == "rule not found" {
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(map[string]string{"error": "regra não encontrada"})
+			return
+		}
+		log.Errorf("Failed to update rule %d: %v", id, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "falha ao atualizar regra"})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rule)
+}
+
+// deleteRule remove uma regra
+func deleteRule(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "ID inválido"})
+		return
+	}
+
+	if err := db.DeleteRule(id); err != nil {
+		if err.Error() == "rule not found" {
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(map[string]string{"error": "regra não encontrada"})
+			return
+		}
+		log.Errorf("Failed to delete rule %d: %v", id, err)
 		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]string{"error": "falha ao buscar lista de leitura"})
+		json.NewEncoder(w).Encode(map[string]string{"error": "falha ao remover regra"})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"message": "regra removida com sucesso"})
+}
+
+// previewRule lista os artigos já salvos que casariam com a regra enviada, sem alterar nada
+func previewRule(w http.ResponseWriter, r *http.Request) {
+	rule, ok := decodeRule(w, r)
+	if !ok {
+		return
+	}
+	rule.Enabled = true
+
+	engine, err := rules.NewEngine([]database.Rule{*rule})
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": fmt.Sprintf("regra inválida: %v", err)})
+		return
+	}
+
+	articles, _, err := db.GetAllArticles(1, -1, database.ArticleFilter{})
+	if err != nil {
+		log.Errorf("Failed to get articles for rule preview: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "falha ao buscar artigos"})
 		return
 	}
 
+	matched := []database.Article{}
+	for _, article := range articles {
+		if engine.Evaluate(rules.CandidateFromArticle(article)).Matched() {
+			matched = append(matched, article)
+		}
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]interface{}{
-		"articles": articles,
-		"total":    len(articles),
+		"links": matched,
+		"total": len(matched),
 	})
 }
 
-// getImportedIDs retorna os IDs de todos os artigos importados
-func getImportedIDs(w http.ResponseWriter, r *http.Request) {
-	ids, err := nosqlDB.GetImportedIDs()
+// ==================== Export Handlers ====================
+
+// startExport valida o formato e prepara a resposta para download em streaming
+func startExport(w http.ResponseWriter, r *http.Request, name string, opts export.Options) (export.Writer, bool) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = export.FormatJSON
+	}
+
+	if !export.IsValidFormat(format) {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "formato inválido (use csv, json, ndjson, html-bookmarks ou opml)"})
+		return nil, false
+	}
+
+	filename := fmt.Sprintf("%s-%s.%s", name, time.Now().Format("20060102-150405"), export.FileExtension(format))
+	w.Header().Set("Content-Type", export.ContentType(format))
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+
+	writer, err := export.NewWriter(format, w, opts)
+	if err != nil {
+		log.Errorf("Failed to start export: %v", err)
+		return nil, false
+	}
+
+	return writer, true
+}
+
+// exportArticles exporta os artigos extraídos com os mesmos filtros de /api/articles
+func exportArticles(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseArticleFilter(r)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	writer, ok := startExport(w, r, "articles", export.Options{Title: "Gmail Scanner - Artigos"})
+	if !ok {
+		return
+	}
+
+	count := 0
+	err = db.EachArticle(filter, func(article database.Article) error {
+		count++
+		return writer.Write(export.Record{
+			ID:          article.ID,
+			URL:         article.URL,
+			Title:       article.Title,
+			Description: article.Description,
+			Domain:      article.Domain,
+			Newsletter:  article.Newsletter,
+			EmailDate:   article.EmailDate,
+			Folder:      article.Folder,
+			Status:      article.Status,
+			Starred:     article.Starred,
+			ReadAt:      article.ReadAt,
+			Tags:        article.Tags,
+			CreatedAt:   article.CreatedAt,
+		})
+	})
+	if err == nil {
+		err = writer.Close()
+	}
+
+	// O cabeçalho já foi enviado; só resta registrar a falha
+	if err != nil {
+		log.Errorf("Article export failed after %d records: %v", count, err)
+		return
+	}
+	log.Infof("Exported %d articles", count)
+}
+
+// exportReadingList exporta a lista de leitura, incluindo o conteúdo dos artigos
+func exportReadingList(w http.ResponseWriter, r *http.Request) {
+	tags, matchAll, err := parseTagFilter(r)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	writer, ok := startExport(w, r, "reading-list", export.Options{
+		IncludeContent: true,
+		Title:          "Gmail Scanner - Lista de Leitura",
+	})
+	if !ok {
+		return
+	}
+
+	count := 0
+	err = nosqlDB.ForEachImported(func(article nosql.Article) error {
+		if len(tags) > 0 && !article.HasTags(tags, matchAll) {
+			return nil
+		}
+		count++
+		importedAt := article.ImportedAt
+		return writer.Write(export.Record{
+			ID:          article.ID,
+			URL:         article.URL,
+			Title:       article.Title,
+			Description: article.Description,
+			Domain:      article.Domain,
+			Newsletter:  article.Newsletter,
+			EmailDate:   article.EmailDate,
+			Folder:      article.Folder,
+			Tags:        article.Tags,
+			ImportedAt:  &importedAt,
+			Content:     article.Content,
+			ContentType: article.ContentType,
+		})
+	})
+	if err == nil {
+		err = writer.Close()
+	}
+
+	if err != nil {
+		log.Errorf("Reading list export failed after %d records: %v", count, err)
+		return
+	}
+	log.Infof("Exported %d reading list articles", count)
+}
+
+// ==================== Import Handlers ====================
+
+// maxImportSize limita o tamanho do arquivo de importação (50 MB)
+const maxImportSize = 50 << 20
+
+// importArticles importa links de exportações de outras ferramentas
+// (?format=netscape|pocket|instapaper|raindrop). Aceita o arquivo no campo
+// "file" de um formulário multipart ou direto no corpo da requisição.
+func importArticles(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+	if !importer.IsValidFormat(format) {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "formato inválido (use netscape, pocket, instapaper ou raindrop)"})
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportSize)
+
+	var source io.Reader = r.Body
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "arquivo não enviado"})
+			return
+		}
+		defer file.Close()
+		source = file
+	}
+
+	entries, err := importer.Parse(format, source)
+	if err != nil {
+		log.Warnf("Failed to parse %s import: %v", format, err)
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": fmt.Sprintf("arquivo inválido: %v", err)})
+		return
+	}
+
+	report, err := importer.Import(db, format, entries)
+	if err != nil {
+		log.Errorf("Import failed: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "falha ao importar artigos"})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+// ==================== Canonical URL Handlers ====================
+
+// adoptCanonicalURL passa o artigo para a URL canônica da página, fundindo nele
+// os artigos que já usavam essa URL (ou variantes dela)
+func adoptCanonicalURL(articleID int64, currentURL, canonicalURL string) (string, string, bool) {
+	parsedURL, err := url.Parse(canonicalURL)
+	if err != nil || parsedURL.Host == "" {
+		return "", "", false
+	}
+
+	normalized := urlnorm.Normalize(parsedURL)
+	if normalized == currentURL {
+		return "", "", false
+	}
+
+	duplicates, err := db.FindDuplicates(articleID, normalized)
+	if err != nil {
+		log.Warnf("Failed to find duplicates of %s: %v", normalized, err)
+		return "", "", false
+	}
+
+	if len(duplicates) > 0 {
+		if err := db.MergeArticles(articleID, duplicates); err != nil {
+			log.Warnf("Failed to merge duplicates into article %d: %v", articleID, err)
+			return "", "", false
+		}
+		moveReadingListEntries([]database.Merge{{KeepID: articleID, MergedIDs: duplicates}})
+	}
+
+	domain := parsedURL.Hostname()
+	if err := db.UpdateArticleURL(articleID, normalized, domain); err != nil {
+		log.Warnf("Failed to update article %d to canonical URL: %v", articleID, err)
+		return "", "", false
+	}
+
+	log.Infof("Article %d moved to canonical URL %s (merged %d duplicates)", articleID, normalized, len(duplicates))
+	return normalized, domain, true
+}
+
+// moveReadingListEntries leva para o artigo mantido os itens da lista de leitura dos duplicados
+func moveReadingListEntries(merges []database.Merge) {
+	for _, merge := range merges {
+		for _, id := range merge.MergedIDs {
+			if err := nosqlDB.MoveArticle(id, merge.KeepID); err != nil {
+				log.Warnf("Failed to move reading list entry %d to %d: %v", id, merge.KeepID, err)
+			}
+		}
+	}
+}
+
+// MergeRequest representa a fusão manual de artigos duplicados
+type MergeRequest struct {
+	KeepID int64   `json:"keep_id"`
+	IDs    []int64 `json:"ids"`
+}
+
+// mergeArticles funde manualmente artigos duplicados em um só
+func mergeArticles(w http.ResponseWriter, r *http.Request) {
+	var req MergeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.KeepID == 0 || len(req.IDs) == 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "dados inválidos"})
+		return
+	}
+
+	if err := db.MergeArticles(req.KeepID, req.IDs); err != nil {
+		if err.Error() == "article not found" {
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(map[string]string{"error": "artigo não encontrado"})
+			return
+		}
+		log.Errorf("Failed to merge articles: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "falha ao fundir artigos"})
+		return
+	}
+
+	moveReadingListEntries([]database.Merge{{KeepID: req.KeepID, MergedIDs: req.IDs}})
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"keep_id":    req.KeepID,
+		"merged_ids": req.IDs,
+	})
+}
+
+// dedupeArticles funde todos os artigos com a mesma URL canônica
+func dedupeArticles(w http.ResponseWriter, r *http.Request) {
+	merges, err := db.MergeAllDuplicates()
+	if err != nil {
+		log.Errorf("Failed to merge duplicate articles: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "falha ao fundir artigos duplicados"})
+		return
+	}
+
+	moveReadingListEntries(merges)
+
+	if merges == nil {
+		merges = []database.Merge{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"merges": merges,
+	})
+}
+
+// getArticleMentions lista todas as newsletters/emails em que o artigo apareceu
+func getArticleMentions(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "ID inválido"})
+		return
+	}
+
+	mentions, err := db.GetArticleMentions(id)
+	if err != nil {
+		log.Errorf("Failed to get mentions for article %d: %v", id, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "falha ao buscar menções"})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"mentions": mentions,
+		"total":    len(mentions),
+	})
+}
+
+// getArticleThumbnail serve a miniatura local da imagem do artigo
+func getArticleThumbnail(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "ID inválido"})
+		return
+	}
+
+	if thumbnails == nil {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{"error": "cache de miniaturas desativado"})
+		return
+	}
+
+	imageURL, err := db.GetArticleImage(id)
+	if err != nil {
+		if err.Error() == "article not found" {
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(map[string]string{"error": "artigo não encontrado"})
+			return
+		}
+		log.Errorf("Failed to get image of article %d: %v", id, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "falha ao buscar imagem"})
+		return
+	}
+	if imageURL == "" {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{"error": "artigo sem imagem"})
+		return
+	}
+
+	path, err := thumbnails.Path(imageURL)
+	if err != nil {
+		log.Warnf("Failed to get thumbnail for article %d: %v", id, err)
+		w.WriteHeader(http.StatusBadGateway)
+		json.NewEncoder(w).Encode(map[string]string{"error": "falha ao gerar miniatura"})
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/jpeg")
+	w.Header().Set("Cache-Control", "private, max-age=86400")
+	http.ServeFile(w, r, path)
+}
+
+// rankNewsletters ordena as newsletters pelo sinal (menos aproveitadas primeiro,
+// ou ?order=desc para as mais aproveitadas)
+func rankNewsletters(w http.ResponseWriter, r *http.Request) {
+	importedIDs, err := nosqlDB.GetImportedIDs()
 	if err != nil {
 		log.Errorf("Failed to get imported IDs: %v", err)
 		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]string{"error": "falha ao buscar IDs importados"})
+		json.NewEncoder(w).Encode(map[string]string{"error": "falha ao buscar lista de leitura"})
 		return
 	}
 
-	log.Infof("Returning %d imported IDs", len(ids))
+	ranks, err := db.RankNewsletters(importedIDs)
+	if err != nil {
+		log.Errorf("Failed to rank newsletters: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "falha ao classificar newsletters"})
+		return
+	}
+
+	if r.URL.Query().Get("order") == "desc" {
+		for i, j := 0, len(ranks)-1; i < j; i, j = i+1, j-1 {
+			ranks[i], ranks[j] = ranks[j], ranks[i]
+		}
+	}
+
+	type rankedNewsletter struct {
+		database.NewsletterRank
+		Unsubscribe unsubscribe.Options `json:"unsubscribe"`
+	}
+	result := make([]rankedNewsletter, 0, len(ranks))
+	for _, rank := range ranks {
+		result = append(result, rankedNewsletter{
+			NewsletterRank: rank,
+			Unsubscribe:    unsubscribe.Parse(rank.ListUnsubscribe, rank.ListUnsubscribePost),
+		})
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]interface{}{
-		"imported_ids": ids,
+		"newsletters": result,
+		"total":       len(result),
 	})
 }
+
+// unsubscribeNewsletter descadastra a newsletter: executa o POST one-click
+// (RFC 8058) quando anunciado; caso contrário devolve o mailto/URL para o usuário
+func unsubscribeNewsletter(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "ID inválido"})
+		return
+	}
+
+	newsletter, err := db.GetNewsletter(id)
+	if err != nil {
+		writeNewsletterError(w, err, "get")
+		return
+	}
+
+	opts := unsubscribe.Parse(newsletter.ListUnsubscribe, newsletter.ListUnsubscribePost)
+	w.Header().Set("Content-Type", "application/json")
+
+	switch {
+	case opts.OneClick:
+		if err := unsubscribe.New().OneClick(opts.HTTPURL); err != nil {
+			log.Warnf("One-click unsubscribe failed for newsletter %d: %v", id, err)
+			w.WriteHeader(http.StatusBadGateway)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"error":  "falha no descadastro automático",
+				"mailto": opts.Mailto,
+				"url":    opts.HTTPURL,
+			})
+			return
+		}
+
+		if err := db.MarkNewsletterUnsubscribed(id); err != nil {
+			log.Warnf("Failed to mark newsletter %d unsubscribed: %v", id, err)
+		}
+
+		log.Infof("Unsubscribed from newsletter %d (%s) via one-click", id, newsletter.Name)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"method":       "one-click",
+			"unsubscribed": true,
+		})
+
+	case opts.Mailto != "":
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"method":       "mailto",
+			"mailto":       opts.Mailto,
+			"unsubscribed": false,
+		})
+
+	case opts.HTTPURL != "":
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"method":       "url",
+			"url":          opts.HTTPURL,
+			"unsubscribed": false,
+		})
+
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]string{"error": "newsletter não informa como descadastrar"})
+	}
+}
@@ -0,0 +1,302 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gustavoflandal/gmail-scanner/internal/urlnorm"
+)
+
+// Mention registra cada vez que um artigo apareceu em uma newsletter.
+// O artigo guarda só a primeira aparição; as demais ficam aqui.
+type Mention struct {
+	ArticleID  int64  `json:"article_id"`
+	Newsletter string `json:"newsletter"`
+	EmailDate  string `json:"email_date"`
+	Folder     string `json:"folder"`
+	SourceURL  string `json:"source_url,omitempty"`
+	CreatedAt  string `json:"created_at"`
+}
+
+// Merge descreve artigos duplicados absorvidos por outro
+type Merge struct {
+	KeepID    int64   `json:"keep_id"`
+	MergedIDs []int64 `json:"merged_ids"`
+}
+
+// statusRank ordena os estados para a fusão: o mais avançado prevalece
+var statusRank = map[string]int{StatusUnread: 0, StatusRead: 1, StatusArchived: 2}
+
+// createMentionTable cria a tabela de menções e, na primeira vez, a preenche
+// com a aparição já registrada em cada artigo
+func (d *Database) createMentionTable() error {
+	var exists int
+	d.db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'article_mentions'`).Scan(&exists)
+
+	queries := []string{
+		`CREATE TABLE IF NOT EXISTS article_mentions (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			article_id INTEGER NOT NULL,
+			newsletter TEXT NOT NULL DEFAULT '',
+			email_date TEXT NOT NULL DEFAULT '',
+			folder TEXT,
+			source_url TEXT,
+			created_at TEXT DEFAULT (datetime('now')),
+			UNIQUE (article_id, newsletter, email_date)
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_article_mentions_newsletter ON article_mentions(newsletter)`,
+	}
+	for _, query := range queries {
+		if _, err := d.db.Exec(query); err != nil {
+			return fmt.Errorf("failed to create article_mentions table: %w", err)
+		}
+	}
+
+	if exists == 0 {
+		_, err := d.db.Exec(`
+		INSERT OR IGNORE INTO article_mentions (article_id, newsletter, email_date, folder, source_url, created_at)
+		SELECT id, COALESCE(newsletter, ''), COALESCE(email_date, ''), folder, source_url, created_at FROM articles
+		`)
+		if err != nil {
+			return fmt.Errorf("failed to backfill article mentions: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// backfillURLKeys calcula url_key dos artigos gravados antes da coluna existir
+func (d *Database) backfillURLKeys() error {
+	rows, err := d.db.Query(`SELECT id, url FROM articles WHERE url_key IS NULL`)
+	if err != nil {
+		return fmt.Errorf("failed to find articles without url key: %w", err)
+	}
+
+	keys := make(map[int64]string)
+	for rows.Next() {
+		var id int64
+		var articleURL string
+		if err := rows.Scan(&id, &articleURL); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan article url: %w", err)
+		}
+		keys[id] = urlnorm.KeyString(articleURL)
+	}
+	rows.Close()
+
+	for id, key := range keys {
+		if _, err := d.db.Exec(`UPDATE articles SET url_key = ? WHERE id = ?`, key, id); err != nil {
+			return fmt.Errorf("failed to set url key: %w", err)
+		}
+	}
+	return nil
+}
+
+// recordMention registra a aparição do artigo (ignorada se já registrada)
+func (d *Database) recordMention(articleID int64, article *Article) error {
+	_, err := d.db.Exec(`
+	INSERT OR IGNORE INTO article_mentions (article_id, newsletter, email_date, folder, source_url)
+	VALUES (?, ?, ?, ?, NULLIF(?, ''))
+	`, articleID, article.Newsletter, article.EmailDate, article.Folder, article.SourceURL)
+	if err != nil {
+		return fmt.Errorf("failed to record mention: %w", err)
+	}
+	return nil
+}
+
+// findArticleByKey retorna o ID do artigo com a mesma URL ou chave canônica (0 se não houver)
+func (d *Database) findArticleByKey(articleURL, key string) (int64, error) {
+	var id int64
+	err := d.db.QueryRow(`SELECT id FROM articles WHERE url = ? OR url_key = ? ORDER BY id LIMIT 1`, articleURL, key).Scan(&id)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to find article: %w", err)
+	}
+	return id, nil
+}
+
+// GetArticleMentions retorna todas as aparições de um artigo em newsletters
+func (d *Database) GetArticleMentions(articleID int64) ([]Mention, error) {
+	rows, err := d.db.Query(`
+	SELECT article_id, newsletter, email_date, folder, source_url, created_at
+	FROM article_mentions
+	WHERE article_id = ?
+	ORDER BY email_date DESC
+	`, articleID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get mentions: %w", err)
+	}
+	defer rows.Close()
+
+	mentions := []Mention{}
+	for rows.Next() {
+		var mention Mention
+		var folder, sourceURL, createdAt sql.NullString
+		if err := rows.Scan(&mention.ArticleID, &mention.Newsletter, &mention.EmailDate, &folder, &sourceURL, &createdAt); err != nil {
+			return nil, fmt.Errorf("failed to scan mention: %w", err)
+		}
+		mention.Folder = folder.String
+		mention.SourceURL = sourceURL.String
+		mention.CreatedAt = createdAt.String
+		mentions = append(mentions, mention)
+	}
+
+	return mentions, nil
+}
+
+// FindDuplicates retorna os IDs de outros artigos com a mesma chave canônica da URL
+func (d *Database) FindDuplicates(articleID int64, articleURL string) ([]int64, error) {
+	rows, err := d.db.Query(`SELECT id FROM articles WHERE url_key = ? AND id != ? ORDER BY id`,
+		urlnorm.KeyString(articleURL), articleID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find duplicates: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan duplicate: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// MergeArticles funde os duplicados no artigo keepID: menções e tags são
+// movidas, favorito, estado mais avançado e imagem são mantidos e os duplicados removidos
+func (d *Database) MergeArticles(keepID int64, duplicateIDs []int64) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var keepStatus string
+	var keepStarred bool
+	err = tx.QueryRow(`SELECT status, starred FROM articles WHERE id = ?`, keepID).Scan(&keepStatus, &keepStarred)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("article not found")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load article %d: %w", keepID, err)
+	}
+
+	for _, dupID := range duplicateIDs {
+		if dupID == keepID {
+			continue
+		}
+
+		var status string
+		var starred bool
+		var readAt sql.NullString
+		err := tx.QueryRow(`SELECT status, starred, read_at FROM articles WHERE id = ?`, dupID).Scan(&status, &starred, &readAt)
+		if err == sql.ErrNoRows {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to load article %d: %w", dupID, err)
+		}
+
+		statements := []string{
+			`UPDATE OR IGNORE article_mentions SET article_id = ? WHERE article_id = ?`,
+			`UPDATE OR IGNORE article_tags SET article_id = ? WHERE article_id = ?`,
+		}
+		for _, statement := range statements {
+			if _, err := tx.Exec(statement, keepID, dupID); err != nil {
+				return fmt.Errorf("failed to move article data: %w", err)
+			}
+		}
+
+		if statusRank[status] > statusRank[keepStatus] {
+			keepStatus = status
+			if _, err := tx.Exec(`UPDATE articles SET status = ?, read_at = COALESCE(read_at, ?) WHERE id = ?`,
+				status, readAt, keepID); err != nil {
+				return fmt.Errorf("failed to merge status: %w", err)
+			}
+		}
+		if starred && !keepStarred {
+			keepStarred = true
+			if _, err := tx.Exec(`UPDATE articles SET starred = 1 WHERE id = ?`, keepID); err != nil {
+				return fmt.Errorf("failed to merge starred flag: %w", err)
+			}
+		}
+
+		// Imagem do duplicado quando o artigo mantido não tem uma
+		if _, err := tx.Exec(`UPDATE articles SET
+			image_url = (SELECT image_url FROM articles WHERE id = ?),
+			image_alt = (SELECT image_alt FROM articles WHERE id = ?)
+		WHERE id = ? AND (image_url IS NULL OR image_url = '')`, dupID, dupID, keepID); err != nil {
+			return fmt.Errorf("failed to merge image: %w", err)
+		}
+
+		// O que não pôde ser movido (já existia no artigo mantido) é descartado
+		for _, table := range []string{"article_mentions", "article_tags"} {
+			if _, err := tx.Exec(`DELETE FROM `+table+` WHERE article_id = ?`, dupID); err != nil {
+				return fmt.Errorf("failed to clean %s: %w", table, err)
+			}
+		}
+		if _, err := tx.Exec(`DELETE FROM articles WHERE id = ?`, dupID); err != nil {
+			return fmt.Errorf("failed to delete duplicate %d: %w", dupID, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// MergeAllDuplicates funde todos os grupos de artigos com a mesma chave
+// canônica, mantendo o mais antigo de cada grupo
+func (d *Database) MergeAllDuplicates() ([]Merge, error) {
+	rows, err := d.db.Query(`
+	SELECT url_key, GROUP_CONCAT(id) FROM articles
+	WHERE url_key IS NOT NULL
+	GROUP BY url_key
+	HAVING COUNT(*) > 1
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find duplicate groups: %w", err)
+	}
+
+	var groups [][]int64
+	for rows.Next() {
+		var key, idList string
+		if err := rows.Scan(&key, &idList); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan duplicate group: %w", err)
+		}
+		var ids []int64
+		for _, idStr := range strings.Split(idList, ",") {
+			if id, err := strconv.ParseInt(idStr, 10, 64); err == nil {
+				ids = append(ids, id)
+			}
+		}
+		sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+		groups = append(groups, ids)
+	}
+	rows.Close()
+
+	var merges []Merge
+	for _, ids := range groups {
+		if err := d.MergeArticles(ids[0], ids[1:]); err != nil {
+			return merges, err
+		}
+		merges = append(merges, Merge{KeepID: ids[0], MergedIDs: ids[1:]})
+	}
+	return merges, nil
+}
+
+// UpdateArticleURL troca a URL do artigo (ex.: pela URL canônica da página)
+func (d *Database) UpdateArticleURL(articleID int64, articleURL, domain string) error {
+	_, err := d.db.Exec(`UPDATE articles SET url = ?, url_key = ?, domain = ? WHERE id = ?`,
+		articleURL, urlnorm.KeyString(articleURL), domain, articleID)
+	if err != nil {
+		return fmt.Errorf("failed to update article url: %w", err)
+	}
+	return nil
+}
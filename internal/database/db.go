@@ -3,21 +3,64 @@ package database
 import (
 	"database/sql"
 	"fmt"
+	"sort"
+	"strings"
 
+	"github.com/gustavoflandal/gmail-scanner/internal/urlnorm"
 	_ "modernc.org/sqlite"
 )
 
+// Estados possíveis de um artigo
+const (
+	StatusUnread   = "unread"
+	StatusRead     = "read"
+	StatusArchived = "archived"
+)
+
+// IsValidStatus verifica se o status informado é suportado
+func IsValidStatus(status string) bool {
+	switch status {
+	case StatusUnread, StatusRead, StatusArchived:
+		return true
+	}
+	return false
+}
+
 // Article representa um artigo/link extraído de uma newsletter
 type Article struct {
-	ID          int64  `json:"id"`
-	URL         string `json:"url"`
-	Title       string `json:"title"`
-	Description string `json:"description"`
-	Domain      string `json:"domain"`
-	Newsletter  string `json:"newsletter"` // Nome da newsletter (From do email)
-	EmailDate   string `json:"email_date"` // Data do email
-	Folder      string `json:"folder"`     // Pasta IMAP de origem
-	CreatedAt   string `json:"created_at"`
+	ID           int64    `json:"id"`
+	URL          string   `json:"url"`
+	Title        string   `json:"title"`
+	Description  string   `json:"description"`
+	Domain       string   `json:"domain"`
+	Newsletter   string   `json:"newsletter"`              // Nome de exibição da newsletter
+	NewsletterID int64    `json:"newsletter_id,omitempty"` // Newsletter em newsletters (0 = sem newsletter, ex.: importados)
+	EmailDate    string   `json:"email_date"`              // Data do email
+	Folder       string   `json:"folder"`                  // Pasta IMAP de origem
+	Status       string   `json:"status"`                  // unread, read ou archived
+	Starred      bool     `json:"starred"`
+	ReadAt       string   `json:"read_at,omitempty"`
+	SourceURL    string   `json:"source_url,omitempty"` // Link de rastreamento original, quando a URL foi resolvida
+	Score        float64  `json:"score"`                // 0 a 1: quanto o link parece um artigo
+	Class        string   `json:"class,omitempty"`      // article, sponsor, job, social, footer ou product
+	ImageURL     string   `json:"image_url,omitempty"`  // Imagem que a newsletter mostrava junto do link
+	ImageAlt     string   `json:"image_alt,omitempty"`
+	Tags         []string `json:"tags"`
+	CreatedAt    string   `json:"created_at"`
+}
+
+// ArticleFilter agrupa os filtros aceitos na listagem de artigos
+type ArticleFilter struct {
+	Domain       string
+	Search       string
+	Newsletter   string
+	NewsletterID int64
+	Statuses     []string // Vazio = todos os estados
+	Starred      *bool    // nil = não filtra
+	Tags         []string // Vazio = não filtra
+	TagsAll      bool     // true = artigo precisa ter todas as tags (AND)
+	Class        string   // Classe do link (article, sponsor...)
+	MinScore     float64  // 0 = não filtra
 }
 
 type Database struct {
@@ -57,6 +100,15 @@ func (d *Database) CreateTable() error {
 		newsletter TEXT,
 		email_date TEXT,
 		folder TEXT,
+		status TEXT NOT NULL DEFAULT 'unread',
+		starred INTEGER NOT NULL DEFAULT 0,
+		read_at TEXT,
+		source_url TEXT,
+		url_key TEXT,
+		score REAL,
+		link_class TEXT,
+		image_url TEXT,
+		image_alt TEXT,
 		created_at TEXT DEFAULT (datetime('now'))
 	)
 	`
@@ -66,12 +118,33 @@ func (d *Database) CreateTable() error {
 		return fmt.Errorf("failed to create articles table: %w", err)
 	}
 
+	// Bancos criados por versões anteriores não têm as colunas de estado
+	columns := []struct{ name, definition string }{
+		{"status", "TEXT NOT NULL DEFAULT 'unread'"},
+		{"starred", "INTEGER NOT NULL DEFAULT 0"},
+		{"read_at", "TEXT"},
+		{"source_url", "TEXT"},
+		{"url_key", "TEXT"},
+		{"score", "REAL"},
+		{"link_class", "TEXT"},
+		{"image_url", "TEXT"},
+		{"image_alt", "TEXT"},
+	}
+	for _, col := range columns {
+		if err := d.addColumnIfMissing("articles", col.name, col.definition); err != nil {
+			return err
+		}
+	}
+
 	// Create indexes
 	indexes := []string{
 		`CREATE INDEX IF NOT EXISTS idx_articles_domain ON articles(domain)`,
 		`CREATE INDEX IF NOT EXISTS idx_articles_newsletter ON articles(newsletter)`,
 		`CREATE INDEX IF NOT EXISTS idx_articles_email_date ON articles(email_date)`,
 		`CREATE UNIQUE INDEX IF NOT EXISTS idx_articles_url ON articles(url)`,
+		`CREATE INDEX IF NOT EXISTS idx_articles_status ON articles(status)`,
+		`CREATE INDEX IF NOT EXISTS idx_articles_starred ON articles(starred)`,
+		`CREATE INDEX IF NOT EXISTS idx_articles_url_key ON articles(url_key)`,
 	}
 
 	for _, idx := range indexes {
@@ -80,24 +153,122 @@ func (d *Database) CreateTable() error {
 		}
 	}
 
+	if err := d.createTagTables(); err != nil {
+		return err
+	}
+
+	if err := d.createRuleTable(); err != nil {
+		return err
+	}
+
+	if err := d.createRedirectTable(); err != nil {
+		return err
+	}
+
+	if err := d.createMentionTable(); err != nil {
+		return err
+	}
+
+	if err := d.createNewsletterTables(); err != nil {
+		return err
+	}
+
+	return d.backfillURLKeys()
+}
+
+// addColumnIfMissing adiciona uma coluna à tabela caso ela ainda não exista
+func (d *Database) addColumnIfMissing(table, column, definition string) error {
+	rows, err := d.db.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		return fmt.Errorf("failed to inspect table %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var cid, notNull, pk int
+		var name, colType string
+		var defaultValue sql.NullString
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &defaultValue, &pk); err != nil {
+			return fmt.Errorf("failed to scan column info: %w", err)
+		}
+		if name == column {
+			return nil
+		}
+	}
+	rows.Close()
+
+	alter := fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, definition)
+	if _, err := d.db.Exec(alter); err != nil {
+		return fmt.Errorf("failed to add column %s.%s: %w", table, column, err)
+	}
 	return nil
 }
 
-// IndexArticle salva um artigo no banco (ignora se URL já existe)
+// IndexArticle salva um artigo no banco (ignora se a URL, ou sua forma
+// canônica, já existe). Quando o artigo é novo, article.ID é preenchido;
+// se já existia, fica 0. Em ambos os casos a aparição é registrada em article_mentions.
 func (d *Database) IndexArticle(article *Article) error {
+	key := urlnorm.KeyString(article.URL)
+
+	existingID, err := d.findArticleByKey(article.URL, key)
+	if err != nil {
+		return fmt.Errorf("failed to index article: %w", err)
+	}
+
+	article.ID = 0
+	if existingID != 0 {
+		if err := d.fillArticleImage(existingID, article); err != nil {
+			return err
+		}
+		return d.recordMention(existingID, article)
+	}
+
 	query := `
-	INSERT OR IGNORE INTO articles (url, title, description, domain, newsletter, email_date, folder, created_at)
-	VALUES (?, ?, ?, ?, ?, ?, ?, datetime('now'))
+	INSERT OR IGNORE INTO articles (url, title, description, domain, newsletter, newsletter_id, email_date, folder, source_url, url_key, score, link_class, image_url, image_alt, created_at)
+	VALUES (?, ?, ?, ?, ?, NULLIF(?, 0), ?, ?, NULLIF(?, ''), ?, ?, NULLIF(?, ''), NULLIF(?, ''), NULLIF(?, ''), datetime('now'))
 	`
 
-	_, err := d.db.Exec(query, article.URL, article.Title, article.Description, article.Domain, article.Newsletter, article.EmailDate, article.Folder)
+	result, err := d.db.Exec(query, article.URL, article.Title, article.Description, article.Domain, article.Newsletter, article.NewsletterID,
+		article.EmailDate, article.Folder, article.SourceURL, key, article.Score, article.Class, article.ImageURL, article.ImageAlt)
 	if err != nil {
 		return fmt.Errorf("failed to index article: %w", err)
 	}
 
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected > 0 {
+		article.ID, _ = result.LastInsertId()
+		return d.recordMention(article.ID, article)
+	}
+
 	return nil
 }
 
+// fillArticleImage guarda a imagem no artigo existente se ele ainda não tiver uma
+// (outra newsletter pode ter mostrado o mesmo link com imagem)
+func (d *Database) fillArticleImage(articleID int64, article *Article) error {
+	if article.ImageURL == "" {
+		return nil
+	}
+	_, err := d.db.Exec(`UPDATE articles SET image_url = ?, image_alt = NULLIF(?, '')
+	WHERE id = ? AND (image_url IS NULL OR image_url = '')`, article.ImageURL, article.ImageAlt, articleID)
+	if err != nil {
+		return fmt.Errorf("failed to update article image: %w", err)
+	}
+	return nil
+}
+
+// GetArticleImage retorna a URL da imagem do artigo (vazia se não houver)
+func (d *Database) GetArticleImage(articleID int64) (string, error) {
+	var imageURL sql.NullString
+	err := d.db.QueryRow(`SELECT image_url FROM articles WHERE id = ?`, articleID).Scan(&imageURL)
+	if err == sql.ErrNoRows {
+		return "", fmt.Errorf("article not found")
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get article image: %w", err)
+	}
+	return imageURL.String, nil
+}
+
 // IndexArticles salva múltiplos artigos
 func (d *Database) IndexArticles(articles []Article) error {
 	for _, article := range articles {
@@ -109,54 +280,21 @@ func (d *Database) IndexArticles(articles []Article) error {
 }
 
 // GetAllArticles retorna todos os artigos com paginação e filtros
-func (d *Database) GetAllArticles(page, pageSize int, domain, search, newsletter string) ([]Article, int, error) {
+func (d *Database) GetAllArticles(page, pageSize int, filter ArticleFilter) ([]Article, int, error) {
 	offset := (page - 1) * pageSize
 
-	countQuery := `SELECT COUNT(*) FROM articles WHERE 1=1`
-	selectQuery := `
-	SELECT id, url, title, description, domain, newsletter, email_date, folder, created_at
-	FROM articles
-	WHERE 1=1
-	`
-
-	args := []interface{}{}
-	countArgs := []interface{}{}
-
-	// Filtro de domínio
-	if domain != "" {
-		countQuery += " AND domain = ?"
-		selectQuery += " AND domain = ?"
-		args = append(args, domain)
-		countArgs = append(countArgs, domain)
-	}
-
-	// Filtro de newsletter
-	if newsletter != "" {
-		countQuery += " AND newsletter LIKE ?"
-		selectQuery += " AND newsletter LIKE ?"
-		searchNewsletter := "%" + newsletter + "%"
-		args = append(args, searchNewsletter)
-		countArgs = append(countArgs, searchNewsletter)
-	}
-
-	// Filtro de busca
-	if search != "" {
-		searchTerm := "%" + search + "%"
-		countQuery += " AND (title LIKE ? OR description LIKE ? OR url LIKE ? OR newsletter LIKE ?)"
-		selectQuery += " AND (title LIKE ? OR description LIKE ? OR url LIKE ? OR newsletter LIKE ?)"
-		args = append(args, searchTerm, searchTerm, searchTerm, searchTerm)
-		countArgs = append(countArgs, searchTerm, searchTerm, searchTerm, searchTerm)
-	}
+	where, args := filter.whereClause()
 
 	// Contar total
 	var total int
-	err := d.db.QueryRow(countQuery, countArgs...).Scan(&total)
+	err := d.db.QueryRow(`SELECT COUNT(*) FROM articles WHERE 1=1`+where, args...).Scan(&total)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to count articles: %w", err)
 	}
 
 	// Buscar resultados paginados
-	selectQuery += " ORDER BY email_date DESC, created_at DESC LIMIT ? OFFSET ?"
+	selectQuery := `SELECT ` + articleColumns + ` FROM articles WHERE 1=1` + where +
+		` ORDER BY email_date DESC, created_at DESC LIMIT ? OFFSET ?`
 	args = append(args, pageSize, offset)
 
 	rows, err := d.db.Query(selectQuery, args...)
@@ -167,23 +305,145 @@ func (d *Database) GetAllArticles(page, pageSize int, domain, search, newsletter
 
 	var articles []Article
 	for rows.Next() {
-		var article Article
-		var emailDate, createdAt sql.NullString
-		err := rows.Scan(&article.ID, &article.URL, &article.Title, &article.Description,
-			&article.Domain, &article.Newsletter, &emailDate, &article.Folder, &createdAt)
+		article, err := scanArticle(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		articles = append(articles, *article)
+	}
+
+	if err := d.attachTags(articles); err != nil {
+		return nil, 0, err
+	}
+
+	return articles, total, nil
+}
+
+// EachArticle percorre todos os artigos que casam com o filtro, um por vez,
+// sem carregar o resultado inteiro em memória. As tags já vêm preenchidas.
+func (d *Database) EachArticle(filter ArticleFilter, fn func(Article) error) error {
+	where, args := filter.whereClause()
+
+	query := `SELECT ` + articleColumns + `,
+		(SELECT GROUP_CONCAT(t.name, ',') FROM article_tags at JOIN tags t ON t.id = at.tag_id
+		 WHERE at.article_id = articles.id) AS tag_list
+	FROM articles WHERE 1=1` + where + ` ORDER BY email_date DESC, created_at DESC`
+
+	rows, err := d.db.Query(query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to get articles: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var tagList sql.NullString
+		article, err := scanArticle(rows, &tagList)
 		if err != nil {
-			return nil, 0, fmt.Errorf("failed to scan article: %w", err)
+			return err
 		}
-		if emailDate.Valid {
-			article.EmailDate = emailDate.String
+		article.Tags = splitTags(tagList.String)
+		sort.Strings(article.Tags) // Mesma ordem de GetArticleTags
+
+		if err := fn(*article); err != nil {
+			return err
 		}
-		if createdAt.Valid {
-			article.CreatedAt = createdAt.String
+	}
+
+	return rows.Err()
+}
+
+// articleColumns lista as colunas lidas por scanArticle, na mesma ordem
+const articleColumns = `id, url, title, description, domain, newsletter, newsletter_id, email_date, folder, status, starred, read_at, source_url, score, link_class, image_url, image_alt, created_at`
+
+// scanArticle lê uma linha com as colunas de articleColumns; extra recebe as
+// colunas selecionadas depois delas (ex.: tag_list em EachArticle)
+func scanArticle(rows *sql.Rows, extra ...interface{}) (*Article, error) {
+	var article Article
+	var emailDate, readAt, sourceURL, linkClass, imageURL, imageAlt, createdAt sql.NullString
+	var newsletterID sql.NullInt64
+	var score sql.NullFloat64
+	dest := []interface{}{&article.ID, &article.URL, &article.Title, &article.Description,
+		&article.Domain, &article.Newsletter, &newsletterID, &emailDate, &article.Folder,
+		&article.Status, &article.Starred, &readAt, &sourceURL, &score, &linkClass, &imageURL, &imageAlt, &createdAt}
+	err := rows.Scan(append(dest, extra...)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan article: %w", err)
+	}
+	article.NewsletterID = newsletterID.Int64
+	article.Score = score.Float64
+	article.Class = linkClass.String
+	article.ImageURL = imageURL.String
+	article.ImageAlt = imageAlt.String
+	article.EmailDate = emailDate.String
+	article.ReadAt = readAt.String
+	article.SourceURL = sourceURL.String
+	article.CreatedAt = createdAt.String
+	return &article, nil
+}
+
+// whereClause monta as condições SQL (prefixadas com AND) e seus argumentos
+func (f ArticleFilter) whereClause() (string, []interface{}) {
+	var where strings.Builder
+	args := []interface{}{}
+
+	// Filtro de domínio
+	if f.Domain != "" {
+		where.WriteString(" AND domain = ?")
+		args = append(args, f.Domain)
+	}
+
+	// Filtro de newsletter
+	if f.Newsletter != "" {
+		where.WriteString(" AND newsletter LIKE ?")
+		args = append(args, "%"+f.Newsletter+"%")
+	}
+	if f.NewsletterID != 0 {
+		where.WriteString(" AND newsletter_id = ?")
+		args = append(args, f.NewsletterID)
+	}
+
+	// Filtros de classificação do link
+	if f.Class != "" {
+		where.WriteString(" AND link_class = ?")
+		args = append(args, f.Class)
+	}
+	if f.MinScore > 0 {
+		// Artigos anteriores à classificação não têm score e continuam visíveis
+		where.WriteString(" AND (score IS NULL OR score >= ?)")
+		args = append(args, f.MinScore)
+	}
+
+	// Filtro de busca
+	if f.Search != "" {
+		searchTerm := "%" + f.Search + "%"
+		where.WriteString(" AND (title LIKE ? OR description LIKE ? OR url LIKE ? OR newsletter LIKE ?)")
+		args = append(args, searchTerm, searchTerm, searchTerm, searchTerm)
+	}
+
+	// Filtro de estado
+	if len(f.Statuses) > 0 {
+		placeholders := make([]string, len(f.Statuses))
+		for i, status := range f.Statuses {
+			placeholders[i] = "?"
+			args = append(args, status)
 		}
-		articles = append(articles, article)
+		where.WriteString(" AND status IN (" + strings.Join(placeholders, ", ") + ")")
 	}
 
-	return articles, total, nil
+	// Filtro de favoritos
+	if f.Starred != nil {
+		where.WriteString(" AND starred = ?")
+		args = append(args, *f.Starred)
+	}
+
+	// Filtro de tags
+	if len(f.Tags) > 0 {
+		condition, tagArgs := tagCondition(f.Tags, f.TagsAll)
+		where.WriteString(condition)
+		args = append(args, tagArgs...)
+	}
+
+	return where.String(), args
 }
 
 // GetStats retorna estatísticas gerais
@@ -239,12 +499,34 @@ func (d *Database) GetArticleStats() (map[string]interface{}, error) {
 
 	// Newsletters únicas
 	var totalNewsletters int
-	d.db.QueryRow(`SELECT COUNT(DISTINCT newsletter) FROM articles`).Scan(&totalNewsletters)
+	d.db.QueryRow(`SELECT COUNT(*) FROM newsletters`).Scan(&totalNewsletters)
+
+	// Artigos por estado
+	byStatus := map[string]int{StatusUnread: 0, StatusRead: 0, StatusArchived: 0}
+	statusRows, err := d.db.Query(`SELECT status, COUNT(*) FROM articles GROUP BY status`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get status stats: %w", err)
+	}
+	defer statusRows.Close()
+
+	for statusRows.Next() {
+		var status string
+		var count int
+		if err := statusRows.Scan(&status, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan status: %w", err)
+		}
+		byStatus[status] = count
+	}
+
+	var totalStarred int
+	d.db.QueryRow(`SELECT COUNT(*) FROM articles WHERE starred = 1`).Scan(&totalStarred)
 
 	stats := map[string]interface{}{
 		"total_links":       totalArticles, // Compatibilidade com frontend
 		"total_newsletters": totalNewsletters,
+		"total_starred":     totalStarred,
 		"by_domain":         byDomain,
+		"by_status":         byStatus,
 	}
 
 	return stats, nil
@@ -261,34 +543,69 @@ func (d *Database) DeleteArticle(articleID int64) error {
 	if rowsAffected == 0 {
 		return fmt.Errorf("article not found")
 	}
+
+	for _, table := range []string{"article_tags", "article_mentions"} {
+		if _, err := d.db.Exec(`DELETE FROM `+table+` WHERE article_id = ?`, articleID); err != nil {
+			return fmt.Errorf("failed to delete from %s: %w", table, err)
+		}
+	}
 	return nil
 }
 
-// GetNewsletters retorna lista de newsletters únicas
-func (d *Database) GetNewsletters() ([]string, error) {
-	query := `
-	SELECT DISTINCT newsletter 
-	FROM articles 
-	WHERE newsletter != '' 
-	ORDER BY newsletter
-	`
+// SetArticlesStatus altera o estado de vários artigos de uma vez.
+// read_at é preenchido na primeira leitura e limpo ao voltar para unread.
+func (d *Database) SetArticlesStatus(ids []int64, status string) (int64, error) {
+	if !IsValidStatus(status) {
+		return 0, fmt.Errorf("invalid status: %s", status)
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	readAt := `COALESCE(read_at, datetime('now'))`
+	if status == StatusUnread {
+		readAt = `NULL`
+	}
 
-	rows, err := d.db.Query(query)
+	in, args := int64Placeholders(ids)
+	query := `UPDATE articles SET status = ?, read_at = ` + readAt + ` WHERE id IN (` + in + `)`
+	args = append([]interface{}{status}, args...)
+
+	result, err := d.db.Exec(query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get newsletters: %w", err)
+		return 0, fmt.Errorf("failed to update article status: %w", err)
 	}
-	defer rows.Close()
+	rowsAffected, _ := result.RowsAffected()
+	return rowsAffected, nil
+}
 
-	var newsletters []string
-	for rows.Next() {
-		var newsletter string
-		if err := rows.Scan(&newsletter); err != nil {
-			return nil, fmt.Errorf("failed to scan newsletter: %w", err)
-		}
-		newsletters = append(newsletters, newsletter)
+// SetArticlesStarred marca ou desmarca vários artigos como favoritos
+func (d *Database) SetArticlesStarred(ids []int64, starred bool) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
 	}
 
-	return newsletters, nil
+	in, args := int64Placeholders(ids)
+	query := `UPDATE articles SET starred = ? WHERE id IN (` + in + `)`
+	args = append([]interface{}{starred}, args...)
+
+	result, err := d.db.Exec(query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to update article starred flag: %w", err)
+	}
+	rowsAffected, _ := result.RowsAffected()
+	return rowsAffected, nil
+}
+
+// int64Placeholders gera "?, ?, ..." e os argumentos correspondentes
+func int64Placeholders(ids []int64) (string, []interface{}) {
+	placeholders := make([]string, len(ids))
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		placeholders[i] = "?"
+		args[i] = id
+	}
+	return strings.Join(placeholders, ", "), args
 }
 
 func (d *Database) Close() error {
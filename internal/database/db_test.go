@@ -0,0 +1,97 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// openTestDB abre (ou cria) o banco no caminho, fechando-o no fim do teste
+func openTestDB(t *testing.T, path string) *Database {
+	t.Helper()
+	db, err := NewDatabase(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+// newTestDB cria um banco vazio num diretório temporário
+func newTestDB(t *testing.T) *Database {
+	t.Helper()
+	return openTestDB(t, filepath.Join(t.TempDir(), "articles.db"))
+}
+
+// legacyTestDB cria um banco no formato anterior às newsletters: só a tabela de
+// artigos, com o From completo no campo newsletter
+func legacyTestDB(t *testing.T, articles map[string]string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "legacy.db")
+	raw, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer raw.Close()
+
+	if _, err := raw.Exec(`CREATE TABLE articles (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		url TEXT NOT NULL, title TEXT, description TEXT, domain TEXT,
+		newsletter TEXT, email_date TEXT, folder TEXT,
+		created_at TEXT DEFAULT (datetime('now'))
+	)`); err != nil {
+		t.Fatal(err)
+	}
+	for url, from := range articles {
+		if _, err := raw.Exec(`INSERT INTO articles (url, title, domain, newsletter) VALUES (?, ?, 'example.com', ?)`, url, url, from); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return path
+}
+
+func (d *Database) newsletterIDOf(t *testing.T, url string) int64 {
+	t.Helper()
+	var id sql.NullInt64
+	if err := d.db.QueryRow(`SELECT newsletter_id FROM articles WHERE url = ?`, url).Scan(&id); err != nil {
+		t.Fatal(err)
+	}
+	return id.Int64
+}
+
+func TestEachArticleMatchesGetAllArticles(t *testing.T) {
+	db := newTestDB(t)
+	for i, tags := range [][]string{{"go", "db"}, nil, {"rust"}} {
+		article := Article{
+			URL: fmt.Sprintf("https://example.com/%d", i), Title: fmt.Sprintf("Article %d", i), Domain: "example.com",
+			EmailDate: fmt.Sprintf("2026-10-1%dT09:00:00-03:00", i), Score: 0.5, Class: "article",
+			ImageURL: "https://example.com/a.png", SourceURL: "https://t.co/x",
+		}
+		if err := db.IndexArticle(&article); err != nil {
+			t.Fatal(err)
+		}
+		if tags != nil {
+			if err := db.AddTags([]int64{article.ID}, tags); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	want, _, err := db.GetAllArticles(1, -1, ArticleFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []Article
+	if err := db.EachArticle(ArticleFilter{}, func(article Article) error {
+		got = append(got, article)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(got) != 3 || !reflect.DeepEqual(got, want) {
+		t.Errorf("EachArticle =\n%+v\nGetAllArticles =\n%+v", got, want)
+	}
+}
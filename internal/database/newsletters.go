@@ -0,0 +1,669 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"net/mail"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Newsletter representa uma newsletter identificada pelo List-Id ou, na falta
+// dele, pelo endereço do remetente. Um mesmo registro pode ter várias chaves
+// (ex.: endereços alternativos) depois de uma fusão.
+type Newsletter struct {
+	ID         int64           `json:"id"`
+	Name       string          `json:"name"`
+	ListID     string          `json:"list_id,omitempty"`
+	Sender     string          `json:"sender,omitempty"`
+	Domain     string          `json:"domain"`
+	IconURL    string          `json:"icon_url,omitempty"`
+	Muted      bool            `json:"muted"` // Emails de newsletters silenciadas são ignorados na varredura
+	Keys       []string        `json:"keys"`
+	Stats      NewsletterStats `json:"stats"`
+	CreatedAt  string          `json:"created_at"`
+	LastSeenAt string          `json:"last_seen_at,omitempty"`
+
+	// Cabeçalhos de descadastro do email mais recente
+	ListUnsubscribe     string `json:"list_unsubscribe,omitempty"`
+	ListUnsubscribePost string `json:"list_unsubscribe_post,omitempty"`
+	UnsubscribedAt      string `json:"unsubscribed_at,omitempty"`
+}
+
+// NewsletterStats resume os artigos vindos de uma newsletter
+type NewsletterStats struct {
+	Articles   int    `json:"articles"`
+	Unread     int    `json:"unread"`
+	Read       int    `json:"read"`
+	Archived   int    `json:"archived"`
+	Starred    int    `json:"starred"`
+	FirstEmail string `json:"first_email,omitempty"`
+	LastEmail  string `json:"last_email,omitempty"`
+	LastRead   string `json:"last_read,omitempty"`
+}
+
+// newsletterIdentity descreve quem enviou um email
+type newsletterIdentity struct {
+	key    string
+	listID string
+	sender string
+	name   string
+	domain string
+}
+
+// identifyNewsletter deriva a chave da newsletter do cabeçalho List-Id
+// ("Nome <id.exemplo.com>") ou, na falta dele, do From ("Nome <user@host>")
+func identifyNewsletter(from, listID string) (newsletterIdentity, bool) {
+	var identity newsletterIdentity
+
+	if addr, err := mail.ParseAddress(from); err == nil {
+		identity.sender = strings.ToLower(addr.Address)
+		identity.name = strings.TrimSpace(addr.Name)
+	} else if start, end := strings.LastIndex(from, "<"), strings.LastIndex(from, ">"); start >= 0 && end > start {
+		identity.sender = strings.ToLower(strings.TrimSpace(from[start+1 : end]))
+		identity.name = strings.Trim(strings.TrimSpace(from[:start]), `"`)
+	} else if strings.Contains(from, "@") {
+		identity.sender = strings.ToLower(strings.TrimSpace(from))
+	}
+	if !strings.Contains(identity.sender, "@") {
+		identity.sender = ""
+	}
+
+	listName := ""
+	listID = strings.TrimSpace(listID)
+	if start, end := strings.LastIndex(listID, "<"), strings.LastIndex(listID, ">"); start >= 0 && end > start {
+		listName = strings.Trim(strings.TrimSpace(listID[:start]), `"`)
+		listID = listID[start+1 : end]
+	}
+	identity.listID = strings.ToLower(strings.TrimSpace(listID))
+
+	switch {
+	case identity.listID != "":
+		identity.key = identity.listID
+	case identity.sender != "":
+		identity.key = identity.sender
+	default:
+		return identity, false
+	}
+
+	if identity.sender != "" {
+		identity.domain = identity.sender[strings.LastIndex(identity.sender, "@")+1:]
+	} else {
+		identity.domain = identity.listID
+	}
+
+	if identity.name == "" {
+		identity.name = listName
+	}
+	if identity.name == "" {
+		identity.name = identity.key
+	}
+
+	return identity, true
+}
+
+// createNewsletterTables cria as tabelas de newsletters e suas chaves e, na
+// primeira vez, associa os artigos já existentes
+func (d *Database) createNewsletterTables() error {
+	var exists int
+	d.db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'newsletters'`).Scan(&exists)
+
+	queries := []string{
+		`CREATE TABLE IF NOT EXISTS newsletters (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL,
+			list_id TEXT,
+			sender TEXT,
+			domain TEXT NOT NULL DEFAULT '',
+			icon_url TEXT,
+			muted INTEGER NOT NULL DEFAULT 0,
+			created_at TEXT DEFAULT (datetime('now')),
+			last_seen_at TEXT
+		)`,
+		`CREATE TABLE IF NOT EXISTS newsletter_keys (
+			key TEXT PRIMARY KEY,
+			newsletter_id INTEGER NOT NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_newsletter_keys_newsletter ON newsletter_keys(newsletter_id)`,
+	}
+
+	for _, query := range queries {
+		if _, err := d.db.Exec(query); err != nil {
+			return fmt.Errorf("failed to create newsletter tables: %w", err)
+		}
+	}
+
+	columns := []struct{ table, name, definition string }{
+		{"articles", "newsletter_id", "INTEGER"},
+		{"newsletters", "list_unsubscribe", "TEXT"},
+		{"newsletters", "list_unsubscribe_post", "TEXT"},
+		{"newsletters", "unsubscribed_at", "TEXT"},
+	}
+	for _, col := range columns {
+		if err := d.addColumnIfMissing(col.table, col.name, col.definition); err != nil {
+			return err
+		}
+	}
+	if _, err := d.db.Exec(`CREATE INDEX IF NOT EXISTS idx_articles_newsletter_id ON articles(newsletter_id)`); err != nil {
+		return fmt.Errorf("failed to create index: %w", err)
+	}
+
+	// Só na criação da tabela: depois disso, artigos sem newsletter_id são de
+	// newsletters excluídas pelo usuário, que não devem ser recriadas
+	if exists == 0 {
+		return d.backfillNewsletters()
+	}
+	return nil
+}
+
+// backfillNewsletters cria as newsletters dos artigos gravados antes da tabela
+// existir (o campo newsletter guardava o From completo) e os associa a elas
+func (d *Database) backfillNewsletters() error {
+	rows, err := d.db.Query(`SELECT DISTINCT newsletter FROM articles WHERE newsletter_id IS NULL AND newsletter != ''`)
+	if err != nil {
+		return fmt.Errorf("failed to find articles without newsletter: %w", err)
+	}
+
+	var senders []string
+	for rows.Next() {
+		var from string
+		if err := rows.Scan(&from); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan newsletter: %w", err)
+		}
+		senders = append(senders, from)
+	}
+	rows.Close()
+
+	for _, from := range senders {
+		// Artigos importados de arquivos ("import:pocket") não vêm de newsletters
+		if _, ok := identifyNewsletter(from, ""); !ok {
+			continue
+		}
+
+		newsletter, err := d.ResolveNewsletter(from, "")
+		if err != nil {
+			return err
+		}
+
+		_, err = d.db.Exec(`UPDATE articles SET newsletter_id = ?, newsletter = ? WHERE newsletter = ? AND newsletter_id IS NULL`,
+			newsletter.ID, newsletter.Name, from)
+		if err != nil {
+			return fmt.Errorf("failed to link articles to newsletter: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// ResolveNewsletter retorna a newsletter do email, criando-a na primeira vez
+// que o List-Id/remetente aparece. Nome, ícone e mute editados pelo usuário
+// não são sobrescritos.
+func (d *Database) ResolveNewsletter(from, listID string) (*Newsletter, error) {
+	identity, ok := identifyNewsletter(from, listID)
+	if !ok {
+		return nil, fmt.Errorf("newsletter identity not found")
+	}
+
+	tx, err := d.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var id int64
+	err = tx.QueryRow(`SELECT newsletter_id FROM newsletter_keys WHERE key = ?`, identity.key).Scan(&id)
+	if err == sql.ErrNoRows && identity.listID != "" && identity.sender != "" {
+		// Newsletter conhecida só pelo remetente (ex.: criada pelo backfill, que não
+		// tem o List-Id): o List-Id vira mais uma chave dela em vez de uma nova
+		err = tx.QueryRow(`
+		SELECT k.newsletter_id FROM newsletter_keys k
+		JOIN newsletters n ON n.id = k.newsletter_id
+		WHERE k.key = ? AND n.list_id IS NULL
+		`, identity.sender).Scan(&id)
+		if err == nil {
+			if _, err = tx.Exec(`INSERT OR IGNORE INTO newsletter_keys (key, newsletter_id) VALUES (?, ?)`, identity.key, id); err != nil {
+				return nil, fmt.Errorf("failed to save newsletter key: %w", err)
+			}
+		}
+	}
+
+	switch {
+	case err == sql.ErrNoRows:
+		result, err := tx.Exec(`
+		INSERT INTO newsletters (name, list_id, sender, domain, last_seen_at)
+		VALUES (?, NULLIF(?, ''), NULLIF(?, ''), ?, datetime('now'))
+		`, identity.name, identity.listID, identity.sender, identity.domain)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create newsletter: %w", err)
+		}
+		id, _ = result.LastInsertId()
+
+		if _, err := tx.Exec(`INSERT OR IGNORE INTO newsletter_keys (key, newsletter_id) VALUES (?, ?)`, identity.key, id); err != nil {
+			return nil, fmt.Errorf("failed to save newsletter key: %w", err)
+		}
+	case err != nil:
+		return nil, fmt.Errorf("failed to find newsletter: %w", err)
+	default:
+		_, err := tx.Exec(`
+		UPDATE newsletters
+		SET last_seen_at = datetime('now'),
+			sender = COALESCE(NULLIF(?, ''), sender),
+			list_id = COALESCE(list_id, NULLIF(?, ''))
+		WHERE id = ?
+		`, identity.sender, identity.listID, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to update newsletter: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to save newsletter: %w", err)
+	}
+	return d.getNewsletterRow(id)
+}
+
+// getNewsletterRow lê apenas os dados da newsletter, sem estatísticas
+func (d *Database) getNewsletterRow(id int64) (*Newsletter, error) {
+	var newsletter Newsletter
+	var listID, sender, iconURL, createdAt, lastSeenAt, unsubscribe, unsubscribePost, unsubscribedAt sql.NullString
+	err := d.db.QueryRow(`
+	SELECT id, name, list_id, sender, domain, icon_url, muted, created_at, last_seen_at,
+		list_unsubscribe, list_unsubscribe_post, unsubscribed_at
+	FROM newsletters WHERE id = ?
+	`, id).Scan(&newsletter.ID, &newsletter.Name, &listID, &sender, &newsletter.Domain,
+		&iconURL, &newsletter.Muted, &createdAt, &lastSeenAt, &unsubscribe, &unsubscribePost, &unsubscribedAt)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("newsletter not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get newsletter: %w", err)
+	}
+
+	newsletter.ListID = listID.String
+	newsletter.Sender = sender.String
+	newsletter.IconURL = iconURL.String
+	newsletter.CreatedAt = createdAt.String
+	newsletter.LastSeenAt = lastSeenAt.String
+	newsletter.ListUnsubscribe = unsubscribe.String
+	newsletter.ListUnsubscribePost = unsubscribePost.String
+	newsletter.UnsubscribedAt = unsubscribedAt.String
+	return &newsletter, nil
+}
+
+// newsletterSelect lê a newsletter com as estatísticas dos seus artigos
+const newsletterSelect = `
+	SELECT n.id, n.name, n.list_id, n.sender, n.domain, n.icon_url, n.muted, n.created_at, n.last_seen_at,
+		n.list_unsubscribe, n.list_unsubscribe_post, n.unsubscribed_at,
+		(SELECT GROUP_CONCAT(k.key, ',') FROM newsletter_keys k WHERE k.newsletter_id = n.id),
+		COUNT(a.id),
+		COALESCE(SUM(a.status = 'unread'), 0),
+		COALESCE(SUM(a.status = 'read'), 0),
+		COALESCE(SUM(a.status = 'archived'), 0),
+		COALESCE(SUM(a.starred), 0),
+		MIN(a.email_date),
+		MAX(a.email_date),
+		MAX(a.read_at)
+	FROM newsletters n
+	LEFT JOIN articles a ON a.newsletter_id = n.id
+`
+
+// scanNewsletter lê uma linha de newsletterSelect
+func scanNewsletter(rows *sql.Rows) (*Newsletter, error) {
+	var newsletter Newsletter
+	var listID, sender, iconURL, createdAt, lastSeenAt, keys, firstEmail, lastEmail, lastRead sql.NullString
+	var unsubscribe, unsubscribePost, unsubscribedAt sql.NullString
+	err := rows.Scan(&newsletter.ID, &newsletter.Name, &listID, &sender, &newsletter.Domain,
+		&iconURL, &newsletter.Muted, &createdAt, &lastSeenAt,
+		&unsubscribe, &unsubscribePost, &unsubscribedAt, &keys,
+		&newsletter.Stats.Articles, &newsletter.Stats.Unread, &newsletter.Stats.Read,
+		&newsletter.Stats.Archived, &newsletter.Stats.Starred, &firstEmail, &lastEmail, &lastRead)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan newsletter: %w", err)
+	}
+
+	newsletter.ListID = listID.String
+	newsletter.Sender = sender.String
+	newsletter.IconURL = iconURL.String
+	newsletter.CreatedAt = createdAt.String
+	newsletter.LastSeenAt = lastSeenAt.String
+	newsletter.Keys = []string{}
+	if keys.String != "" {
+		newsletter.Keys = strings.Split(keys.String, ",")
+	}
+	newsletter.ListUnsubscribe = unsubscribe.String
+	newsletter.ListUnsubscribePost = unsubscribePost.String
+	newsletter.UnsubscribedAt = unsubscribedAt.String
+	newsletter.Stats.FirstEmail = firstEmail.String
+	newsletter.Stats.LastEmail = lastEmail.String
+	newsletter.Stats.LastRead = lastRead.String
+	return &newsletter, nil
+}
+
+// GetNewsletters retorna todas as newsletters com suas estatísticas
+func (d *Database) GetNewsletters() ([]Newsletter, error) {
+	rows, err := d.db.Query(newsletterSelect + ` GROUP BY n.id ORDER BY n.name COLLATE NOCASE`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get newsletters: %w", err)
+	}
+	defer rows.Close()
+
+	newsletters := []Newsletter{}
+	for rows.Next() {
+		newsletter, err := scanNewsletter(rows)
+		if err != nil {
+			return nil, err
+		}
+		newsletters = append(newsletters, *newsletter)
+	}
+
+	return newsletters, rows.Err()
+}
+
+// GetNewsletter retorna uma newsletter com suas estatísticas
+func (d *Database) GetNewsletter(id int64) (*Newsletter, error) {
+	rows, err := d.db.Query(newsletterSelect+` WHERE n.id = ? GROUP BY n.id`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get newsletter: %w", err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return nil, fmt.Errorf("newsletter not found")
+	}
+	return scanNewsletter(rows)
+}
+
+// CreateNewsletter cadastra manualmente uma newsletter a partir do List-Id
+// e/ou do remetente, antes mesmo de algum email dela ser varrido
+func (d *Database) CreateNewsletter(newsletter *Newsletter) error {
+	identity, ok := identifyNewsletter(newsletter.Sender, newsletter.ListID)
+	if !ok {
+		return fmt.Errorf("list_id or sender is required")
+	}
+
+	var existing int64
+	err := d.db.QueryRow(`SELECT newsletter_id FROM newsletter_keys WHERE key = ?`, identity.key).Scan(&existing)
+	if err == nil {
+		return fmt.Errorf("newsletter already exists")
+	}
+	if err != sql.ErrNoRows {
+		return fmt.Errorf("failed to find newsletter: %w", err)
+	}
+
+	if strings.TrimSpace(newsletter.Name) == "" {
+		newsletter.Name = identity.name
+	}
+	if newsletter.Domain == "" {
+		newsletter.Domain = identity.domain
+	}
+
+	result, err := d.db.Exec(`
+	INSERT INTO newsletters (name, list_id, sender, domain, icon_url, muted)
+	VALUES (?, NULLIF(?, ''), NULLIF(?, ''), ?, NULLIF(?, ''), ?)
+	`, newsletter.Name, identity.listID, identity.sender, newsletter.Domain, newsletter.IconURL, newsletter.Muted)
+	if err != nil {
+		return fmt.Errorf("failed to create newsletter: %w", err)
+	}
+	newsletter.ID, _ = result.LastInsertId()
+
+	if _, err := d.db.Exec(`INSERT INTO newsletter_keys (key, newsletter_id) VALUES (?, ?)`, identity.key, newsletter.ID); err != nil {
+		return fmt.Errorf("failed to save newsletter key: %w", err)
+	}
+	return nil
+}
+
+// UpdateNewsletter altera nome, domínio, ícone e mute. O novo nome é
+// propagado para os artigos da newsletter.
+func (d *Database) UpdateNewsletter(newsletter *Newsletter) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
+	UPDATE newsletters SET name = ?, domain = ?, icon_url = NULLIF(?, ''), muted = ?
+	WHERE id = ?
+	`, newsletter.Name, newsletter.Domain, newsletter.IconURL, newsletter.Muted, newsletter.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update newsletter: %w", err)
+	}
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		return fmt.Errorf("newsletter not found")
+	}
+
+	if _, err := tx.Exec(`UPDATE articles SET newsletter = ? WHERE newsletter_id = ?`, newsletter.Name, newsletter.ID); err != nil {
+		return fmt.Errorf("failed to rename newsletter articles: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// DeleteNewsletter remove a newsletter e suas chaves. Os artigos são mantidos,
+// apenas desassociados; se ela reaparecer numa varredura, é recriada.
+func (d *Database) DeleteNewsletter(id int64) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`DELETE FROM newsletters WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete newsletter: %w", err)
+	}
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		return fmt.Errorf("newsletter not found")
+	}
+
+	if _, err := tx.Exec(`DELETE FROM newsletter_keys WHERE newsletter_id = ?`, id); err != nil {
+		return fmt.Errorf("failed to delete newsletter keys: %w", err)
+	}
+	if _, err := tx.Exec(`UPDATE articles SET newsletter_id = NULL WHERE newsletter_id = ?`, id); err != nil {
+		return fmt.Errorf("failed to unlink newsletter articles: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// MergeNewsletters junta outras newsletters em keepID (ex.: endereços de
+// envio alternativos). Chaves e artigos passam para a newsletter mantida.
+func (d *Database) MergeNewsletters(keepID int64, ids []int64) error {
+	keep, err := d.getNewsletterRow(keepID)
+	if err != nil {
+		return err
+	}
+
+	tx, err := d.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, id := range ids {
+		if id == keepID {
+			continue
+		}
+		if _, err := tx.Exec(`UPDATE newsletter_keys SET newsletter_id = ? WHERE newsletter_id = ?`, keepID, id); err != nil {
+			return fmt.Errorf("failed to move newsletter keys: %w", err)
+		}
+		if _, err := tx.Exec(`UPDATE articles SET newsletter_id = ?, newsletter = ? WHERE newsletter_id = ?`, keepID, keep.Name, id); err != nil {
+			return fmt.Errorf("failed to move newsletter articles: %w", err)
+		}
+		if _, err := tx.Exec(`DELETE FROM newsletters WHERE id = ?`, id); err != nil {
+			return fmt.Errorf("failed to delete merged newsletter: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// SetNewsletterUnsubscribe guarda os cabeçalhos List-Unsubscribe mais recentes
+func (d *Database) SetNewsletterUnsubscribe(id int64, header, post string) error {
+	_, err := d.db.Exec(`
+	UPDATE newsletters SET list_unsubscribe = NULLIF(?, ''), list_unsubscribe_post = NULLIF(?, '')
+	WHERE id = ?
+	`, header, post, id)
+	if err != nil {
+		return fmt.Errorf("failed to save unsubscribe headers: %w", err)
+	}
+	return nil
+}
+
+// MarkNewsletterUnsubscribed registra o descadastro e silencia a newsletter
+func (d *Database) MarkNewsletterUnsubscribed(id int64) error {
+	result, err := d.db.Exec(`UPDATE newsletters SET unsubscribed_at = datetime('now'), muted = 1 WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to mark newsletter unsubscribed: %w", err)
+	}
+	if rowsAffected, _ := result.RowsAffected(); rowsAffected == 0 {
+		return fmt.Errorf("newsletter not found")
+	}
+	return nil
+}
+
+// NewsletterRank resume o quanto uma newsletter é aproveitada
+type NewsletterRank struct {
+	Newsletter
+	Extracted    int     `json:"extracted"`     // Artigos extraídos
+	Imported     int     `json:"imported"`      // Artigos importados para a lista de leitura
+	Engaged      int     `json:"engaged"`       // Artigos lidos ou importados
+	LastActivity string  `json:"last_activity"` // Email ou leitura mais recente (RFC 3339, UTC)
+	Score        float64 `json:"score"`         // Engajamento ponderado pela atividade recente (0 a 1)
+}
+
+// RankNewsletters calcula o sinal de cada newsletter: quantos artigos rendeu,
+// quantos foram lidos ou importados (importedIDs vem da lista de leitura) e
+// quando houve atividade. Ordena do menor para o maior score, para facilitar
+// a escolha das newsletters que não são lidas.
+func (d *Database) RankNewsletters(importedIDs []int64) ([]NewsletterRank, error) {
+	newsletters, err := d.GetNewsletters()
+	if err != nil {
+		return nil, err
+	}
+
+	imported := make(map[int64]int)
+	engaged := make(map[int64]int)
+
+	// Consultar em lotes para não passar do limite de parâmetros do SQLite
+	const batchSize = 500
+	for start := 0; start < len(importedIDs); start += batchSize {
+		end := start + batchSize
+		if end > len(importedIDs) {
+			end = len(importedIDs)
+		}
+		batch := importedIDs[start:end]
+
+		placeholders, args := int64Placeholders(batch)
+		rows, err := d.db.Query(`
+		SELECT newsletter_id, COUNT(*), SUM(read_at IS NULL) FROM articles
+		WHERE newsletter_id IS NOT NULL AND id IN (`+placeholders+`)
+		GROUP BY newsletter_id
+		`, args...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count imported articles: %w", err)
+		}
+		for rows.Next() {
+			var id int64
+			var count, unread int
+			if err := rows.Scan(&id, &count, &unread); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("failed to scan imported count: %w", err)
+			}
+			imported[id] += count
+			engaged[id] += unread // Importados ainda não lidos também contam como interesse
+		}
+		rows.Close()
+	}
+
+	// Artigos com read_at foram lidos; uma única agregação para todas as newsletters
+	readCounts := make(map[int64]int)
+	rows, err := d.db.Query(`
+	SELECT newsletter_id, COUNT(*) FROM articles
+	WHERE newsletter_id IS NOT NULL AND read_at IS NOT NULL
+	GROUP BY newsletter_id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count read articles: %w", err)
+	}
+	for rows.Next() {
+		var id int64
+		var count int
+		if err := rows.Scan(&id, &count); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan read count: %w", err)
+		}
+		readCounts[id] = count
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to count read articles: %w", err)
+	}
+
+	now := time.Now()
+	ranks := make([]NewsletterRank, 0, len(newsletters))
+	for _, newsletter := range newsletters {
+		rank := NewsletterRank{
+			Newsletter: newsletter,
+			Extracted:  newsletter.Stats.Articles,
+			Imported:   imported[newsletter.ID],
+		}
+
+		// Lidos mais os importados ainda não lidos
+		rank.Engaged = readCounts[newsletter.ID] + engaged[newsletter.ID]
+
+		// email_date tem fuso (RFC 3339) e read_at é o datetime do SQLite em UTC:
+		// comparar como horários, não como texto
+		lastActivity := parseActivityTime(newsletter.Stats.LastEmail)
+		if lastRead := parseActivityTime(newsletter.Stats.LastRead); lastRead.After(lastActivity) {
+			lastActivity = lastRead
+		}
+		if !lastActivity.IsZero() {
+			rank.LastActivity = lastActivity.UTC().Format(time.RFC3339)
+		}
+
+		if rank.Extracted > 0 {
+			rate := float64(rank.Engaged) / float64(rank.Extracted)
+			if rate > 1 {
+				rate = 1
+			}
+			rank.Score = rate * recencyFactor(lastActivity, now)
+		}
+
+		ranks = append(ranks, rank)
+	}
+
+	sort.SliceStable(ranks, func(i, j int) bool {
+		if ranks[i].Score != ranks[j].Score {
+			return ranks[i].Score < ranks[j].Score
+		}
+		return ranks[i].Extracted > ranks[j].Extracted
+	})
+
+	return ranks, nil
+}
+
+// recencyFactor decai com os dias desde a última atividade (metade em 30 dias)
+func recencyFactor(lastActivity, now time.Time) float64 {
+	if lastActivity.IsZero() {
+		return 0
+	}
+	days := now.Sub(lastActivity).Hours() / 24
+	if days < 0 {
+		days = 0
+	}
+	return 1 / (1 + days/30)
+}
+
+// parseActivityTime lê as datas gravadas (RFC 3339 dos emails ou datetime do
+// SQLite, em UTC); zero se vazia ou inválida
+func parseActivityTime(value string) time.Time {
+	for _, layout := range []string{time.RFC3339, "2006-01-02 15:04:05", "2006-01-02"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
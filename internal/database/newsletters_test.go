@@ -0,0 +1,137 @@
+package database
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBackfillNewslettersOnlyOnFirstCreation(t *testing.T) {
+	path := legacyTestDB(t, map[string]string{
+		"https://example.com/a": "Weekly Go <news@golang.example>",
+		"https://example.com/b": "Weekly Go <news@golang.example>",
+		"https://example.com/c": "import:pocket",
+	})
+
+	db := openTestDB(t, path)
+	newsletters, err := db.GetNewsletters()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(newsletters) != 1 || newsletters[0].Name != "Weekly Go" || newsletters[0].Stats.Articles != 2 {
+		t.Fatalf("newsletters after migration = %+v", newsletters)
+	}
+	if id := db.newsletterIDOf(t, "https://example.com/c"); id != 0 {
+		t.Errorf("imported article linked to newsletter %d", id)
+	}
+
+	// Excluída pelo usuário, não volta ao reabrir o banco
+	if err := db.DeleteNewsletter(newsletters[0].ID); err != nil {
+		t.Fatal(err)
+	}
+	db.Close()
+
+	db = openTestDB(t, path)
+	newsletters, err = db.GetNewsletters()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(newsletters) != 0 {
+		t.Errorf("deleted newsletter came back after restart: %+v", newsletters)
+	}
+	if id := db.newsletterIDOf(t, "https://example.com/a"); id != 0 {
+		t.Errorf("article relinked to newsletter %d", id)
+	}
+}
+
+func TestResolveNewsletterAdoptsListIDOfSenderKeyedNewsletter(t *testing.T) {
+	path := legacyTestDB(t, map[string]string{
+		"https://example.com/a": "Weekly Go <news@golang.example>",
+	})
+	db := openTestDB(t, path)
+	migrated := db.newsletterIDOf(t, "https://example.com/a")
+	if migrated == 0 {
+		t.Fatal("legacy article was not linked")
+	}
+
+	tests := []struct {
+		name, from, listID string
+		wantSame           bool
+	}{
+		// Primeiro email com List-Id depois da migração: mesma newsletter
+		{"first list-id email", "Weekly Go <news@golang.example>", "Weekly Go <weekly.golang.example>", true},
+		{"same list again", "Other Name <other@golang.example>", "<weekly.golang.example>", true},
+		{"sender only", "news@golang.example", "", true},
+		// O remetente já tem List-Id: outra lista dele é outra newsletter
+		{"second list from the same sender", "Weekly Go <news@golang.example>", "<digest.golang.example>", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			newsletter, err := db.ResolveNewsletter(tt.from, tt.listID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if (newsletter.ID == migrated) != tt.wantSame {
+				t.Errorf("newsletter = %d, migrated = %d, want same = %v", newsletter.ID, migrated, tt.wantSame)
+			}
+		})
+	}
+
+	newsletter, err := db.GetNewsletter(migrated)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if newsletter.ListID != "weekly.golang.example" {
+		t.Errorf("list id = %q", newsletter.ListID)
+	}
+	if keys := strings.Join(newsletter.Keys, ","); !strings.Contains(keys, "news@golang.example") || !strings.Contains(keys, "weekly.golang.example") {
+		t.Errorf("keys = %q", keys)
+	}
+}
+
+func TestRankNewslettersLastActivity(t *testing.T) {
+	tests := []struct {
+		name, emailDate, readAt, want string
+		engaged                       int
+	}{
+		// Mesmo dia: a leitura (UTC) é mais recente que o email das 09:00 -03:00
+		{"read after email", "2026-10-18T09:00:00-03:00", "2026-10-18 13:00:00", "2026-10-18T13:00:00Z", 1},
+		// Email às 23:00 -03:00 já é dia 19 em UTC, depois da leitura
+		{"email after read across days", "2026-10-18T23:00:00-03:00", "2026-10-18 20:00:00", "2026-10-19T02:00:00Z", 1},
+		{"never read", "2026-10-18T09:00:00-03:00", "", "2026-10-18T12:00:00Z", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := newTestDB(t)
+			newsletter, err := db.ResolveNewsletter("Weekly Go <news@golang.example>", "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			article := Article{URL: "https://example.com/a", Title: "a", Domain: "example.com",
+				Newsletter: newsletter.Name, NewsletterID: newsletter.ID, EmailDate: tt.emailDate}
+			if err := db.IndexArticle(&article); err != nil {
+				t.Fatal(err)
+			}
+			if tt.readAt != "" {
+				if _, err := db.db.Exec(`UPDATE articles SET status = 'read', read_at = ? WHERE id = ?`, tt.readAt, article.ID); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			ranks, err := db.RankNewsletters(nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(ranks) != 1 {
+				t.Fatalf("ranks = %+v", ranks)
+			}
+			if ranks[0].LastActivity != tt.want {
+				t.Errorf("last activity = %q, want %q", ranks[0].LastActivity, tt.want)
+			}
+			if ranks[0].Engaged != tt.engaged {
+				t.Errorf("engaged = %d, want %d", ranks[0].Engaged, tt.engaged)
+			}
+		})
+	}
+}
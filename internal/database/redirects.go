@@ -0,0 +1,45 @@
+package database
+
+import (
+	"fmt"
+)
+
+// createRedirectTable cria o cache persistente de links de rastreamento resolvidos
+func (d *Database) createRedirectTable() error {
+	query := `
+	CREATE TABLE IF NOT EXISTS url_redirects (
+		tracker_url TEXT PRIMARY KEY,
+		final_url TEXT NOT NULL,
+		resolved_at TEXT DEFAULT (datetime('now'))
+	)
+	`
+
+	if _, err := d.db.Exec(query); err != nil {
+		return fmt.Errorf("failed to create url_redirects table: %w", err)
+	}
+	return nil
+}
+
+// GetRedirect retorna a URL final já resolvida para um link de rastreamento
+func (d *Database) GetRedirect(trackerURL string) (string, bool) {
+	var finalURL string
+	err := d.db.QueryRow(`SELECT final_url FROM url_redirects WHERE tracker_url = ?`, trackerURL).Scan(&finalURL)
+	if err != nil {
+		return "", false
+	}
+	return finalURL, true
+}
+
+// SaveRedirect guarda o mapeamento rastreador → URL final
+func (d *Database) SaveRedirect(trackerURL, finalURL string) error {
+	query := `
+	INSERT INTO url_redirects (tracker_url, final_url, resolved_at)
+	VALUES (?, ?, datetime('now'))
+	ON CONFLICT(tracker_url) DO UPDATE SET final_url = excluded.final_url, resolved_at = excluded.resolved_at
+	`
+
+	if _, err := d.db.Exec(query, trackerURL, finalURL); err != nil {
+		return fmt.Errorf("failed to save redirect: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// Rule representa uma regra definida pelo usuário, avaliada durante a varredura.
+// A condição compara um campo do link (Field) com Value usando Operator;
+// as ações dizem o que fazer com os artigos que casarem.
+type Rule struct {
+	ID         int64    `json:"id"`
+	Name       string   `json:"name"`
+	Enabled    bool     `json:"enabled"`
+	Priority   int      `json:"priority"` // Menor valor é avaliado primeiro
+	Field      string   `json:"field"`    // domain, url, newsletter, title, folder ou class
+	Operator   string   `json:"operator"` // equals, contains, prefix, suffix ou regex
+	Value      string   `json:"value"`
+	Tags       []string `json:"tags"`
+	Skip       bool     `json:"skip"`
+	MarkRead   bool     `json:"mark_read"`
+	AutoImport bool     `json:"auto_import"`
+	CreatedAt  string   `json:"created_at"`
+}
+
+// createRuleTable cria a tabela de regras
+func (d *Database) createRuleTable() error {
+	query := `
+	CREATE TABLE IF NOT EXISTS rules (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		name TEXT NOT NULL,
+		enabled INTEGER NOT NULL DEFAULT 1,
+		priority INTEGER NOT NULL DEFAULT 0,
+		field TEXT NOT NULL,
+		operator TEXT NOT NULL,
+		value TEXT NOT NULL,
+		tags TEXT NOT NULL DEFAULT '',
+		skip INTEGER NOT NULL DEFAULT 0,
+		mark_read INTEGER NOT NULL DEFAULT 0,
+		auto_import INTEGER NOT NULL DEFAULT 0,
+		created_at TEXT DEFAULT (datetime('now'))
+	)
+	`
+
+	if _, err := d.db.Exec(query); err != nil {
+		return fmt.Errorf("failed to create rules table: %w", err)
+	}
+	return nil
+}
+
+// GetRules retorna todas as regras em ordem de avaliação
+func (d *Database) GetRules() ([]Rule, error) {
+	query := `
+	SELECT id, name, enabled, priority, field, operator, value, tags, skip, mark_read, auto_import, created_at
+	FROM rules
+	ORDER BY priority, id
+	`
+
+	rows, err := d.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rules: %w", err)
+	}
+	defer rows.Close()
+
+	rules := []Rule{}
+	for rows.Next() {
+		var rule Rule
+		var tags string
+		var createdAt sql.NullString
+		err := rows.Scan(&rule.ID, &rule.Name, &rule.Enabled, &rule.Priority, &rule.Field, &rule.Operator,
+			&rule.Value, &tags, &rule.Skip, &rule.MarkRead, &rule.AutoImport, &createdAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan rule: %w", err)
+		}
+		rule.Tags = splitTags(tags)
+		rule.CreatedAt = createdAt.String
+		rules = append(rules, rule)
+	}
+
+	return rules, nil
+}
+
+// CreateRule salva uma nova regra e preenche rule.ID
+func (d *Database) CreateRule(rule *Rule) error {
+	query := `
+	INSERT INTO rules (name, enabled, priority, field, operator, value, tags, skip, mark_read, auto_import)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	result, err := d.db.Exec(query, rule.Name, rule.Enabled, rule.Priority, rule.Field, rule.Operator,
+		rule.Value, joinTags(rule.Tags), rule.Skip, rule.MarkRead, rule.AutoImport)
+	if err != nil {
+		return fmt.Errorf("failed to create rule: %w", err)
+	}
+
+	rule.ID, _ = result.LastInsertId()
+	return nil
+}
+
+// UpdateRule substitui os dados de uma regra existente
+func (d *Database) UpdateRule(rule *Rule) error {
+	query := `
+	UPDATE rules
+	SET name = ?, enabled = ?, priority = ?, field = ?, operator = ?, value = ?,
+		tags = ?, skip = ?, mark_read = ?, auto_import = ?
+	WHERE id = ?
+	`
+
+	result, err := d.db.Exec(query, rule.Name, rule.Enabled, rule.Priority, rule.Field, rule.Operator,
+		rule.Value, joinTags(rule.Tags), rule.Skip, rule.MarkRead, rule.AutoImport, rule.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update rule: %w", err)
+	}
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return fmt.Errorf("rule not found")
+	}
+	return nil
+}
+
+// DeleteRule remove uma regra pelo ID
+func (d *Database) DeleteRule(ruleID int64) error {
+	result, err := d.db.Exec(`DELETE FROM rules WHERE id = ?`, ruleID)
+	if err != nil {
+		return fmt.Errorf("failed to delete rule: %w", err)
+	}
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return fmt.Errorf("rule not found")
+	}
+	return nil
+}
+
+// joinTags serializa tags normalizadas separadas por vírgula
+func joinTags(tags []string) string {
+	var normalized []string
+	for _, tag := range tags {
+		if tag = NormalizeTag(tag); tag != "" {
+			normalized = append(normalized, tag)
+		}
+	}
+	return strings.Join(normalized, ",")
+}
+
+// splitTags é o inverso de joinTags
+func splitTags(tags string) []string {
+	if tags == "" {
+		return nil
+	}
+	return strings.Split(tags, ",")
+}
@@ -0,0 +1,248 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Tag representa uma tag definida pelo usuário e quantos artigos a usam
+type Tag struct {
+	ID    int64  `json:"id"`
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// ErrInvalidTag indica um nome de tag com vírgula: as tags são gravadas e
+// transmitidas separadas por vírgula (filtro tags=, regras, exportação)
+var ErrInvalidTag = errors.New("tag names cannot contain commas")
+
+// NormalizeTag padroniza o nome de uma tag (sem espaços nas pontas, minúsculas)
+func NormalizeTag(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// ValidateTags recusa tags com vírgula
+func ValidateTags(tags []string) error {
+	for _, tag := range tags {
+		if strings.Contains(tag, ",") {
+			return fmt.Errorf("%w: %q", ErrInvalidTag, tag)
+		}
+	}
+	return nil
+}
+
+// createTagTables cria as tabelas de tags e da relação artigo ↔ tag
+func (d *Database) createTagTables() error {
+	queries := []string{
+		`CREATE TABLE IF NOT EXISTS tags (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			name TEXT NOT NULL UNIQUE,
+			created_at TEXT DEFAULT (datetime('now'))
+		)`,
+		`CREATE TABLE IF NOT EXISTS article_tags (
+			article_id INTEGER NOT NULL,
+			tag_id INTEGER NOT NULL,
+			PRIMARY KEY (article_id, tag_id)
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_article_tags_tag ON article_tags(tag_id)`,
+	}
+
+	for _, query := range queries {
+		if _, err := d.db.Exec(query); err != nil {
+			return fmt.Errorf("failed to create tag tables: %w", err)
+		}
+	}
+	return nil
+}
+
+// GetTags retorna todas as tags com a contagem de artigos
+func (d *Database) GetTags() ([]Tag, error) {
+	query := `
+	SELECT t.id, t.name, COUNT(at.article_id) AS count
+	FROM tags t
+	LEFT JOIN article_tags at ON at.tag_id = t.id
+	GROUP BY t.id, t.name
+	ORDER BY count DESC, t.name
+	`
+
+	rows, err := d.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tags: %w", err)
+	}
+	defer rows.Close()
+
+	tags := []Tag{}
+	for rows.Next() {
+		var tag Tag
+		if err := rows.Scan(&tag.ID, &tag.Name, &tag.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan tag: %w", err)
+		}
+		tags = append(tags, tag)
+	}
+
+	return tags, nil
+}
+
+// AddTags associa as tags aos artigos, criando as tags que ainda não existem
+func (d *Database) AddTags(articleIDs []int64, tags []string) error {
+	if err := ValidateTags(tags); err != nil {
+		return err
+	}
+
+	tx, err := d.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, name := range tags {
+		name = NormalizeTag(name)
+		if name == "" {
+			continue
+		}
+
+		if _, err := tx.Exec(`INSERT OR IGNORE INTO tags (name) VALUES (?)`, name); err != nil {
+			return fmt.Errorf("failed to create tag %s: %w", name, err)
+		}
+
+		var tagID int64
+		if err := tx.QueryRow(`SELECT id FROM tags WHERE name = ?`, name).Scan(&tagID); err != nil {
+			return fmt.Errorf("failed to find tag %s: %w", name, err)
+		}
+
+		for _, articleID := range articleIDs {
+			_, err := tx.Exec(`INSERT OR IGNORE INTO article_tags (article_id, tag_id) VALUES (?, ?)`, articleID, tagID)
+			if err != nil {
+				return fmt.Errorf("failed to tag article %d: %w", articleID, err)
+			}
+		}
+	}
+
+	return tx.Commit()
+}
+
+// RemoveTags desassocia as tags dos artigos
+func (d *Database) RemoveTags(articleIDs []int64, tags []string) error {
+	if len(articleIDs) == 0 {
+		return nil
+	}
+
+	in, idArgs := int64Placeholders(articleIDs)
+	for _, name := range tags {
+		name = NormalizeTag(name)
+		if name == "" {
+			continue
+		}
+
+		query := `DELETE FROM article_tags
+		WHERE tag_id = (SELECT id FROM tags WHERE name = ?) AND article_id IN (` + in + `)`
+		args := append([]interface{}{name}, idArgs...)
+		if _, err := d.db.Exec(query, args...); err != nil {
+			return fmt.Errorf("failed to untag articles: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// DeleteTag remove a tag e todas as suas associações
+func (d *Database) DeleteTag(name string) error {
+	name = NormalizeTag(name)
+
+	if _, err := d.db.Exec(`DELETE FROM article_tags WHERE tag_id = (SELECT id FROM tags WHERE name = ?)`, name); err != nil {
+		return fmt.Errorf("failed to delete tag associations: %w", err)
+	}
+
+	result, err := d.db.Exec(`DELETE FROM tags WHERE name = ?`, name)
+	if err != nil {
+		return fmt.Errorf("failed to delete tag: %w", err)
+	}
+	rowsAffected, _ := result.RowsAffected()
+	if rowsAffected == 0 {
+		return fmt.Errorf("tag not found")
+	}
+	return nil
+}
+
+// GetArticleTags retorna as tags de vários artigos, indexadas pelo ID do artigo
+func (d *Database) GetArticleTags(articleIDs []int64) (map[int64][]string, error) {
+	result := make(map[int64][]string)
+	if len(articleIDs) == 0 {
+		return result, nil
+	}
+
+	in, args := int64Placeholders(articleIDs)
+	query := `
+	SELECT at.article_id, t.name
+	FROM article_tags at
+	JOIN tags t ON t.id = at.tag_id
+	WHERE at.article_id IN (` + in + `)
+	ORDER BY t.name
+	`
+
+	rows, err := d.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get article tags: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var articleID int64
+		var name string
+		if err := rows.Scan(&articleID, &name); err != nil {
+			return nil, fmt.Errorf("failed to scan article tag: %w", err)
+		}
+		result[articleID] = append(result[articleID], name)
+	}
+
+	return result, nil
+}
+
+// attachTags preenche o campo Tags dos artigos com uma única consulta
+func (d *Database) attachTags(articles []Article) error {
+	ids := make([]int64, len(articles))
+	for i := range articles {
+		ids[i] = articles[i].ID
+	}
+
+	tagsByArticle, err := d.GetArticleTags(ids)
+	if err != nil {
+		return err
+	}
+
+	for i := range articles {
+		articles[i].Tags = tagsByArticle[articles[i].ID]
+	}
+	return nil
+}
+
+// tagCondition monta a subconsulta de filtro por tags.
+// Com matchAll o artigo precisa ter todas as tags (AND), senão qualquer uma (OR).
+func tagCondition(tags []string, matchAll bool) (string, []interface{}) {
+	// Tags repetidas ("go,Go") contam uma vez só, senão o AND nunca casa
+	seen := make(map[string]bool, len(tags))
+	var placeholders []string
+	var args []interface{}
+	for _, tag := range tags {
+		tag = NormalizeTag(tag)
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		placeholders = append(placeholders, "?")
+		args = append(args, tag)
+	}
+	if len(args) == 0 {
+		return "", nil
+	}
+
+	subquery := `SELECT at.article_id FROM article_tags at JOIN tags t ON t.id = at.tag_id
+		WHERE t.name IN (` + strings.Join(placeholders, ", ") + `)`
+	if matchAll {
+		subquery += ` GROUP BY at.article_id HAVING COUNT(DISTINCT t.id) = ?`
+		args = append(args, len(placeholders))
+	}
+
+	return " AND id IN (" + subquery + ")", args
+}
@@ -0,0 +1,84 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestTagFilter(t *testing.T) {
+	db := newTestDB(t)
+
+	// a: go, db   b: go   c: db, rust   d: sem tags
+	articleTags := map[string][]string{
+		"a": {"go", "db"},
+		"b": {"Go"},
+		"c": {"db", "rust"},
+		"d": nil,
+	}
+	ids := make(map[int64]string)
+	for _, name := range []string{"a", "b", "c", "d"} {
+		article := Article{URL: fmt.Sprintf("https://example.com/%s", name), Title: name, Domain: "example.com"}
+		if err := db.IndexArticle(&article); err != nil {
+			t.Fatal(err)
+		}
+		ids[article.ID] = name
+		if tags := articleTags[name]; tags != nil {
+			if err := db.AddTags([]int64{article.ID}, tags); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	tests := []struct {
+		name string
+		tags []string
+		all  bool
+		want []string
+	}{
+		{"or", []string{"go", "rust"}, false, []string{"a", "b", "c"}},
+		{"and", []string{"go", "db"}, true, []string{"a"}},
+		{"and with duplicates", []string{"go", "Go", " go "}, true, []string{"a", "b"}},
+		{"and with duplicates and another tag", []string{"go", "GO", "db"}, true, []string{"a"}},
+		{"and with unknown tag", []string{"go", "python"}, true, nil},
+		{"only empty tags", []string{"", " "}, true, []string{"a", "b", "c", "d"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			articles, _, err := db.GetAllArticles(1, -1, ArticleFilter{Tags: tt.tags, TagsAll: tt.all})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, article := range articles {
+				got = append(got, ids[article.ID])
+			}
+			sort.Strings(got)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("articles = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAddTagsRejectsCommas(t *testing.T) {
+	db := newTestDB(t)
+	article := Article{URL: "https://example.com/a", Title: "a", Domain: "example.com"}
+	if err := db.IndexArticle(&article); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := db.AddTags([]int64{article.ID}, []string{"go", "a,b"}); !errors.Is(err, ErrInvalidTag) {
+		t.Fatalf("err = %v, want ErrInvalidTag", err)
+	}
+	tags, err := db.GetTags()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tags) != 0 {
+		t.Errorf("tags = %+v, want none", tags)
+	}
+}
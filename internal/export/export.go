@@ -0,0 +1,345 @@
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Formatos de exportação suportados
+const (
+	FormatCSV           = "csv"
+	FormatJSON          = "json"
+	FormatNDJSON        = "ndjson"
+	FormatHTMLBookmarks = "html-bookmarks"
+	FormatOPML          = "opml"
+)
+
+// Record é a representação comum de um artigo para exportação
+type Record struct {
+	ID          int64      `json:"id"`
+	URL         string     `json:"url"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	Domain      string     `json:"domain"`
+	Newsletter  string     `json:"newsletter"`
+	EmailDate   string     `json:"email_date"`
+	Folder      string     `json:"folder"`
+	Status      string     `json:"status,omitempty"`
+	Starred     bool       `json:"starred,omitempty"`
+	ReadAt      string     `json:"read_at,omitempty"`
+	Tags        []string   `json:"tags"`
+	CreatedAt   string     `json:"created_at,omitempty"`
+	ImportedAt  *time.Time `json:"imported_at,omitempty"` // Só na lista de leitura
+	Content     string     `json:"content,omitempty"`
+	ContentType string     `json:"content_type,omitempty"`
+}
+
+// Writer escreve registros um a um, sem manter a exportação inteira em memória
+type Writer interface {
+	Write(record Record) error
+	// Close finaliza o documento (fecha arrays, tags, etc.) e faz flush
+	Close() error
+}
+
+// Options controla o conteúdo da exportação
+type Options struct {
+	IncludeContent bool   // Inclui o HTML do artigo (lista de leitura)
+	Title          string // Título do documento em html-bookmarks e OPML
+}
+
+// IsValidFormat verifica se o formato é suportado
+func IsValidFormat(format string) bool {
+	switch format {
+	case FormatCSV, FormatJSON, FormatNDJSON, FormatHTMLBookmarks, FormatOPML:
+		return true
+	}
+	return false
+}
+
+// ContentType retorna o Content-Type HTTP do formato
+func ContentType(format string) string {
+	switch format {
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	case FormatJSON:
+		return "application/json"
+	case FormatNDJSON:
+		return "application/x-ndjson"
+	case FormatHTMLBookmarks:
+		return "text/html; charset=utf-8"
+	case FormatOPML:
+		return "text/x-opml; charset=utf-8"
+	}
+	return "application/octet-stream"
+}
+
+// FileExtension retorna a extensão de arquivo sugerida para o formato
+func FileExtension(format string) string {
+	switch format {
+	case FormatHTMLBookmarks:
+		return "html"
+	case FormatOPML:
+		return "opml"
+	}
+	return format
+}
+
+// NewWriter cria o escritor do formato pedido, já emitindo o cabeçalho do documento
+func NewWriter(format string, w io.Writer, opts Options) (Writer, error) {
+	if opts.Title == "" {
+		opts.Title = "Gmail Scanner"
+	}
+
+	var writer Writer
+	var err error
+
+	switch format {
+	case FormatCSV:
+		writer, err = newCSVWriter(w, opts)
+	case FormatJSON:
+		writer, err = newJSONWriter(w, opts, false)
+	case FormatNDJSON:
+		writer, err = newJSONWriter(w, opts, true)
+	case FormatHTMLBookmarks:
+		writer, err = newBookmarksWriter(w, opts)
+	case FormatOPML:
+		writer, err = newOPMLWriter(w, opts)
+	default:
+		return nil, fmt.Errorf("unsupported export format: %s", format)
+	}
+
+	return writer, err
+}
+
+// ==================== CSV ====================
+
+type csvWriter struct {
+	w    *csv.Writer
+	opts Options
+}
+
+func newCSVWriter(w io.Writer, opts Options) (*csvWriter, error) {
+	cw := &csvWriter{w: csv.NewWriter(w), opts: opts}
+
+	header := []string{"id", "url", "title", "description", "domain", "newsletter", "email_date",
+		"folder", "status", "starred", "read_at", "tags", "created_at"}
+	if opts.IncludeContent {
+		header = append(header, "imported_at", "content_type", "content")
+	}
+
+	if err := cw.w.Write(header); err != nil {
+		return nil, fmt.Errorf("failed to write csv header: %w", err)
+	}
+	return cw, nil
+}
+
+func (c *csvWriter) Write(r Record) error {
+	row := []string{
+		strconv.FormatInt(r.ID, 10), r.URL, r.Title, r.Description, r.Domain, r.Newsletter, r.EmailDate,
+		r.Folder, r.Status, strconv.FormatBool(r.Starred), r.ReadAt, strings.Join(r.Tags, ","), r.CreatedAt,
+	}
+	if c.opts.IncludeContent {
+		importedAt := ""
+		if r.ImportedAt != nil {
+			importedAt = r.ImportedAt.Format(time.RFC3339)
+		}
+		row = append(row, importedAt, r.ContentType, r.Content)
+	}
+	return c.w.Write(row)
+}
+
+func (c *csvWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// ==================== JSON / NDJSON ====================
+
+type jsonWriter struct {
+	w         io.Writer
+	enc       *json.Encoder
+	opts      Options
+	delimited bool // NDJSON: um objeto por linha, sem array
+	count     int
+}
+
+func newJSONWriter(w io.Writer, opts Options, delimited bool) (*jsonWriter, error) {
+	jw := &jsonWriter{w: w, enc: json.NewEncoder(w), opts: opts, delimited: delimited}
+	if !delimited {
+		if _, err := io.WriteString(w, "[\n"); err != nil {
+			return nil, err
+		}
+	}
+	return jw, nil
+}
+
+func (j *jsonWriter) Write(r Record) error {
+	if !j.opts.IncludeContent {
+		r.Content = ""
+		r.ContentType = ""
+	}
+	if r.Tags == nil {
+		r.Tags = []string{}
+	}
+
+	if !j.delimited && j.count > 0 {
+		if _, err := io.WriteString(j.w, ","); err != nil {
+			return err
+		}
+	}
+	j.count++
+
+	// Encoder.Encode já termina cada objeto com "\n"
+	return j.enc.Encode(r)
+}
+
+func (j *jsonWriter) Close() error {
+	if j.delimited {
+		return nil
+	}
+	_, err := io.WriteString(j.w, "]\n")
+	return err
+}
+
+// ==================== Netscape bookmarks ====================
+
+type bookmarksWriter struct {
+	w io.Writer
+}
+
+func newBookmarksWriter(w io.Writer, opts Options) (*bookmarksWriter, error) {
+	header := `<!DOCTYPE NETSCAPE-Bookmark-file-1>
+<!-- This is an automatically generated file.
+     It will be read and overwritten.
+     DO NOT EDIT! -->
+<META HTTP-EQUIV="Content-Type" CONTENT="text/html; charset=UTF-8">
+<TITLE>Bookmarks</TITLE>
+<H1>Bookmarks</H1>
+<DL><p>
+    <DT><H3>` + html.EscapeString(opts.Title) + `</H3>
+    <DL><p>
+`
+	if _, err := io.WriteString(w, header); err != nil {
+		return nil, err
+	}
+	return &bookmarksWriter{w: w}, nil
+}
+
+func (b *bookmarksWriter) Write(r Record) error {
+	var attrs strings.Builder
+	attrs.WriteString(` HREF="` + html.EscapeString(r.URL) + `"`)
+	if addDate := recordTime(r); !addDate.IsZero() {
+		attrs.WriteString(` ADD_DATE="` + strconv.FormatInt(addDate.Unix(), 10) + `"`)
+	}
+	if len(r.Tags) > 0 {
+		attrs.WriteString(` TAGS="` + html.EscapeString(strings.Join(r.Tags, ",")) + `"`)
+	}
+
+	title := r.Title
+	if title == "" {
+		title = r.URL
+	}
+
+	entry := `        <DT><A` + attrs.String() + `>` + html.EscapeString(title) + "</A>\n"
+	if r.Description != "" {
+		entry += `        <DD>` + html.EscapeString(r.Description) + "\n"
+	}
+
+	_, err := io.WriteString(b.w, entry)
+	return err
+}
+
+func (b *bookmarksWriter) Close() error {
+	_, err := io.WriteString(b.w, "    </DL><p>\n</DL><p>\n")
+	return err
+}
+
+// ==================== OPML ====================
+
+type opmlWriter struct {
+	w io.Writer
+}
+
+// opmlOutline é um item de link no OPML
+type opmlOutline struct {
+	XMLName     xml.Name `xml:"outline"`
+	Text        string   `xml:"text,attr"`
+	Type        string   `xml:"type,attr"`
+	URL         string   `xml:"url,attr"`
+	Description string   `xml:"description,attr,omitempty"`
+	Category    string   `xml:"category,attr,omitempty"`
+	Created     string   `xml:"created,attr,omitempty"`
+}
+
+func newOPMLWriter(w io.Writer, opts Options) (*opmlWriter, error) {
+	var title strings.Builder
+	xml.EscapeText(&title, []byte(opts.Title))
+
+	header := xml.Header + `<opml version="2.0">
+  <head>
+    <title>` + title.String() + `</title>
+    <dateCreated>` + time.Now().UTC().Format(time.RFC1123Z) + `</dateCreated>
+  </head>
+  <body>
+`
+	if _, err := io.WriteString(w, header); err != nil {
+		return nil, err
+	}
+	return &opmlWriter{w: w}, nil
+}
+
+func (o *opmlWriter) Write(r Record) error {
+	title := r.Title
+	if title == "" {
+		title = r.URL
+	}
+
+	outline := opmlOutline{
+		Text:        title,
+		Type:        "link",
+		URL:         r.URL,
+		Description: r.Description,
+		Category:    strings.Join(r.Tags, ","),
+	}
+	if created := recordTime(r); !created.IsZero() {
+		outline.Created = created.UTC().Format(time.RFC1123Z)
+	}
+
+	data, err := xml.Marshal(outline)
+	if err != nil {
+		return fmt.Errorf("failed to marshal outline: %w", err)
+	}
+
+	_, err = io.WriteString(o.w, "    "+string(data)+"\n")
+	return err
+}
+
+func (o *opmlWriter) Close() error {
+	_, err := io.WriteString(o.w, "  </body>\n</opml>\n")
+	return err
+}
+
+// recordTime escolhe a data mais representativa do registro
+func recordTime(r Record) time.Time {
+	if r.ImportedAt != nil {
+		return *r.ImportedAt
+	}
+	for _, value := range []string{r.EmailDate, r.CreatedAt} {
+		if value == "" {
+			continue
+		}
+		for _, layout := range []string{time.RFC3339, "2006-01-02 15:04:05"} {
+			if t, err := time.Parse(layout, value); err == nil {
+				return t
+			}
+		}
+	}
+	return time.Time{}
+}
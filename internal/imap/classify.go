@@ -0,0 +1,408 @@
+package imap
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/gustavoflandal/gmail-scanner/internal/resolver"
+)
+
+// Classes de link atribuídas na extração
+const (
+	ClassArticle = "article"
+	ClassSponsor = "sponsor"
+	ClassJob     = "job"
+	ClassSocial  = "social"
+	ClassFooter  = "footer"
+	ClassProduct = "product"
+)
+
+// Ações possíveis para cada classe na varredura
+const (
+	ActionKeep = "keep" // Salva o link normalmente
+	ActionTag  = "tag"  // Salva e aplica a tag com o nome da classe
+	ActionDrop = "drop" // Descarta o link
+)
+
+// IsValidClass verifica se a classe é conhecida
+func IsValidClass(class string) bool {
+	switch class {
+	case ClassArticle, ClassSponsor, ClassJob, ClassSocial, ClassFooter, ClassProduct:
+		return true
+	}
+	return false
+}
+
+var (
+	sponsorMarkers = regexp.MustCompile(`(?i)\b(sponsor(ed|s)?|patrocinad[oa]s?|presented by|brought to you by|in partnership with|partner(ed)? content|advertis(ement|ing)|publicidade|promoted)\b|\((ad|ads)\)|\[(ad|ads)\]`)
+	jobMarkers     = regexp.MustCompile(`(?i)\b(we'?re hiring|hiring|job (board|opening|post)s?|jobs|careers?|vagas?|open (roles?|positions?)|is looking for an?)\b`)
+	footerMarkers  = regexp.MustCompile(`(?i)(unsubscribe|descadastr|manage (your )?(preferences|subscription)|update your (profile|preferences)|privacy policy|pol[ií]tica de privacidade|terms of (service|use)|view (this email )?in (your )?browser|forward (this )?to a friend|all rights reserved|©)`)
+	genericTitles  = map[string]bool{
+		"read more": true, "click here": true, "here": true, "learn more": true, "link": true,
+		"continue reading": true, "view": true, "more": true, "leia mais": true, "saiba mais": true,
+		"clique aqui": true, "aqui": true, "read": true, "open": true, "watch": true, "listen": true,
+		"subscribe": true, "sign up": true, "share": true, "tweet": true,
+	}
+)
+
+// Listas de domínios (casam pelo sufixo, respeitando a fronteira de rótulo)
+var (
+	socialDomains = []string{
+		"twitter.com", "x.com", "facebook.com", "instagram.com", "linkedin.com", "youtube.com",
+		"tiktok.com", "threads.net", "mastodon.social", "bsky.app", "discord.gg", "discord.com",
+		"t.me", "telegram.me", "pinterest.com", "reddit.com/user",
+	}
+	jobDomains = []string{
+		"greenhouse.io", "lever.co", "workable.com", "ashbyhq.com", "smartrecruiters.com",
+		"wellfound.com", "angel.co", "weworkremotely.com", "remoteok.com", "indeed.com",
+		"glassdoor.com", "linkedin.com/jobs", "jobs.", "careers.",
+	}
+	productDomains = []string{
+		"apps.apple.com", "play.google.com", "amazon.com", "amzn.to", "producthunt.com",
+		"gumroad.com", "chrome.google.com/webstore", "chromewebstore.google.com", "etsy.com",
+		"shopify.com", "kickstarter.com", "indiegogo.com", "store.",
+	}
+)
+
+// classWeights reduz o score das classes que raramente são leitura
+var classWeights = map[string]float64{
+	ClassArticle: 1.0,
+	ClassProduct: 0.7,
+	ClassJob:     0.5,
+	ClassSponsor: 0.35,
+	ClassSocial:  0.2,
+	ClassFooter:  0.1,
+}
+
+// linkPlacement guarda o contexto e a posição do link no email, para refazer a
+// classificação quando o destino do link muda
+type linkPlacement struct {
+	ctx      linkContext
+	position int
+	total    int
+}
+
+// linkContext são as características do link no email usadas na classificação
+type linkContext struct {
+	text        string  // Texto ao redor do link (bloco que o contém)
+	imageOnly   bool    // Link só com imagem, sem texto
+	linkDensity float64 // Fração do texto do bloco que é texto de links
+	imageURL    string  // Imagem dentro ou perto do link
+	imageAlt    string
+}
+
+// LinkPolicy decide o que fazer com cada link conforme score e classe
+type LinkPolicy struct {
+	Threshold float64           `json:"threshold"`
+	Actions   map[string]string `json:"actions"` // classe → keep, tag ou drop
+}
+
+// DefaultLinkPolicy descarta links de baixo score, patrocínios, redes sociais e rodapés
+func DefaultLinkPolicy() LinkPolicy {
+	return LinkPolicy{
+		Threshold: 0.4,
+		Actions: map[string]string{
+			ClassArticle: ActionKeep,
+			ClassProduct: ActionTag,
+			ClassJob:     ActionTag,
+			ClassSponsor: ActionDrop,
+			ClassSocial:  ActionDrop,
+			ClassFooter:  ActionDrop,
+		},
+	}
+}
+
+// ParseLinkPolicy monta a política a partir da configuração: threshold ("0.4")
+// e ações ("sponsor=drop,job=tag"). Valores vazios mantêm o padrão.
+func ParseLinkPolicy(threshold, actions string) (LinkPolicy, error) {
+	policy := DefaultLinkPolicy()
+
+	if threshold = strings.TrimSpace(threshold); threshold != "" {
+		value, err := strconv.ParseFloat(threshold, 64)
+		if err != nil || value < 0 || value > 1 {
+			return policy, fmt.Errorf("invalid threshold %q: must be between 0 and 1", threshold)
+		}
+		policy.Threshold = value
+	}
+
+	for _, pair := range strings.Split(actions, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		class, action, found := strings.Cut(pair, "=")
+		class = strings.ToLower(strings.TrimSpace(class))
+		action = strings.ToLower(strings.TrimSpace(action))
+		if !found || !IsValidClass(class) {
+			return policy, fmt.Errorf("invalid class action %q", pair)
+		}
+		switch action {
+		case ActionKeep, ActionTag, ActionDrop:
+			policy.Actions[class] = action
+		default:
+			return policy, fmt.Errorf("invalid action %q for class %s", action, class)
+		}
+	}
+
+	return policy, nil
+}
+
+// Decide diz se o link deve ser salvo e, se for o caso, a tag a aplicar
+func (p LinkPolicy) Decide(link EmailLink) (keep bool, tag string) {
+	if link.Score < p.Threshold {
+		return false, ""
+	}
+
+	switch p.Actions[link.Class] {
+	case ActionDrop:
+		return false, ""
+	case ActionTag:
+		return true, link.Class
+	}
+	return true, ""
+}
+
+// classifyLinks atribui classe e score a cada link; a posição relativa só é
+// conhecida depois que todos os links do email foram coletados
+func classifyLinks(links []EmailLink, contexts []linkContext) {
+	total := len(links)
+	for i := range links {
+		links[i].placement = linkPlacement{ctx: contexts[i], position: i, total: total}
+		links[i].classify()
+	}
+}
+
+// classify calcula classe e score com o contexto guardado na extração
+func (l *EmailLink) classify() {
+	p := l.placement
+	l.Class = classifyLink(*l, p.ctx, p.position, p.total)
+	l.Score = scoreLink(*l, p.ctx, l.Class, p.position, p.total)
+}
+
+// Retarget aponta o link para o destino final de um rastreador (resolvido online)
+// e refaz classe e score com o novo domínio
+func (l *EmailLink) Retarget(finalURL, domain string) {
+	l.URL, l.Domain = finalURL, domain
+	if l.placement.total > 0 {
+		l.classify()
+	}
+}
+
+// linkTarget é o host+caminho usado na classificação. Links de rastreadores são
+// decodificados sem acessar a rede, para as listas de domínios verem o destino real.
+func linkTarget(link EmailLink) string {
+	rawURL, domain := link.URL, link.Domain
+	if u, err := url.Parse(rawURL); err == nil && resolver.IsTracker(u) {
+		if decoded, ok := resolver.DecodeOffline(u); ok {
+			if target, err := url.Parse(decoded); err == nil && target.Host != "" {
+				rawURL, domain = decoded, target.Hostname()
+			}
+		}
+	}
+	return strings.ToLower(domain + urlPath(rawURL))
+}
+
+// classifyLink escolhe a classe pelo domínio e pelos marcadores no título e no contexto
+func classifyLink(link EmailLink, ctx linkContext, position, total int) string {
+	target := linkTarget(link)
+	text := link.Title + " " + ctx.text
+
+	switch {
+	case matchesDomainList(target, jobDomains): // Antes das redes sociais, por linkedin.com/jobs
+		return ClassJob
+	case matchesDomainList(target, socialDomains):
+		return ClassSocial
+	case jobMarkers.MatchString(link.Title):
+		return ClassJob
+	case sponsorMarkers.MatchString(text):
+		return ClassSponsor
+	case footerMarkers.MatchString(link.Title) || (isTail(position, total) && footerMarkers.MatchString(ctx.text)):
+		return ClassFooter
+	case matchesDomainList(target, productDomains):
+		return ClassProduct
+	}
+	return ClassArticle
+}
+
+// scoreLink combina a qualidade do título com a classe e o contexto (0 a 1)
+func scoreLink(link EmailLink, ctx linkContext, class string, position, total int) float64 {
+	score := titleQuality(link.Title, link.URL)
+
+	if link.Description != "" {
+		score += 0.1
+	}
+	if ctx.imageOnly {
+		score *= 0.6
+	}
+	if ctx.linkDensity > 0.9 && link.Description == "" {
+		score *= 0.85 // Listas de links soltos (menus, rodapés)
+	}
+	if isTail(position, total) {
+		score *= 0.8
+	}
+
+	score *= classWeights[class]
+
+	if score > 1 {
+		score = 1
+	}
+	return float64(int(score*1000+0.5)) / 1000
+}
+
+// titleQuality avalia se o texto parece um título de artigo, sem exigir
+// maiúscula inicial nem tamanho mínimo (aceita "iOS 18 is out" e títulos CJK)
+func titleQuality(title, href string) float64 {
+	title = strings.TrimSpace(title)
+	if title == "" || title == href || strings.HasPrefix(title, "http://") || strings.HasPrefix(title, "https://") {
+		return 0
+	}
+	if genericTitles[strings.ToLower(strings.Trim(title, ".…!»>→ "))] {
+		return 0.1
+	}
+
+	var letters, runes int
+	cjk := false
+	for _, r := range title {
+		runes++
+		if unicode.IsLetter(r) {
+			letters++
+		}
+		if unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul) {
+			cjk = true
+		}
+	}
+	if letters == 0 {
+		return 0
+	}
+
+	// Escritas sem espaços entre palavras: conta caracteres
+	if cjk {
+		switch {
+		case runes >= 8:
+			return 0.9
+		case runes >= 4:
+			return 0.7
+		default:
+			return 0.4
+		}
+	}
+
+	var quality float64
+	switch words := len(strings.Fields(title)); {
+	case words >= 3:
+		quality = 0.8
+	case words == 2:
+		quality = 0.5
+	default:
+		quality = 0.3
+	}
+	if runes >= 15 {
+		quality += 0.2
+	}
+	if float64(letters)/float64(runes) < 0.5 {
+		quality *= 0.5 // Muitos números/símbolos
+	}
+
+	if quality > 1 {
+		quality = 1
+	}
+	return quality
+}
+
+// isTail indica se o link está nos últimos 15% do email (onde ficam os rodapés)
+func isTail(position, total int) bool {
+	return total >= 4 && float64(position) >= float64(total)*0.85
+}
+
+// matchesDomainList compara host+caminho com a lista. Entradas terminadas em
+// "." casam com o início do host (jobs.exemplo.com); as demais pelo sufixo do host
+// e, se tiverem caminho, pelo prefixo do caminho.
+func matchesDomainList(target string, list []string) bool {
+	host, path, _ := strings.Cut(target, "/")
+	path = "/" + path
+	for _, entry := range list {
+		entryHost, entryPath, hasPath := strings.Cut(entry, "/")
+		if strings.HasSuffix(entryHost, ".") {
+			if strings.HasPrefix(host, entryHost) {
+				return true
+			}
+			continue
+		}
+		if host != entryHost && !strings.HasSuffix(host, "."+entryHost) {
+			continue
+		}
+		if !hasPath || strings.HasPrefix(path, "/"+entryPath) {
+			return true
+		}
+	}
+	return false
+}
+
+// truncateRunes corta o texto em n caracteres, sem partir caracteres UTF-8
+func truncateRunes(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n])
+}
+
+// urlPath devolve o caminho da URL (com "/" inicial) ou vazio
+func urlPath(rawURL string) string {
+	rest := rawURL
+	if i := strings.Index(rest, "://"); i >= 0 {
+		rest = rest[i+3:]
+	}
+	if i := strings.Index(rest, "/"); i >= 0 {
+		return rest[i:]
+	}
+	return ""
+}
+
+// htmlLinkContext descreve o bloco do email em que o link aparece
+func htmlLinkContext(s *goquery.Selection) linkContext {
+	var ctx linkContext
+
+	linkText := cleanText(s.Text())
+	ctx.imageOnly = linkText == "" && s.Find("img").Length() > 0
+
+	// Subir até um bloco com mais texto que o próprio link
+	container := s.Parent()
+	for i := 0; i < 4 && container.Length() > 0; i++ {
+		if len(cleanText(container.Text())) > len(linkText)+40 || container.Is("td, li, p, table") {
+			break
+		}
+		container = container.Parent()
+	}
+
+	text := cleanText(container.Text())
+	if len(text) > 0 {
+		var anchorText int
+		container.Find("a").Each(func(i int, a *goquery.Selection) {
+			anchorText += len(cleanText(a.Text()))
+		})
+		ctx.linkDensity = float64(anchorText) / float64(len(text))
+	}
+
+	// Rótulos como "SPONSOR" costumam ficar no bloco anterior (ou na linha anterior da tabela)
+	prev := ""
+	for block := container; block.Length() > 0 && prev == "" && !block.Is("body"); block = block.Parent() {
+		prev = cleanText(block.Prev().Text())
+		if block.Is("table") {
+			break
+		}
+	}
+	if prev != "" {
+		text = truncateRunes(prev, 120) + " " + text
+	}
+	ctx.text = truncateRunes(text, 500)
+
+	ctx.imageURL, ctx.imageAlt = nearbyImage(s, container)
+
+	return ctx
+}
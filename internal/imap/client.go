@@ -9,14 +9,12 @@ import (
 	"regexp"
 	"strings"
 	"time"
-	"unicode"
 
 	"github.com/PuerkitoBio/goquery"
 	"github.com/emersion/go-imap"
 	"github.com/emersion/go-imap/client"
 	"github.com/emersion/go-message"
 	_ "github.com/emersion/go-message/charset"
-	"github.com/emersion/go-message/mail"
 	"github.com/sirupsen/logrus"
 )
 
@@ -24,30 +22,44 @@ var log = logrus.New()
 
 // Client representa um cliente IMAP conectado
 type Client struct {
-	conn  *client.Client
-	email string
+	conn     *client.Client
+	email    string
+	profiles *Registry
 }
 
 // Message representa uma mensagem de email
 type Message struct {
 	MessageID      string
 	From           string
+	ListID         string
 	Subject        string
 	Date           time.Time
 	Body           string
 	SnippetPreview string
 	Folder         string
 	IsRead         bool
+	Profile        string // Extrator usado nos links ("generic" ou nome do perfil)
+	BodyError      error  // Falha ao interpretar o corpo MIME (*ParseError)
 	Links          []EmailLink
+
+	// Cabeçalhos de descadastro (RFC 2369 e RFC 8058)
+	ListUnsubscribe     string
+	ListUnsubscribePost string
 }
 
 // EmailLink representa um link extraído do corpo do email
 type EmailLink struct {
-	URL         string
-	Title       string
-	Description string
-	Domain      string
-	Position    int
+	URL         string  `json:"url"`
+	Title       string  `json:"title"`
+	Description string  `json:"description,omitempty"`
+	Domain      string  `json:"domain"`
+	Position    int     `json:"position"`
+	Score       float64 `json:"score"`               // 0 a 1: quanto o link parece um artigo
+	Class       string  `json:"class"`               // article, sponsor, job, social, footer ou product
+	ImageURL    string  `json:"image_url,omitempty"` // Imagem da newsletter associada ao link
+	ImageAlt    string  `json:"image_alt,omitempty"`
+
+	placement linkPlacement // Contexto da classificação (ver Retarget)
 }
 
 // Connect estabelece conexão com servidor IMAP do Gmail
@@ -69,11 +81,19 @@ func Connect(email, password string) (*Client, error) {
 	log.Infof("Successfully authenticated as %s", email)
 
 	return &Client{
-		conn:  conn,
-		email: email,
+		conn:     conn,
+		email:    email,
+		profiles: NewRegistry(nil),
 	}, nil
 }
 
+// UseProfiles define os perfis de extração de links usados nas próximas buscas
+func (c *Client) UseProfiles(profiles *Registry) {
+	if profiles != nil {
+		c.profiles = profiles
+	}
+}
+
 // Close fecha a conexão IMAP
 func (c *Client) Close() error {
 	if c.conn != nil {
@@ -195,27 +215,7 @@ func (c *Client) FetchMessages(folder string, limit uint32) ([]*Message, error)
 				log.Warnf("Failed to read body: %v", err)
 			} else if len(body) > 0 {
 				log.Infof("Got body with %d bytes for: %s", len(body), message.Subject)
-
-				// Tentar extrair HTML do corpo MIME
-				htmlContent := extractHTMLFromMIME(body)
-				if htmlContent != "" {
-					message.Body = htmlContent
-
-					// Extrair links do corpo HTML
-					message.Links = extractLinks(htmlContent)
-					if len(message.Links) > 0 {
-						log.Infof("Extracted %d links from email: %s", len(message.Links), message.Subject)
-					} else {
-						log.Infof("No links found in email: %s", message.Subject)
-					}
-				} else {
-					// Fallback: usar corpo bruto
-					message.Body = string(body)
-					message.Links = extractLinks(string(body))
-					if len(message.Links) > 0 {
-						log.Infof("Extracted %d links (raw) from email: %s", len(message.Links), message.Subject)
-					}
-				}
+				c.profiles.parseBody(message, body)
 			} else {
 				log.Warnf("Empty body for email: %s", message.Subject)
 			}
@@ -234,6 +234,41 @@ func (c *Client) FetchMessages(folder string, limit uint32) ([]*Message, error)
 	return result, nil
 }
 
+// parseBody preenche cabeçalhos de lista, corpo e links da mensagem a partir
+// do email bruto, escolhendo o extrator pelo remetente e List-Id
+func (r *Registry) parseBody(message *Message, body []byte) {
+	readListHeaders(message, body)
+	extractor := r.ExtractorFor(message.From, message.ListID)
+	message.Profile = extractor.Name()
+
+	// Extrair as partes HTML e texto do corpo MIME. Partes legíveis são
+	// aproveitadas mesmo com erro; o corpo bruto nunca é usado no lugar delas.
+	htmlContent, textContent, err := extractBodiesFromMIME(body)
+	if err != nil {
+		message.BodyError = err
+		log.Warnf("Failed to parse body of email %s: %v", message.Subject, err)
+	}
+
+	message.Body = htmlContent
+	if message.Body == "" {
+		message.Body = textContent
+	}
+
+	// Links do HTML primeiro (mais ricos); a parte texto completa o que faltar
+	if htmlContent != "" {
+		message.Links = extractLinks(extractor, htmlContent)
+	}
+	if textContent != "" {
+		message.Links = mergeLinks(message.Links, extractTextLinks(textContent))
+	}
+
+	if len(message.Links) > 0 {
+		log.Infof("Extracted %d links from email: %s (profile: %s)", len(message.Links), message.Subject, extractor.Name())
+	} else {
+		log.Infof("No links found in email: %s", message.Subject)
+	}
+}
+
 // fetchSnippet busca um preview do corpo da mensagem
 func (c *Client) fetchSnippet(uid uint32, folder string) (string, error) {
 	// Re-selecionar pasta se necessário
@@ -327,91 +362,6 @@ func TestConnection(email, password string) error {
 	return nil
 }
 
-// extractHTMLFromMIME extrai o conteúdo HTML de um corpo MIME
-func extractHTMLFromMIME(rawBody []byte) string {
-	// Tentar parsear como mensagem MIME
-	r := bytes.NewReader(rawBody)
-
-	// Primeiro tentar como email completo
-	mr, err := mail.CreateReader(r)
-	if err != nil {
-		// Se falhar, tentar como entidade única
-		r.Reset(rawBody)
-		entity, err := message.Read(r)
-		if err != nil {
-			// Retornar corpo bruto se não conseguir parsear
-			return string(rawBody)
-		}
-
-		// Se for multipart, iterar pelas partes
-		mpReader := entity.MultipartReader()
-		if mpReader != nil {
-			for {
-				part, err := mpReader.NextPart()
-				if err != nil {
-					break
-				}
-
-				contentType, _, _ := part.Header.ContentType()
-				if strings.Contains(contentType, "text/html") {
-					body, err := io.ReadAll(part.Body)
-					if err == nil && len(body) > 0 {
-						return string(body)
-					}
-				}
-			}
-		}
-
-		// Se não for multipart, verificar se é HTML
-		contentType, _, _ := entity.Header.ContentType()
-		if strings.Contains(contentType, "text/html") || strings.Contains(contentType, "text/plain") {
-			body, err := io.ReadAll(entity.Body)
-			if err == nil {
-				return string(body)
-			}
-		}
-
-		return string(rawBody)
-	}
-	defer mr.Close()
-
-	var htmlContent string
-	var textContent string
-
-	// Iterar pelas partes do email
-	for {
-		part, err := mr.NextPart()
-		if err != nil {
-			break
-		}
-
-		switch h := part.Header.(type) {
-		case *mail.InlineHeader:
-			contentType, _, _ := h.ContentType()
-			body, err := io.ReadAll(part.Body)
-			if err != nil {
-				continue
-			}
-
-			if strings.Contains(contentType, "text/html") {
-				htmlContent = string(body)
-			} else if strings.Contains(contentType, "text/plain") && textContent == "" {
-				textContent = string(body)
-			}
-		}
-	}
-
-	// Preferir HTML sobre texto plano
-	if htmlContent != "" {
-		return htmlContent
-	}
-	if textContent != "" {
-		return textContent
-	}
-
-	return string(rawBody)
-}
-
 // extractHTMLBody extrai o corpo HTML do email
 func (c *Client) extractHTMLBody(msg *imap.Message) string {
 	if msg.BodyStructure == nil {
@@ -460,142 +410,41 @@ func (c *Client) extractHTMLBody(msg *imap.Message) string {
 	return string(body)
 }
 
-// extractLinks extrai links relevantes do corpo HTML
-func extractLinks(htmlBody string) []EmailLink {
+// extractLinks extrai links relevantes do corpo HTML usando o extrator escolhido.
+// Se o perfil da newsletter não encontrar nada (layout mudou), usa a heurística genérica.
+func extractLinks(extractor Extractor, htmlBody string) []EmailLink {
 	doc, err := goquery.NewDocumentFromReader(strings.NewReader(htmlBody))
 	if err != nil {
 		return []EmailLink{}
 	}
 
-	var links []EmailLink
-	position := 0
-	seenURLs := make(map[string]bool)
-
-	doc.Find("a[href]").Each(func(i int, s *goquery.Selection) {
-		href, exists := s.Attr("href")
-		if !exists || href == "" {
-			return
-		}
-
-		// Ignorar links irrelevantes
-		if isIgnorableLink(href) {
-			return
-		}
-
-		// Parse URL
-		parsedURL, err := url.Parse(href)
-		if err != nil {
-			return
-		}
-
-		// Apenas links HTTP/HTTPS
-		if parsedURL.Scheme != "http" && parsedURL.Scheme != "https" {
-			return
-		}
-
-		// Normalizar URL (remover parâmetros de tracking)
-		normalizedURL := normalizeURL(parsedURL)
-
-		// Evitar duplicatas usando URL normalizada
-		if seenURLs[normalizedURL] {
-			return
-		}
-		seenURLs[normalizedURL] = true
-
-		// Extrair título usando múltiplas estratégias
-		title := extractTitleFromLink(s, href, parsedURL)
-
-		// Validar título: deve ter pelo menos 20 caracteres e iniciar com letra maiúscula
-		if !isValidTitle(title) {
-			return
-		}
-
-		// Limitar tamanho do título
-		if len(title) > 200 {
-			title = title[:200] + "..."
-		}
-
-		// Tentar pegar descrição (próximo elemento <p>)
-		description := ""
-		parent := s.Parent()
-		next := parent.Next()
-		if next.Is("p") {
-			description = strings.TrimSpace(next.Text())
-			if len(description) > 300 {
-				description = description[:300] + "..."
-			}
+	links := extractor.Extract(doc)
+	if len(links) == 0 {
+		if _, generic := extractor.(genericExtractor); !generic {
+			log.Infof("Profile %s found no links, falling back to generic extraction", extractor.Name())
+			links = genericExtractor{}.Extract(doc)
 		}
+	}
 
-		links = append(links, EmailLink{
-			URL:         normalizedURL, // Usar URL normalizada
-			Title:       title,
-			Description: description,
-			Domain:      parsedURL.Hostname(),
-			Position:    position,
-		})
+	// Links sem imagem própria herdam a de outro <a> com a mesma URL (hero image)
+	attachImages(doc, links)
 
-		position++
-	})
+	// URLs soltas no texto (fora de <a>) também contam
+	links = mergeLinks(links, extractTextLinks(htmlText(doc)))
 
 	log.Infof("Extracted %d links from email", len(links))
 	return links
 }
 
-// normalizeURL remove parâmetros de tracking e normaliza a URL
-func normalizeURL(u *url.URL) string {
-	// Parâmetros de tracking a serem removidos
-	trackingParams := []string{
-		"utm_source", "utm_medium", "utm_campaign", "utm_term", "utm_content",
-		"ref", "source", "mc_cid", "mc_eid",
-		"fbclid", "gclid", "dclid",
-		"_ga", "_gl",
-		"oly_enc_id", "oly_anon_id",
-		"vero_id", "vero_conv",
-		"spm", "share_token",
-		"si", "feature",
-	}
-
-	// Copiar URL
-	normalized := *u
-
-	// Remover parâmetros de tracking
-	query := normalized.Query()
-	for _, param := range trackingParams {
-		query.Del(param)
-	}
-
-	// Se não sobrou nenhum parâmetro, remover o "?"
-	if len(query) == 0 {
-		normalized.RawQuery = ""
-	} else {
-		normalized.RawQuery = query.Encode()
-	}
-
-	// Remover fragmento (#)
-	normalized.Fragment = ""
-
-	// Remover barra final desnecessária
-	normalized.Path = strings.TrimSuffix(normalized.Path, "/")
-
-	return normalized.String()
-}
-
-// isValidTitle verifica se o título é válido:
-// - Deve ter pelo menos 20 caracteres
-// - Deve iniciar com letra maiúscula
-func isValidTitle(title string) bool {
-	// Verificar tamanho mínimo
-	if len(title) < 20 {
-		return false
-	}
-
-	// Pegar primeiro caractere (rune) do título
-	for _, r := range title {
-		// Verificar se é letra maiúscula
-		return unicode.IsUpper(r)
+// readListHeaders lê os cabeçalhos List-Id e List-Unsubscribe da mensagem bruta
+func readListHeaders(msg *Message, rawBody []byte) {
+	entity, err := message.Read(bytes.NewReader(rawBody))
+	if err != nil && entity == nil {
+		return
 	}
-
-	return false
+	msg.ListID = strings.TrimSpace(entity.Header.Get("List-Id"))
+	msg.ListUnsubscribe = strings.TrimSpace(entity.Header.Get("List-Unsubscribe"))
+	msg.ListUnsubscribePost = strings.TrimSpace(entity.Header.Get("List-Unsubscribe-Post"))
 }
 
 // isIgnorableLink filtra links irrelevantes
@@ -695,6 +544,11 @@ func extractTitleFromLink(s *goquery.Selection, href string, parsedURL *url.URL)
 			break
 		}
 
+		// Um ancestral com vários títulos e vários links já abrange outros itens
+		if parent.Find("h1, h2, h3, h4, strong").Length() > 1 && parent.Find("a[href]").Length() > 1 {
+			break
+		}
+
 		// Procurar h1, h2, h3, h4, strong dentro do contexto pai
 		parent.Find("h1, h2, h3, h4, strong").Each(func(j int, heading *goquery.Selection) {
 			if !isValidTitle(title) {
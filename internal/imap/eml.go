@@ -0,0 +1,77 @@
+package imap
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/emersion/go-message"
+	"github.com/emersion/go-message/mail"
+	"github.com/sirupsen/logrus"
+)
+
+// Extraction é o resultado da extração de links de um email, no formato
+// impresso pelo subcomando extract e guardado nos arquivos golden dos testes
+type Extraction struct {
+	Subject string      `json:"subject"`
+	From    string      `json:"from"`
+	ListID  string      `json:"list_id,omitempty"`
+	Profile string      `json:"profile"`
+	Error   string      `json:"error,omitempty"` // Falha ao interpretar o corpo MIME
+	Links   []EmailLink `json:"links"`
+}
+
+// SetLogLevel ajusta o nível de log do pacote (o subcomando extract usa Warn)
+func SetLogLevel(level logrus.Level) {
+	log.SetLevel(level)
+}
+
+// ParseEML lê um email bruto (arquivo .eml) e extrai os links exatamente como
+// na varredura IMAP, usando os perfis informados (nil = só os embutidos)
+func ParseEML(raw []byte, profiles *Registry) (*Message, error) {
+	entity, err := message.Read(bytes.NewReader(raw))
+	if err != nil && !message.IsUnknownCharset(err) {
+		return nil, fmt.Errorf("failed to parse email: %w", err)
+	}
+	header := mail.Header{Header: entity.Header}
+
+	msg := &Message{}
+	msg.MessageID, _ = header.MessageID()
+	msg.Subject, _ = header.Subject()
+	msg.Date, _ = header.Date()
+	msg.SnippetPreview = msg.Subject
+
+	// Mesmo formato do envelope IMAP: "Nome <email>" ou só o email
+	if from, err := header.AddressList("From"); err == nil && len(from) > 0 {
+		if from[0].Name != "" {
+			msg.From = fmt.Sprintf("%s <%s>", from[0].Name, from[0].Address)
+		} else {
+			msg.From = from[0].Address
+		}
+	}
+
+	if profiles == nil {
+		profiles = NewRegistry(nil)
+	}
+	profiles.parseBody(msg, raw)
+
+	return msg, nil
+}
+
+// Extraction resume a mensagem para inspeção dos links extraídos
+func (m *Message) Extraction() Extraction {
+	links := m.Links
+	if links == nil {
+		links = []EmailLink{}
+	}
+	extraction := Extraction{
+		Subject: m.Subject,
+		From:    m.From,
+		ListID:  m.ListID,
+		Profile: m.Profile,
+		Links:   links,
+	}
+	if m.BodyError != nil {
+		extraction.Error = m.BodyError.Error()
+	}
+	return extraction
+}
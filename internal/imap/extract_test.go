@@ -0,0 +1,126 @@
+package imap
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Regenerar os arquivos esperados: go test ./internal/imap -run TestExtractionGolden -update
+var update = flag.Bool("update", false, "rewrite testdata/*.golden.json with the current extraction")
+
+// TestExtractionGolden extrai os links de cada testdata/*.eml e compara com o
+// testdata/*.golden.json correspondente
+func TestExtractionGolden(t *testing.T) {
+	log.SetLevel(logrus.ErrorLevel)
+
+	files, err := filepath.Glob(filepath.Join("testdata", "*.eml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) == 0 {
+		t.Fatal("no .eml files in testdata")
+	}
+
+	for _, file := range files {
+		name := strings.TrimSuffix(filepath.Base(file), ".eml")
+		t.Run(name, func(t *testing.T) {
+			raw, err := os.ReadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			msg, err := ParseEML(raw, nil)
+			if err != nil {
+				t.Fatalf("ParseEML: %v", err)
+			}
+
+			got, err := json.MarshalIndent(msg.Extraction(), "", "  ")
+			if err != nil {
+				t.Fatal(err)
+			}
+			got = append(got, '\n')
+
+			golden := filepath.Join("testdata", name+".golden.json")
+			if *update {
+				if err := os.WriteFile(golden, got, 0644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("missing golden file (run with -update): %v", err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("extraction differs from %s (run with -update if the change is intended)\n%s", golden, diffLines(string(want), string(got)))
+			}
+		})
+	}
+}
+
+// diffLines mostra as linhas removidas (-) e acrescentadas (+) entre o esperado
+// e o obtido, alinhando pela maior subsequência comum
+func diffLines(want, got string) string {
+	a := strings.Split(want, "\n")
+	b := strings.Split(got, "\n")
+
+	// lcs[i][j] = tamanho da maior subsequência comum entre a[i:] e b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var out strings.Builder
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			i++
+			j++
+		case j < len(b) && (i == len(a) || lcs[i][j+1] >= lcs[i+1][j]):
+			out.WriteString("+ " + b[j] + "\n")
+			j++
+		default:
+			out.WriteString("- " + a[i] + "\n")
+			i++
+		}
+	}
+	return out.String()
+}
+
+// TestRetargetReclassifies cobre os rastreadores que só se resolvem online (bit.ly):
+// a classe é refeita com o domínio de destino
+func TestRetargetReclassifies(t *testing.T) {
+	raw := "Content-Type: text/html; charset=utf-8\r\nFrom: digest@example.com\r\nSubject: Digest\r\n\r\n" +
+		`<p><a href="https://bit.ly/3xYzAbC">The thread everyone is talking about this week</a></p>`
+	msg, err := ParseEML([]byte(raw), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(msg.Links) != 1 || msg.Links[0].Class != ClassArticle {
+		t.Fatalf("links = %+v", msg.Links)
+	}
+
+	link := msg.Links[0]
+	link.Retarget("https://x.com/someone/status/1", "x.com")
+	if link.Class != ClassSocial || link.Score >= msg.Links[0].Score {
+		t.Errorf("after Retarget: class=%s score=%v", link.Class, link.Score)
+	}
+}
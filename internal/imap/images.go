@@ -0,0 +1,132 @@
+package imap
+
+import (
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// minImageSize descarta ícones e pixels de rastreamento pelas dimensões declaradas
+const minImageSize = 40
+
+// ignoredImagePattern reconhece pixels de rastreamento, espaçadores e ícones pelo endereço
+var ignoredImagePattern = regexp.MustCompile(`(?i)(pixel|spacer|blank\.gif|transparent\.(gif|png)|beacon|/open(\.gif|\.png)?$|/track|/wf/open|icon|logo|badge|avatar|emoji|1x1)`)
+
+// hiddenImagePattern reconhece imagens escondidas ou de 1px pelo estilo inline
+var hiddenImagePattern = regexp.MustCompile(`(?i)(display\s*:\s*none|(width|height)\s*:\s*[0-2]px)`)
+
+// nearbyImage procura a imagem associada ao link: dentro do <a>, no mesmo bloco
+// (se o bloco não tiver links para outros destinos) ou no bloco anterior (hero image)
+func nearbyImage(s *goquery.Selection, container *goquery.Selection) (string, string) {
+	if src, alt, ok := firstUsableImage(s); ok {
+		return src, alt
+	}
+
+	href, _ := s.Attr("href")
+	target := normalizeHref(href)
+
+	for _, block := range []*goquery.Selection{container, container.Prev()} {
+		if block.Length() == 0 || !onlyLinksTo(block, target) {
+			continue
+		}
+		if src, alt, ok := firstUsableImage(block); ok {
+			return src, alt
+		}
+	}
+	return "", ""
+}
+
+// attachImages completa os links sem imagem com a de outro <a> para a mesma URL,
+// comum quando a newsletter repete o link na imagem e no título
+func attachImages(doc *goquery.Document, links []EmailLink) {
+	images := make(map[string][2]string)
+	doc.Find("a[href]").Each(func(i int, a *goquery.Selection) {
+		href, _ := a.Attr("href")
+		target := normalizeHref(href)
+		if target == "" {
+			return
+		}
+		if _, exists := images[target]; exists {
+			return
+		}
+		if src, alt, ok := firstUsableImage(a); ok {
+			images[target] = [2]string{src, alt}
+		}
+	})
+
+	for i := range links {
+		if links[i].ImageURL != "" {
+			continue
+		}
+		if image, ok := images[links[i].URL]; ok {
+			links[i].ImageURL = image[0]
+			if links[i].ImageAlt == "" {
+				links[i].ImageAlt = image[1]
+			}
+		}
+	}
+}
+
+// firstUsableImage devolve a primeira imagem aproveitável dentro da seleção
+func firstUsableImage(s *goquery.Selection) (src, alt string, ok bool) {
+	s.Find("img").EachWithBreak(func(i int, img *goquery.Selection) bool {
+		src, alt, ok = usableImage(img)
+		return !ok
+	})
+	return src, alt, ok
+}
+
+// usableImage valida a imagem: precisa ser HTTP(S) e não parecer pixel, espaçador ou ícone.
+// Imagens embutidas (cid:) e data: URIs são ignoradas.
+func usableImage(img *goquery.Selection) (string, string, bool) {
+	src := strings.TrimSpace(img.AttrOr("src", ""))
+	if src == "" {
+		src = strings.TrimSpace(img.AttrOr("data-src", ""))
+	}
+	if strings.HasPrefix(src, "//") {
+		src = "https:" + src
+	}
+
+	parsed, err := url.Parse(src)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return "", "", false
+	}
+
+	for _, attr := range []string{"width", "height"} {
+		value := strings.TrimSuffix(strings.TrimSpace(img.AttrOr(attr, "")), "px")
+		if size, err := strconv.Atoi(value); err == nil && size < minImageSize {
+			return "", "", false
+		}
+	}
+	if hiddenImagePattern.MatchString(img.AttrOr("style", "")) {
+		return "", "", false
+	}
+	if ignoredImagePattern.MatchString(parsed.Host + parsed.Path) {
+		return "", "", false
+	}
+
+	return src, cleanText(img.AttrOr("alt", "")), true
+}
+
+// onlyLinksTo verifica se todos os links do bloco apontam para o destino informado
+func onlyLinksTo(block *goquery.Selection, target string) bool {
+	only := true
+	block.Find("a[href]").EachWithBreak(func(i int, a *goquery.Selection) bool {
+		href, _ := a.Attr("href")
+		only = normalizeHref(href) == target
+		return only
+	})
+	return only
+}
+
+// normalizeHref normaliza o href como o linkCollector faz (vazio se não for HTTP(S))
+func normalizeHref(href string) string {
+	parsedURL, err := url.Parse(strings.TrimSpace(href))
+	if err != nil || (parsedURL.Scheme != "http" && parsedURL.Scheme != "https") {
+		return ""
+	}
+	return normalizeLinkURL(parsedURL)
+}
@@ -0,0 +1,269 @@
+package imap
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"mime/quotedprintable"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/emersion/go-message"
+	"github.com/emersion/go-message/charset"
+	"github.com/emersion/go-message/textproto"
+)
+
+// maxMIMEDepth limita o aninhamento de multiparts e mensagens encaminhadas
+const maxMIMEDepth = 12
+
+// metaCharsetPattern encontra o charset declarado no próprio HTML
+var metaCharsetPattern = regexp.MustCompile(`(?i)<meta[^>]+charset\s*=\s*["']?\s*([a-z0-9_.:-]+)`)
+
+// ParseError descreve a falha ao interpretar o corpo MIME de um email
+type ParseError struct {
+	Part        string // Caminho da parte ("2.1"); vazio = mensagem inteira
+	ContentType string
+	Reason      string // header, multipart, encoding, depth ou empty
+	Err         error
+}
+
+func (e *ParseError) Error() string {
+	where := "message"
+	if e.Part != "" {
+		where = "part " + e.Part
+	}
+	if e.ContentType != "" {
+		where += " (" + e.ContentType + ")"
+	}
+	return fmt.Sprintf("mime %s error in %s: %v", e.Reason, where, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// extractBodiesFromMIME percorre o email recursivamente (multiparts aninhados e
+// mensagens encaminhadas) e devolve a melhor versão HTML e texto, já
+// decodificadas e em UTF-8. O erro traz a primeira falha encontrada; partes
+// legíveis continuam sendo devolvidas junto com ele.
+func extractBodiesFromMIME(rawBody []byte) (htmlContent, textContent string, err error) {
+	r := bufio.NewReader(bytes.NewReader(rawBody))
+	header, headerErr := textproto.ReadHeader(r)
+	if headerErr != nil {
+		return "", "", &ParseError{Reason: "header", Err: headerErr}
+	}
+
+	w := &mimeWalker{}
+	htmlContent, textContent = w.walk(header, r, "", 0)
+
+	if w.err != nil {
+		return htmlContent, textContent, w.err
+	}
+	if htmlContent == "" && textContent == "" {
+		return "", "", &ParseError{Reason: "empty", Err: errors.New("no readable text/html or text/plain part")}
+	}
+	return htmlContent, textContent, nil
+}
+
+// mimeWalker guarda a primeira falha encontrada durante o percurso
+type mimeWalker struct {
+	err *ParseError
+}
+
+func (w *mimeWalker) fail(path, contentType, reason string, err error) {
+	log.Debugf("MIME %s error at part %q (%s): %v", reason, path, contentType, err)
+	if w.err == nil {
+		w.err = &ParseError{Part: path, ContentType: contentType, Reason: reason, Err: err}
+	}
+}
+
+// walk devolve o HTML e o texto da entidade. Em multipart/alternative vale a
+// última versão (a mais rica, pela RFC 2046); nos demais, a primeira encontrada.
+func (w *mimeWalker) walk(header textproto.Header, body io.Reader, path string, depth int) (htmlContent, textContent string) {
+	h := message.Header{Header: header}
+	mediaType, params, err := h.ContentType()
+	declared := header.Has("Content-Type") && err == nil
+	if mediaType == "" {
+		mediaType = "text/plain" // Padrão da RFC 2045
+	}
+
+	if depth > maxMIMEDepth {
+		w.fail(path, mediaType, "depth", fmt.Errorf("more than %d nested parts", maxMIMEDepth))
+		return "", ""
+	}
+
+	// Anexos não fazem parte do corpo, exceto emails encaminhados como anexo
+	if disposition, _, _ := h.ContentDisposition(); disposition == "attachment" && !strings.HasPrefix(mediaType, "message/") {
+		return "", ""
+	}
+
+	switch {
+	case strings.HasPrefix(mediaType, "multipart/"):
+		boundary := params["boundary"]
+		if boundary == "" {
+			w.fail(path, mediaType, "multipart", errors.New("missing boundary"))
+			return "", ""
+		}
+
+		alternative := mediaType == "multipart/alternative"
+		mr := textproto.NewMultipartReader(body, boundary)
+		for i := 1; ; i++ {
+			part, err := mr.NextPart()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				w.fail(childPath(path, i), mediaType, "multipart", err)
+				break
+			}
+
+			partHTML, partText := w.walk(part.Header, part, childPath(path, i), depth+1)
+			if partHTML != "" && (alternative || htmlContent == "") {
+				htmlContent = partHTML
+			}
+			if partText != "" && (alternative || textContent == "") {
+				textContent = partText
+			}
+		}
+		return htmlContent, textContent
+
+	case mediaType == "message/rfc822" || mediaType == "message/global":
+		// Email encaminhado: o conteúdo é outra mensagem completa
+		r := bufio.NewReader(w.transferDecode(h, body, path, mediaType))
+		inner, err := textproto.ReadHeader(r)
+		if err != nil {
+			w.fail(path, mediaType, "header", err)
+			return "", ""
+		}
+		return w.walk(inner, r, path, depth+1)
+
+	case mediaType == "text/html" || mediaType == "text/plain":
+		data, err := io.ReadAll(w.transferDecode(h, body, path, mediaType))
+		if err != nil {
+			// Base64/QP quebrado: aproveitar o que foi decodificado até o erro
+			w.fail(path, mediaType, "encoding", err)
+		}
+		text := toUTF8(data, params["charset"], mediaType == "text/html")
+		if strings.TrimSpace(text) == "" {
+			return "", ""
+		}
+
+		// Emails sem Content-Type que na verdade são HTML
+		if mediaType == "text/html" || (!declared && looksLikeHTML(text)) {
+			return text, ""
+		}
+		return "", text
+	}
+
+	return "", ""
+}
+
+// transferDecode aplica o Content-Transfer-Encoding; codificações desconhecidas
+// são lidas como estão
+func (w *mimeWalker) transferDecode(h message.Header, body io.Reader, path, mediaType string) io.Reader {
+	switch encoding := strings.ToLower(strings.TrimSpace(h.Get("Content-Transfer-Encoding"))); encoding {
+	case "quoted-printable":
+		return quotedprintable.NewReader(body)
+	case "base64":
+		return base64.NewDecoder(base64.StdEncoding, &base64Cleaner{r: body})
+	case "", "7bit", "8bit", "binary":
+		return body
+	default:
+		log.Debugf("Unknown transfer encoding %q at part %q (%s), reading as is", encoding, path, mediaType)
+		return body
+	}
+}
+
+// base64Cleaner remove quebras de linha e outros bytes fora do alfabeto base64,
+// comuns em emails gerados por ferramentas descuidadas
+type base64Cleaner struct {
+	r io.Reader
+}
+
+func (c *base64Cleaner) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	kept := 0
+	for _, b := range p[:n] {
+		if (b >= 'A' && b <= 'Z') || (b >= 'a' && b <= 'z') || (b >= '0' && b <= '9') || b == '+' || b == '/' || b == '=' {
+			p[kept] = b
+			kept++
+		}
+	}
+	return kept, err
+}
+
+// toUTF8 converte o texto para UTF-8 tentando, em ordem: o charset declarado,
+// o <meta charset> do HTML, UTF-8 válido e, por fim, windows-1252 (superconjunto
+// do latin-1, o charset errado mais comum em emails)
+func toUTF8(data []byte, declared string, isHTML bool) string {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+	labels := []string{declared}
+	if isHTML {
+		if match := metaCharsetPattern.FindSubmatch(data); match != nil {
+			labels = append(labels, string(match[1]))
+		}
+	}
+
+	for _, label := range labels {
+		label = strings.ToLower(strings.TrimSpace(label))
+		switch label {
+		case "":
+			continue
+		case "utf-8", "utf8", "us-ascii", "ascii":
+			// Charset "UTF-8" com bytes inválidos é mentira: seguir para a detecção
+			if utf8.Valid(data) {
+				return string(data)
+			}
+			continue
+		}
+
+		if converted, ok := convertCharset(label, data); ok {
+			return converted
+		}
+		log.Debugf("Unknown charset %s, falling back to detection", strconv.Quote(label))
+	}
+
+	if utf8.Valid(data) {
+		return string(data)
+	}
+	if converted, ok := convertCharset("windows-1252", data); ok {
+		return converted
+	}
+	return strings.ToValidUTF8(string(data), "�")
+}
+
+// convertCharset decodifica data do charset informado para UTF-8
+func convertCharset(label string, data []byte) (string, bool) {
+	r, err := charset.Reader(label, bytes.NewReader(data))
+	if err != nil {
+		return "", false
+	}
+	converted, err := io.ReadAll(r)
+	if err != nil {
+		return "", false
+	}
+	return string(converted), true
+}
+
+// looksLikeHTML detecta corpos HTML enviados sem Content-Type
+func looksLikeHTML(text string) bool {
+	start := strings.ToLower(strings.TrimSpace(text))
+	if len(start) > 512 {
+		start = start[:512]
+	}
+	return strings.HasPrefix(start, "<!doctype html") || strings.Contains(start, "<html") || strings.Contains(start, "<body")
+}
+
+// childPath monta o caminho da parte no estilo IMAP ("1", "2.1")
+func childPath(parent string, index int) string {
+	if parent == "" {
+		return strconv.Itoa(index)
+	}
+	return parent + "." + strconv.Itoa(index)
+}
@@ -0,0 +1,227 @@
+package imap
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+// bareURLPattern encontra URLs soltas no texto
+var bareURLPattern = regexp.MustCompile(`https?://[^\s<>"'\x60]+`)
+
+// markdownLinkPattern encontra links no formato [Título](url)
+var markdownLinkPattern = regexp.MustCompile(`\[([^\]]+)\]\((https?://[^\s)]+)\)`)
+
+// bulletPrefix remove marcadores de lista ("-", "*", "•", "1.", "2)", "#")
+var bulletPrefix = regexp.MustCompile(`^\s*(?:[-*•·▪►>#]+|\d{1,3}[.)])\s*`)
+
+// maxDescriptionLines limita quantas linhas seguintes viram descrição
+const maxDescriptionLines = 3
+
+// textLine é uma linha do email com as URLs encontradas nela
+type textLine struct {
+	text   string
+	urls   []string
+	titles map[string]string // Títulos explícitos de links [Título](url)
+}
+
+// extractTextLinks extrai links de um corpo text/plain. O título vem do texto
+// antes da URL na mesma linha ou da linha/bullet anterior; a descrição, das
+// linhas seguintes até a próxima linha em branco ou com outra URL.
+func extractTextLinks(text string) []EmailLink {
+	lines := splitTextLines(text)
+	collector := newLinkCollector()
+
+	for i, line := range lines {
+		for _, rawURL := range line.urls {
+			parsedURL, normalizedURL, ok := collector.accept(rawURL)
+			if !ok {
+				continue
+			}
+
+			title := line.titles[rawURL]
+			if title == "" {
+				title = textTitle(lines, i, rawURL)
+
+				// Contexto fraco ("Veja também:"): o slug da URL pode render um título melhor
+				if urlTitle := extractTitleFromURL(rawURL); titleQuality(urlTitle, rawURL) > titleQuality(title, rawURL) {
+					title = urlTitle
+				}
+			}
+			if titleQuality(title, rawURL) == 0 {
+				continue
+			}
+
+			description := textDescription(lines, i, rawURL, title)
+			ctx := linkContext{text: cleanText(line.text + " " + description)}
+			if i > 0 {
+				ctx.text = cleanText(lines[i-1].text + " " + ctx.text)
+			}
+
+			collector.add(parsedURL, normalizedURL, title, description, ctx)
+		}
+	}
+
+	return collector.result()
+}
+
+// splitTextLines separa o texto em linhas e localiza as URLs de cada uma
+func splitTextLines(text string) []textLine {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = strings.ReplaceAll(text, "\r", "\n")
+
+	var lines []textLine
+	for _, raw := range strings.Split(text, "\n") {
+		line := textLine{text: strings.TrimSpace(raw)}
+
+		// [Título](url): guardar o título e deixar só a URL no texto
+		for _, match := range markdownLinkPattern.FindAllStringSubmatch(line.text, -1) {
+			if line.titles == nil {
+				line.titles = make(map[string]string)
+			}
+			line.titles[match[2]] = cleanText(match[1])
+		}
+		line.text = markdownLinkPattern.ReplaceAllString(line.text, "$2")
+
+		for _, match := range bareURLPattern.FindAllString(line.text, -1) {
+			line.urls = append(line.urls, trimURL(match))
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// trimURL remove pontuação que costuma grudar no fim das URLs em texto corrido
+func trimURL(u string) string {
+	for {
+		trimmed := strings.TrimRight(u, ".,;:!?*")
+		// Parêntese final só pertence à URL se houver um "(" correspondente
+		if strings.HasSuffix(trimmed, ")") && strings.Count(trimmed, "(") < strings.Count(trimmed, ")") {
+			trimmed = trimmed[:len(trimmed)-1]
+		}
+		if strings.HasSuffix(trimmed, "]") && !strings.Contains(trimmed, "[") {
+			trimmed = trimmed[:len(trimmed)-1]
+		}
+		if trimmed == u {
+			return u
+		}
+		u = trimmed
+	}
+}
+
+// textTitle procura o título antes da URL na mesma linha ou na linha anterior
+func textTitle(lines []textLine, index int, rawURL string) string {
+	line := lines[index].text
+	if pos := strings.Index(line, rawURL); pos > 0 {
+		if title := cleanTextTitle(line[:pos]); title != "" {
+			return title
+		}
+	}
+
+	// Linha anterior não vazia, desde que não tenha outra URL (senão é de outro item)
+	for j := index - 1; j >= 0 && j >= index-2; j-- {
+		prev := lines[j]
+		if prev.text == "" {
+			continue
+		}
+		if len(prev.urls) > 0 {
+			break
+		}
+		return cleanTextTitle(prev.text)
+	}
+
+	return ""
+}
+
+// textDescription junta o texto depois da URL e as linhas seguintes
+func textDescription(lines []textLine, index int, rawURL, title string) string {
+	var parts []string
+
+	line := lines[index].text
+	if pos := strings.Index(line, rawURL); pos >= 0 {
+		if rest := cleanTextTitle(line[pos+len(rawURL):]); rest != "" && len(lines[index].urls) == 1 {
+			parts = append(parts, rest)
+		}
+	}
+
+	for j := index + 1; j < len(lines) && j <= index+maxDescriptionLines; j++ {
+		next := lines[j]
+		if next.text == "" || len(next.urls) > 0 {
+			break
+		}
+		// Uma linha de bullet começa outro item
+		if bulletPrefix.MatchString(next.text) && !strings.HasPrefix(next.text, ">") {
+			break
+		}
+		parts = append(parts, next.text)
+	}
+
+	description := cleanText(strings.Join(parts, " "))
+	if description == title || strings.HasPrefix(title, description) {
+		return ""
+	}
+	return description
+}
+
+// cleanTextTitle remove marcadores de lista e separadores das pontas
+func cleanTextTitle(s string) string {
+	s = bulletPrefix.ReplaceAllString(s, "")
+	s = strings.Trim(strings.TrimSpace(s), "-–—:;.,|()[]<>*_ ")
+	return cleanText(s)
+}
+
+// htmlText converte o documento em texto preservando quebras de bloco e
+// ignorando o conteúdo de <a>, para encontrar URLs soltas em nós de texto
+func htmlText(doc *goquery.Document) string {
+	var b strings.Builder
+
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		switch n.Type {
+		case html.TextNode:
+			b.WriteString(n.Data)
+			return
+		case html.ElementNode:
+			switch n.Data {
+			case "a", "script", "style", "head", "title":
+				return
+			case "br", "p", "div", "li", "tr", "td", "h1", "h2", "h3", "h4", "h5", "h6", "table", "ul", "ol", "blockquote", "pre":
+				defer b.WriteString("\n")
+				b.WriteString("\n")
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+
+	for _, n := range doc.Nodes {
+		walk(n)
+	}
+	return b.String()
+}
+
+// mergeLinks acrescenta a links os extras com URLs ainda não vistas; quando a
+// URL já existe sem descrição, aproveita a descrição do extra
+func mergeLinks(links, extra []EmailLink) []EmailLink {
+	index := make(map[string]int, len(links))
+	for i, link := range links {
+		index[link.URL] = i
+	}
+
+	for _, link := range extra {
+		if i, exists := index[link.URL]; exists {
+			if links[i].Description == "" {
+				links[i].Description = link.Description
+			}
+			continue
+		}
+		link.Position = len(links)
+		index[link.URL] = len(links)
+		links = append(links, link)
+	}
+
+	return links
+}
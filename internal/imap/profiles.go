@@ -0,0 +1,387 @@
+package imap
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/andybalholm/cascadia"
+	"github.com/gustavoflandal/gmail-scanner/internal/resolver"
+	"github.com/gustavoflandal/gmail-scanner/internal/urlnorm"
+)
+
+// Extractor extrai os links de um email já parseado como HTML
+type Extractor interface {
+	Name() string
+	Extract(doc *goquery.Document) []EmailLink
+}
+
+// Profile descreve de forma declarativa o layout de uma newsletter.
+// O perfil é escolhido pelo remetente ou pelo cabeçalho List-Id.
+type Profile struct {
+	Name    string   `json:"name"`
+	Senders []string `json:"senders,omitempty"`  // "dan@tldr.tech", "@tldr.tech" ou "tldr.tech"
+	ListIDs []string `json:"list_ids,omitempty"` // "golangweekly.cooperpress.com"
+
+	Item        string `json:"item"`                  // Seletor CSS de cada item da newsletter
+	Link        string `json:"link,omitempty"`        // Seletor do <a> dentro do item (padrão: primeiro a[href])
+	Title       string `json:"title,omitempty"`       // Seletor do título (padrão: texto do link)
+	Description string `json:"description,omitempty"` // Seletor da descrição (padrão: texto do item sem o título)
+}
+
+// builtinProfiles cobre newsletters populares
+var builtinProfiles = []Profile{
+	{
+		Name:    "TLDR",
+		Senders: []string{"tldrnewsletter.com", "tldr.tech"},
+		Item:    "div.text-block:has(a[href] strong)",
+		Link:    "a[href]:has(strong)",
+		Title:   "strong",
+	},
+	{
+		Name:        "Medium Daily Digest",
+		Senders:     []string{"noreply@medium.com"},
+		Item:        "a[href]:has(h2)",
+		Title:       "h2",
+		Description: "h3",
+	},
+	{
+		Name:    "Substack",
+		Senders: []string{"substack.com"},
+		ListIDs: []string{"substack.com"},
+		Item:    ".post .body p:has(a[href]), .post .body li:has(a[href]), .markup p:has(a[href]), .markup li:has(a[href])",
+	},
+	{
+		Name:    "Hacker Newsletter",
+		Senders: []string{"hackernewsletter.com"},
+		ListIDs: []string{"hackernewsletter.com"},
+		Item:    "p:has(a[href]), li:has(a[href])",
+	},
+	{
+		Name:        "Golang Weekly",
+		Senders:     []string{"golangweekly.com"},
+		ListIDs:     []string{"golangweekly.com", "golangweekly.cooperpress.com"},
+		Item:        "table.el-item, .el-item",
+		Link:        ".mainlink a[href]",
+		Title:       ".mainlink a[href]",
+		Description: "p.desc, .desc",
+	},
+}
+
+// Registry escolhe o extrator de cada email. Perfis do usuário têm prioridade
+// sobre os embutidos; sem perfil compatível, vale a heurística genérica.
+type Registry struct {
+	profiles []Profile
+}
+
+// NewRegistry cria o registro com os perfis do usuário seguidos dos embutidos
+func NewRegistry(userProfiles []Profile) *Registry {
+	profiles := make([]Profile, 0, len(userProfiles)+len(builtinProfiles))
+	profiles = append(profiles, userProfiles...)
+	profiles = append(profiles, builtinProfiles...)
+	return &Registry{profiles: profiles}
+}
+
+// LoadProfiles lê perfis de um arquivo JSON (lista de Profile)
+func LoadProfiles(path string) ([]Profile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read profiles file: %w", err)
+	}
+
+	var profiles []Profile
+	if err := json.Unmarshal(data, &profiles); err != nil {
+		return nil, fmt.Errorf("failed to parse profiles file: %w", err)
+	}
+
+	for i, profile := range profiles {
+		if err := profile.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid profile %d (%s): %w", i, profile.Name, err)
+		}
+	}
+
+	return profiles, nil
+}
+
+// Validate verifica se o perfil é utilizável
+func (p Profile) Validate() error {
+	if strings.TrimSpace(p.Name) == "" {
+		return fmt.Errorf("name is required")
+	}
+	if len(p.Senders) == 0 && len(p.ListIDs) == 0 {
+		return fmt.Errorf("at least one sender or list id is required")
+	}
+	if strings.TrimSpace(p.Item) == "" {
+		return fmt.Errorf("item selector is required")
+	}
+
+	// O goquery ignora seletores inválidos em silêncio; compilar para reportar o erro
+	for _, selector := range []string{p.Item, p.Link, p.Title, p.Description} {
+		if selector == "" {
+			continue
+		}
+		if _, err := cascadia.ParseGroup(selector); err != nil {
+			return fmt.Errorf("invalid selector %q: %w", selector, err)
+		}
+	}
+
+	return nil
+}
+
+// ExtractorFor retorna o extrator para o remetente e List-Id informados
+func (r *Registry) ExtractorFor(from, listID string) Extractor {
+	if r != nil {
+		address := senderAddress(from)
+		id := listIDValue(listID)
+		for i := range r.profiles {
+			if r.profiles[i].matches(address, id) {
+				return profileExtractor{profile: &r.profiles[i]}
+			}
+		}
+	}
+	return genericExtractor{}
+}
+
+// matches verifica se o perfil vale para o remetente ou List-Id
+func (p *Profile) matches(address, listID string) bool {
+	if address != "" {
+		at := strings.LastIndex(address, "@")
+		domain := address[at+1:]
+		for _, sender := range p.Senders {
+			sender = strings.ToLower(strings.TrimSpace(sender))
+			switch {
+			case sender == "":
+				continue
+			case strings.HasPrefix(sender, "@"):
+				if hostMatches(domain, sender[1:]) {
+					return true
+				}
+			case strings.Contains(sender, "@"):
+				if address == sender {
+					return true
+				}
+			default:
+				if hostMatches(domain, sender) {
+					return true
+				}
+			}
+		}
+	}
+
+	if listID != "" {
+		for _, id := range p.ListIDs {
+			id = strings.ToLower(strings.TrimSpace(id))
+			if id != "" && hostMatches(listID, id) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// hostMatches compara domínios respeitando a fronteira de rótulo (sub.example.com casa example.com)
+func hostMatches(host, domain string) bool {
+	return host == domain || strings.HasSuffix(host, "."+domain)
+}
+
+// senderAddress extrai o endereço de "Nome <email>" em minúsculas
+func senderAddress(from string) string {
+	if from == "" {
+		return ""
+	}
+	if addr, err := mail.ParseAddress(from); err == nil {
+		return strings.ToLower(addr.Address)
+	}
+	if start, end := strings.LastIndex(from, "<"), strings.LastIndex(from, ">"); start >= 0 && end > start {
+		return strings.ToLower(strings.TrimSpace(from[start+1 : end]))
+	}
+	return strings.ToLower(strings.TrimSpace(from))
+}
+
+// listIDValue extrai o identificador de "Nome <id.example.com>"
+func listIDValue(listID string) string {
+	listID = strings.TrimSpace(listID)
+	if start, end := strings.LastIndex(listID, "<"), strings.LastIndex(listID, ">"); start >= 0 && end > start {
+		listID = listID[start+1 : end]
+	}
+	return strings.ToLower(strings.TrimSpace(listID))
+}
+
+// profileExtractor aplica um Profile declarativo
+type profileExtractor struct {
+	profile *Profile
+}
+
+// Name retorna o nome do perfil
+func (e profileExtractor) Name() string { return e.profile.Name }
+
+// Extract aplica os seletores do perfil a cada item da newsletter
+func (e profileExtractor) Extract(doc *goquery.Document) []EmailLink {
+	p := e.profile
+	collector := newLinkCollector()
+
+	doc.Find(p.Item).Each(func(i int, item *goquery.Selection) {
+		var link *goquery.Selection
+		switch {
+		case p.Link != "":
+			link = item.Find(p.Link).First()
+		case item.Is("a[href]"):
+			link = item
+		default:
+			link = item.Find("a[href]").First()
+		}
+		if link.Length() == 0 {
+			return
+		}
+
+		href, _ := link.Attr("href")
+		parsedURL, normalizedURL, ok := collector.accept(href)
+		if !ok {
+			return
+		}
+
+		title := cleanText(link.Text())
+		if p.Title != "" {
+			if t := cleanText(item.Find(p.Title).First().Text()); t != "" {
+				title = t
+			}
+		}
+		if title == "" || strings.HasPrefix(title, "http://") || strings.HasPrefix(title, "https://") {
+			return
+		}
+
+		var description string
+		if p.Description != "" {
+			description = cleanText(item.Find(p.Description).First().Text())
+		} else {
+			description = cleanText(item.Text())
+		}
+		description = strings.TrimSpace(strings.TrimPrefix(description, title))
+		description = strings.TrimLeft(description, "-–—:| ")
+
+		collector.add(parsedURL, normalizedURL, title, description, htmlLinkContext(link))
+	})
+
+	return collector.result()
+}
+
+// genericExtractor é a heurística padrão: título em h1–h4/strong dos ancestrais
+// e descrição no <p> seguinte
+type genericExtractor struct{}
+
+// Name retorna o nome do extrator genérico
+func (genericExtractor) Name() string { return "generic" }
+
+// Extract aplica a heurística genérica a todos os links do email
+func (genericExtractor) Extract(doc *goquery.Document) []EmailLink {
+	collector := newLinkCollector()
+
+	doc.Find("a[href]").Each(func(i int, s *goquery.Selection) {
+		href, _ := s.Attr("href")
+		parsedURL, normalizedURL, ok := collector.accept(href)
+		if !ok {
+			return
+		}
+
+		// Extrair título usando múltiplas estratégias
+		title := extractTitleFromLink(s, href, parsedURL)
+
+		// Sem título aproveitável não há o que salvar; a qualidade entra no score
+		if titleQuality(title, href) == 0 {
+			return
+		}
+
+		// Tentar pegar descrição (próximo elemento <p>)
+		description := ""
+		next := s.Parent().Next()
+		if next.Is("p") {
+			description = strings.TrimSpace(next.Text())
+		}
+
+		collector.add(parsedURL, normalizedURL, title, description, htmlLinkContext(s))
+	})
+
+	return collector.result()
+}
+
+// linkCollector aplica os filtros comuns a todos os extratores e evita duplicatas
+type linkCollector struct {
+	links    []EmailLink
+	contexts []linkContext
+	seen     map[string]bool
+}
+
+func newLinkCollector() *linkCollector {
+	return &linkCollector{seen: make(map[string]bool)}
+}
+
+// accept valida e normaliza o href; retorna false para links irrelevantes ou já vistos
+func (c *linkCollector) accept(href string) (*url.URL, string, bool) {
+	if href == "" || isIgnorableLink(href) {
+		return nil, "", false
+	}
+
+	parsedURL, err := url.Parse(href)
+	if err != nil {
+		return nil, "", false
+	}
+
+	// Apenas links HTTP/HTTPS
+	if parsedURL.Scheme != "http" && parsedURL.Scheme != "https" {
+		return nil, "", false
+	}
+
+	normalizedURL := normalizeLinkURL(parsedURL)
+	if c.seen[normalizedURL] {
+		return nil, "", false
+	}
+	c.seen[normalizedURL] = true
+
+	return parsedURL, normalizedURL, true
+}
+
+// normalizeLinkURL remove parâmetros de tracking. Links de redirecionadores
+// ficam intactos para que o resolver consiga decodificá-los depois.
+func normalizeLinkURL(parsedURL *url.URL) string {
+	if resolver.IsTracker(parsedURL) {
+		return parsedURL.String()
+	}
+	return urlnorm.Normalize(parsedURL)
+}
+
+// add registra o link limitando o tamanho de título e descrição
+func (c *linkCollector) add(parsedURL *url.URL, normalizedURL, title, description string, ctx linkContext) {
+	if len(title) > 200 {
+		title = title[:200] + "..."
+	}
+	if len(description) > 300 {
+		description = description[:300] + "..."
+	}
+
+	c.links = append(c.links, EmailLink{
+		URL:         normalizedURL,
+		Title:       title,
+		Description: description,
+		Domain:      parsedURL.Hostname(),
+		Position:    len(c.links),
+		ImageURL:    ctx.imageURL,
+		ImageAlt:    ctx.imageAlt,
+	})
+	c.contexts = append(c.contexts, ctx)
+}
+
+// result classifica e devolve os links coletados
+func (c *linkCollector) result() []EmailLink {
+	classifyLinks(c.links, c.contexts)
+	return c.links
+}
+
+// cleanText colapsa espaços e quebras de linha
+func cleanText(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,364 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/gustavoflandal/gmail-scanner/internal/database"
+	"github.com/gustavoflandal/gmail-scanner/internal/urlnorm"
+	"github.com/sirupsen/logrus"
+)
+
+var log = logrus.New()
+
+// Formatos de importação suportados
+const (
+	FormatNetscape   = "netscape"
+	FormatPocket     = "pocket"
+	FormatInstapaper = "instapaper"
+	FormatRaindrop   = "raindrop"
+)
+
+// Entry é um link lido de um arquivo de exportação de outra ferramenta
+type Entry struct {
+	Line        int // Posição no arquivo (linha do CSV ou ordem do link no HTML)
+	URL         string
+	Title       string
+	Description string
+	Folder      string
+	Tags        []string
+	AddedAt     time.Time
+	Archived    bool
+	Starred     bool
+}
+
+// ReportEntry descreve o resultado de uma entrada na importação
+type ReportEntry struct {
+	Line   int    `json:"line"`
+	URL    string `json:"url"`
+	Title  string `json:"title,omitempty"`
+	ID     int64  `json:"id,omitempty"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// Report resume a importação: artigos criados, duplicados e entradas inválidas
+type Report struct {
+	Source     string        `json:"source"`
+	Total      int           `json:"total"`
+	Created    []ReportEntry `json:"created"`
+	Duplicates []ReportEntry `json:"duplicates"`
+	Invalid    []ReportEntry `json:"invalid"`
+}
+
+// IsValidFormat verifica se o formato é suportado
+func IsValidFormat(format string) bool {
+	switch format {
+	case FormatNetscape, FormatPocket, FormatInstapaper, FormatRaindrop:
+		return true
+	}
+	return false
+}
+
+// Parse lê as entradas do arquivo no formato informado
+func Parse(format string, r io.Reader) ([]Entry, error) {
+	switch format {
+	case FormatNetscape:
+		return parseBookmarksHTML(r)
+	case FormatPocket:
+		// Pocket exportava HTML (ril_export.html) e hoje exporta CSV
+		br := bufio.NewReader(r)
+		head, _ := br.Peek(512)
+		if bytes.HasPrefix(bytes.TrimSpace(head), []byte("<")) {
+			return parseBookmarksHTML(br)
+		}
+		return parseCSV(br, pocketColumns)
+	case FormatInstapaper:
+		return parseCSV(r, instapaperColumns)
+	case FormatRaindrop:
+		return parseCSV(r, raindropColumns)
+	}
+	return nil, fmt.Errorf("unsupported import format: %s", format)
+}
+
+// Import grava as entradas como artigos, usando a mesma normalização de URL
+// da varredura para deduplicar. Newsletter recebe "import:<formato>".
+func Import(store *database.Database, format string, entries []Entry) (*Report, error) {
+	source := "import:" + format
+	report := &Report{
+		Source:     source,
+		Total:      len(entries),
+		Created:    []ReportEntry{},
+		Duplicates: []ReportEntry{},
+		Invalid:    []ReportEntry{},
+	}
+	seen := make(map[string]bool)
+
+	for _, entry := range entries {
+		item := ReportEntry{Line: entry.Line, URL: entry.URL, Title: entry.Title}
+
+		parsedURL, err := url.Parse(strings.TrimSpace(entry.URL))
+		if err != nil || (parsedURL.Scheme != "http" && parsedURL.Scheme != "https") || parsedURL.Host == "" {
+			item.Reason = "URL inválida"
+			report.Invalid = append(report.Invalid, item)
+			continue
+		}
+
+		normalizedURL := urlnorm.Normalize(parsedURL)
+		item.URL = normalizedURL
+		if seen[normalizedURL] {
+			item.Reason = "duplicado no arquivo"
+			report.Duplicates = append(report.Duplicates, item)
+			continue
+		}
+		seen[normalizedURL] = true
+
+		title := strings.TrimSpace(entry.Title)
+		if title == "" {
+			title = normalizedURL
+		}
+
+		emailDate := ""
+		if !entry.AddedAt.IsZero() {
+			emailDate = entry.AddedAt.UTC().Format(time.RFC3339)
+		}
+
+		article := &database.Article{
+			URL:         normalizedURL,
+			Title:       title,
+			Description: strings.TrimSpace(entry.Description),
+			Domain:      parsedURL.Hostname(),
+			Newsletter:  source,
+			EmailDate:   emailDate,
+			Folder:      entry.Folder,
+		}
+
+		if err := store.IndexArticle(article); err != nil {
+			return report, err
+		}
+
+		if article.ID == 0 {
+			item.Reason = "já existe no banco"
+			report.Duplicates = append(report.Duplicates, item)
+			continue
+		}
+
+		item.ID = article.ID
+		report.Created = append(report.Created, item)
+
+		if len(entry.Tags) > 0 {
+			if err := store.AddTags([]int64{article.ID}, entry.Tags); err != nil {
+				log.Warnf("Failed to tag imported article %d: %v", article.ID, err)
+			}
+		}
+		if entry.Archived {
+			if _, err := store.SetArticlesStatus([]int64{article.ID}, database.StatusArchived); err != nil {
+				log.Warnf("Failed to archive imported article %d: %v", article.ID, err)
+			}
+		}
+		if entry.Starred {
+			if _, err := store.SetArticlesStarred([]int64{article.ID}, true); err != nil {
+				log.Warnf("Failed to star imported article %d: %v", article.ID, err)
+			}
+		}
+	}
+
+	log.Infof("Import %s: %d created, %d duplicates, %d invalid",
+		source, len(report.Created), len(report.Duplicates), len(report.Invalid))
+	return report, nil
+}
+
+// ==================== HTML (Netscape / Pocket) ====================
+
+// parseBookmarksHTML lê o formato Netscape (navegadores, Raindrop, Pinboard)
+// e o HTML antigo do Pocket, que usam <a> com atributos de data e tags
+func parseBookmarksHTML(r io.Reader) ([]Entry, error) {
+	doc, err := goquery.NewDocumentFromReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse bookmarks HTML: %w", err)
+	}
+
+	var entries []Entry
+	doc.Find("a[href]").Each(func(i int, s *goquery.Selection) {
+		href, _ := s.Attr("href")
+		entry := Entry{
+			Line:   i + 1,
+			URL:    href,
+			Title:  strings.TrimSpace(s.Text()),
+			Folder: bookmarkFolder(s),
+		}
+
+		for _, attr := range []string{"add_date", "time_added"} {
+			if value, ok := s.Attr(attr); ok {
+				entry.AddedAt = parseTime(value)
+				break
+			}
+		}
+		if tags, ok := s.Attr("tags"); ok {
+			entry.Tags = splitList(tags, ",")
+		}
+
+		// Descrição do Netscape fica no <DD> logo após o <DT> do link
+		if dd := s.Closest("dt").Next(); dd.Is("dd") {
+			entry.Description = strings.TrimSpace(dd.Contents().First().Text())
+		}
+
+		// Pocket separa "Unread" e "Read Archive" em seções <h1>
+		if strings.Contains(strings.ToLower(entry.Folder), "archive") {
+			entry.Archived = true
+		}
+
+		entries = append(entries, entry)
+	})
+
+	return entries, nil
+}
+
+// bookmarkFolder descobre a pasta do link: o <H3> que antecede a <DL> (Netscape)
+// ou o <h1> que antecede a <ul> (Pocket)
+func bookmarkFolder(s *goquery.Selection) string {
+	if dl := s.Closest("dl"); dl.Length() > 0 {
+		if h3 := dl.PrevAllFiltered("h3").First(); h3.Length() > 0 {
+			return strings.TrimSpace(h3.Text())
+		}
+	}
+	if ul := s.Closest("ul"); ul.Length() > 0 {
+		if h1 := ul.PrevAllFiltered("h1").First(); h1.Length() > 0 {
+			return strings.TrimSpace(h1.Text())
+		}
+	}
+	return ""
+}
+
+// ==================== CSV (Pocket / Instapaper / Raindrop) ====================
+
+// csvColumns mapeia os campos de Entry para os nomes de coluna de cada ferramenta
+type csvColumns struct {
+	url, title, description, folder, tags, added, status, favorite string
+	tagSeparator                                                   string
+}
+
+var (
+	// title,url,time_added,tags,status
+	pocketColumns = csvColumns{url: "url", title: "title", tags: "tags", added: "time_added", status: "status", tagSeparator: "|"}
+	// URL,Title,Selection,Folder,Timestamp,Tags
+	instapaperColumns = csvColumns{url: "url", title: "title", description: "selection", folder: "folder", added: "timestamp", tags: "tags", tagSeparator: ","}
+	// id,title,note,excerpt,url,folder,tags,created,cover,highlights,favorite
+	raindropColumns = csvColumns{url: "url", title: "title", description: "excerpt", folder: "folder", tags: "tags", added: "created", favorite: "favorite", tagSeparator: ","}
+)
+
+// parseCSV lê um CSV com cabeçalho, localizando as colunas pelo nome
+func parseCSV(r io.Reader, cols csvColumns) ([]Entry, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read csv header: %w", err)
+	}
+
+	index := make(map[string]int)
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		index[name] = i
+	}
+
+	if _, ok := index[cols.url]; !ok {
+		return nil, fmt.Errorf("csv has no %q column", cols.url)
+	}
+
+	var entries []Entry
+	line := 1
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		line++
+		if err != nil {
+			return nil, fmt.Errorf("failed to read csv line %d: %w", line, err)
+		}
+
+		field := func(name string) string {
+			if name == "" {
+				return ""
+			}
+			if i, ok := index[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		entry := Entry{
+			Line:        line,
+			URL:         field(cols.url),
+			Title:       field(cols.title),
+			Description: field(cols.description),
+			Folder:      field(cols.folder),
+			Tags:        parseTagField(field(cols.tags), cols.tagSeparator),
+			AddedAt:     parseTime(field(cols.added)),
+		}
+
+		status := strings.ToLower(field(cols.status))
+		entry.Archived = status == "archive" || status == "archived" ||
+			strings.EqualFold(entry.Folder, "archive")
+		entry.Starred, _ = strconv.ParseBool(field(cols.favorite))
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// parseTagField aceita listas separadas (a,b ou a|b) e arrays JSON (["a","b"]),
+// usados nas exportações mais recentes do Instapaper
+func parseTagField(value, separator string) []string {
+	var items []string
+	if !strings.HasPrefix(value, "[") || json.Unmarshal([]byte(value), &items) != nil {
+		items = splitList(value, separator)
+	}
+
+	// Tags não podem ter vírgula (ver database.ValidateTags): "a,b" vira duas
+	var tags []string
+	for _, item := range items {
+		tags = append(tags, splitList(item, ",")...)
+	}
+	return tags
+}
+
+// splitList separa uma lista, descartando itens vazios
+func splitList(value, separator string) []string {
+	var items []string
+	for _, item := range strings.Split(value, separator) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// parseTime aceita timestamp Unix (segundos) ou datas ISO 8601
+func parseTime(value string) time.Time {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}
+	}
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(seconds, 0)
+	}
+	for _, layout := range []string{time.RFC3339Nano, time.RFC3339, "2006-01-02 15:04:05", "2006-01-02"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
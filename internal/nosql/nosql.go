@@ -1,8 +1,12 @@
 package nosql
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"html"
+	"regexp"
+	"strings"
 	"sync"
 	"time"
 
@@ -24,7 +28,76 @@ type Article struct {
 	Folder      string    `json:"folder"`
 	Content     string    `json:"content"`      // Conteúdo HTML do artigo
 	ContentType string    `json:"content_type"` // "html" ou "text"
+	Tags        []string  `json:"tags"`         // Copiadas do SQLite na importação
 	ImportedAt  time.Time `json:"imported_at"`
+
+	// Metadados declarados pela página (JSON-LD, OpenGraph, Twitter cards, <meta>)
+	Authors     []string   `json:"authors,omitempty"`
+	PublishedAt *time.Time `json:"published_at,omitempty"`
+	ModifiedAt  *time.Time `json:"modified_at,omitempty"`
+	SiteName    string     `json:"site_name,omitempty"`
+	LeadImage   string     `json:"lead_image,omitempty"`
+	Language    string     `json:"language,omitempty"`
+	WordCount   int        `json:"word_count,omitempty"`
+	ReadingTime int        `json:"reading_time,omitempty"` // Minutos
+
+	// Arquivo original (PDF); os bytes ficam no bucket de anexos
+	Attachment *AttachmentInfo `json:"attachment,omitempty"`
+}
+
+// AttachmentInfo descreve o anexo de um artigo
+type AttachmentInfo struct {
+	Filename    string `json:"filename"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+}
+
+// htmlTag remove as tags do conteúdo na busca textual
+var htmlTag = regexp.MustCompile(`<[^>]*>`)
+
+// Matches verifica se todos os termos da busca aparecem no título, descrição,
+// autores, site ou texto do artigo (sem diferenciar maiúsculas)
+func (a Article) Matches(query string) bool {
+	terms := strings.Fields(strings.ToLower(query))
+	if len(terms) == 0 {
+		return true
+	}
+
+	text := strings.ToLower(strings.Join([]string{
+		a.Title,
+		a.Description,
+		strings.Join(a.Authors, " "),
+		a.SiteName,
+		html.UnescapeString(htmlTag.ReplaceAllString(a.Content, " ")),
+	}, "\n"))
+
+	for _, term := range terms {
+		if !strings.Contains(text, term) {
+			return false
+		}
+	}
+	return true
+}
+
+// HasTags verifica se o artigo tem as tags informadas.
+// Com matchAll todas precisam estar presentes (AND), senão basta uma (OR).
+func (a Article) HasTags(tags []string, matchAll bool) bool {
+	present := make(map[string]bool, len(a.Tags))
+	for _, tag := range a.Tags {
+		present[strings.ToLower(tag)] = true
+	}
+
+	for _, tag := range tags {
+		found := present[strings.ToLower(strings.TrimSpace(tag))]
+		if matchAll && !found {
+			return false
+		}
+		if !matchAll && found {
+			return true
+		}
+	}
+
+	return matchAll
 }
 
 // NoSQLDB gerencia o banco de dados BBolt
@@ -34,7 +107,8 @@ type NoSQLDB struct {
 }
 
 const (
-	bucketName = "articles"
+	bucketName            = "articles"
+	attachmentsBucketName = "attachments" // Arquivos originais, fora do JSON dos artigos
 )
 
 // NewNoSQLDB cria uma nova instância do banco NoSQL
@@ -44,10 +118,14 @@ func NewNoSQLDB(dbPath string) (*NoSQLDB, error) {
 		return nil, fmt.Errorf("failed to open nosql database: %w", err)
 	}
 
-	// Criar bucket se não existir
+	// Criar buckets se não existirem
 	err = db.Update(func(tx *bolt.Tx) error {
-		_, err := tx.CreateBucketIfNotExists([]byte(bucketName))
-		return err
+		for _, name := range []string{bucketName, attachmentsBucketName} {
+			if _, err := tx.CreateBucketIfNotExists([]byte(name)); err != nil {
+				return err
+			}
+		}
+		return nil
 	})
 	if err != nil {
 		db.Close()
@@ -134,6 +212,126 @@ func (n *NoSQLDB) GetArticle(id int64) (*Article, error) {
 	return &article, nil
 }
 
+// SetArticleTags atualiza as tags de um artigo já importado.
+// Artigos que não estão na lista de leitura são ignorados.
+func (n *NoSQLDB) SetArticleTags(id int64, tags []string) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	return n.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(bucketName))
+		if bucket == nil {
+			return fmt.Errorf("bucket not found")
+		}
+
+		key := []byte(fmt.Sprintf("%d", id))
+		data := bucket.Get(key)
+		if data == nil {
+			return nil
+		}
+
+		var article Article
+		if err := json.Unmarshal(data, &article); err != nil {
+			return fmt.Errorf("failed to unmarshal article: %w", err)
+		}
+
+		article.Tags = tags
+		updated, err := json.Marshal(article)
+		if err != nil {
+			return fmt.Errorf("failed to marshal article: %w", err)
+		}
+
+		return bucket.Put(key, updated)
+	})
+}
+
+// UpdateArticle aplica update a um artigo já importado e o regrava, mantendo a
+// data de importação. Artigos que não estão na lista de leitura são ignorados.
+func (n *NoSQLDB) UpdateArticle(id int64, update func(*Article)) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	return n.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(bucketName))
+		if bucket == nil {
+			return fmt.Errorf("bucket not found")
+		}
+
+		key := []byte(fmt.Sprintf("%d", id))
+		data := bucket.Get(key)
+		if data == nil {
+			return nil
+		}
+
+		var article Article
+		if err := json.Unmarshal(data, &article); err != nil {
+			return fmt.Errorf("failed to unmarshal article: %w", err)
+		}
+
+		update(&article)
+		article.ID = id
+		updated, err := json.Marshal(article)
+		if err != nil {
+			return fmt.Errorf("failed to marshal article: %w", err)
+		}
+
+		return bucket.Put(key, updated)
+	})
+}
+
+// MoveArticle transfere o artigo importado para outro ID (usado quando artigos
+// duplicados são fundidos). Se o destino já existe, a origem é apenas removida.
+func (n *NoSQLDB) MoveArticle(fromID, toID int64) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	return n.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(bucketName))
+		if bucket == nil {
+			return fmt.Errorf("bucket not found")
+		}
+
+		fromKey := []byte(fmt.Sprintf("%d", fromID))
+		toKey := []byte(fmt.Sprintf("%d", toID))
+
+		data := bucket.Get(fromKey)
+		if data == nil {
+			return nil
+		}
+
+		if bucket.Get(toKey) == nil {
+			var article Article
+			if err := json.Unmarshal(data, &article); err != nil {
+				return fmt.Errorf("failed to unmarshal article: %w", err)
+			}
+			article.ID = toID
+
+			moved, err := json.Marshal(article)
+			if err != nil {
+				return fmt.Errorf("failed to marshal article: %w", err)
+			}
+			if err := bucket.Put(toKey, moved); err != nil {
+				return fmt.Errorf("failed to save article: %w", err)
+			}
+
+			if attachments := tx.Bucket([]byte(attachmentsBucketName)); attachments != nil {
+				if data := attachments.Get(fromKey); data != nil {
+					if err := attachments.Put(toKey, append([]byte(nil), data...)); err != nil {
+						return fmt.Errorf("failed to move attachment: %w", err)
+					}
+				}
+			}
+		}
+
+		if attachments := tx.Bucket([]byte(attachmentsBucketName)); attachments != nil {
+			if err := attachments.Delete(fromKey); err != nil {
+				return err
+			}
+		}
+		return bucket.Delete(fromKey)
+	})
+}
+
 // IsImported verifica se um artigo já foi importado
 func (n *NoSQLDB) IsImported(id int64) bool {
 	n.mu.RLock()
@@ -211,6 +409,70 @@ func (n *NoSQLDB) GetAllImported() ([]Article, error) {
 	return articles, err
 }
 
+// forEachBatch é quantos artigos ForEachImported decodifica por transação
+const forEachBatch = 50
+
+// ForEachImported percorre os artigos importados um por vez, sem montar a lista
+// completa em memória. Os artigos são lidos em lotes e fn é chamada fora da
+// transação e do lock, para que um consumidor lento (como o download de uma
+// exportação) não bloqueie as escritas na lista de leitura.
+func (n *NoSQLDB) ForEachImported(fn func(Article) error) error {
+	var after []byte
+	for {
+		batch, last, err := n.importedBatch(after)
+		if err != nil {
+			return err
+		}
+		for _, article := range batch {
+			if err := fn(article); err != nil {
+				return err
+			}
+		}
+		if last == nil {
+			return nil
+		}
+		after = last
+	}
+}
+
+// importedBatch lê até forEachBatch artigos com chave depois de after (nil = do
+// início); last é a chave do último artigo lido, ou nil no fim do bucket
+func (n *NoSQLDB) importedBatch(after []byte) (batch []Article, last []byte, err error) {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	err = n.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(bucketName))
+		if bucket == nil {
+			return nil
+		}
+
+		cursor := bucket.Cursor()
+		k, v := cursor.First()
+		if after != nil {
+			k, v = cursor.Seek(after)
+			if k != nil && bytes.Equal(k, after) {
+				k, v = cursor.Next()
+			}
+		}
+
+		for ; k != nil; k, v = cursor.Next() {
+			if len(batch) == forEachBatch {
+				// Ainda há artigos: o próximo lote continua depois do último lido
+				return nil
+			}
+			var article Article
+			if err := json.Unmarshal(v, &article); err == nil {
+				batch = append(batch, article)
+			}
+			last = append(last[:0], k...)
+		}
+		last = nil
+		return nil
+	})
+	return batch, last, err
+}
+
 // DeleteArticle remove um artigo do banco NoSQL
 func (n *NoSQLDB) DeleteArticle(id int64) error {
 	n.mu.Lock()
@@ -223,10 +485,53 @@ func (n *NoSQLDB) DeleteArticle(id int64) error {
 		}
 
 		key := fmt.Sprintf("%d", id)
+		if attachments := tx.Bucket([]byte(attachmentsBucketName)); attachments != nil {
+			if err := attachments.Delete([]byte(key)); err != nil {
+				return err
+			}
+		}
 		return bucket.Delete([]byte(key))
 	})
 }
 
+// SaveAttachment guarda o arquivo original do artigo, substituindo o anterior
+func (n *NoSQLDB) SaveAttachment(id int64, data []byte) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	return n.db.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists([]byte(attachmentsBucketName))
+		if err != nil {
+			return err
+		}
+
+		key := fmt.Sprintf("%d", id)
+		return bucket.Put([]byte(key), data)
+	})
+}
+
+// GetAttachment retorna o arquivo original do artigo (nil se não houver)
+func (n *NoSQLDB) GetAttachment(id int64) ([]byte, error) {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	var data []byte
+	err := n.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(attachmentsBucketName))
+		if bucket == nil {
+			return nil
+		}
+
+		key := fmt.Sprintf("%d", id)
+		if stored := bucket.Get([]byte(key)); stored != nil {
+			// Os bytes do bbolt só valem dentro da transação
+			data = append([]byte(nil), stored...)
+		}
+		return nil
+	})
+	return data, err
+}
+
 // GetStats retorna estatísticas do banco NoSQL
 func (n *NoSQLDB) GetStats() (map[string]interface{}, error) {
 	n.mu.RLock()
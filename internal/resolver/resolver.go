@@ -0,0 +1,358 @@
+package resolver
+
+import (
+	"bytes"
+	"compress/zlib"
+	"container/list"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gustavoflandal/gmail-scanner/internal/scraper"
+	"github.com/sirupsen/logrus"
+)
+
+var log = logrus.New()
+
+// Cache guarda os mapeamentos rastreador → URL final entre execuções
+type Cache interface {
+	GetRedirect(trackerURL string) (string, bool)
+	SaveRedirect(trackerURL, finalURL string) error
+}
+
+// trackerHosts são domínios de redirecionadores de clique usados por newsletters.
+// Casam pelo sufixo do host (ex.: email.mg1.substack.com casa com substack.com
+// apenas quando o caminho também indica redirecionamento, ver isTracker).
+var trackerHosts = []string{
+	"list-manage.com",       // Mailchimp
+	"link.mail.beehiiv.com", // beehiiv
+	"convertkit-mail.com",   // ConvertKit
+	"convertkit-mail2.com",  // ConvertKit
+	"ck.page",               // ConvertKit
+	"link.medium.com",       // Medium
+	"t.co",                  // Twitter/X
+	"bit.ly",                // Bitly
+	"lnkd.in",               // LinkedIn
+	"sendgrid.net",          // SendGrid
+	"awstrack.me",           // Amazon SES
+	"tracking.tldrnewsletter.com",
+	"hubspotlinks.com", // HubSpot
+	"mlsend.com",       // MailerLite
+	"createsend1.com",  // Campaign Monitor
+	"mailgun.org",      // Mailgun
+	"google.com",       // google.com/url?q=
+	"l.facebook.com",   // Facebook
+}
+
+// redirectParams são parâmetros de query que costumam carregar a URL de destino
+var redirectParams = []string{"url", "u", "q", "redirect", "redirect_url", "target", "dest", "destination", "link", "r"}
+
+var base64Segment = regexp.MustCompile(`^[A-Za-z0-9_\-+/=]{16,}$`)
+
+// maxMemEntries limita os redirecionamentos guardados em memória; os mais antigos
+// continuam no cache persistente
+const maxMemEntries = 10000
+
+// Resolver converte links de rastreamento na URL real do artigo
+type Resolver struct {
+	cache   Cache
+	client  *http.Client
+	online  bool
+	minWait time.Duration
+
+	mu       sync.Mutex
+	lastHit  map[string]time.Time
+	memCache map[string]*list.Element // Rastreador -> item de memOrder
+	memOrder *list.List               // memEntry; a frente é o mais recente (LRU)
+}
+
+// memEntry é um redirecionamento guardado em memória
+type memEntry struct {
+	trackerURL string
+	finalURL   string
+}
+
+// New cria um resolver. Com online=false apenas a decodificação offline é usada.
+func New(cache Cache, online bool) *Resolver {
+	r := &Resolver{
+		cache:    cache,
+		online:   online,
+		minWait:  500 * time.Millisecond, // No máximo 2 requisições por segundo por host
+		lastHit:  make(map[string]time.Time),
+		memCache: make(map[string]*list.Element),
+		memOrder: list.New(),
+	}
+
+	// Mesmo cliente do scraper: os links vêm dos emails e não podem levar a
+	// endereços internos (loopback, rede privada, link-local)
+	r.client = scraper.NewSafeClient(15 * time.Second)
+	checkRedirect := r.client.CheckRedirect
+	r.client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if err := checkRedirect(req, via); err != nil {
+			return err
+		}
+		// Parar assim que sair da cadeia de rastreadores: a URL final já é conhecida
+		if !IsTracker(req.URL) {
+			return http.ErrUseLastResponse
+		}
+		r.throttle(req.URL.Hostname())
+		return nil
+	}
+
+	return r
+}
+
+// IsTracker indica se a URL aponta para um redirecionador de clique conhecido
+func IsTracker(u *url.URL) bool {
+	host := strings.ToLower(u.Hostname())
+
+	// Substack: email.mgN.substack.com/c/... e substack.com/redirect/...
+	if host == "substack.com" || strings.HasSuffix(host, ".substack.com") {
+		return strings.HasPrefix(host, "email.") || strings.HasPrefix(u.Path, "/redirect/")
+	}
+
+	for _, tracker := range trackerHosts {
+		if host == tracker || strings.HasSuffix(host, "."+tracker) {
+			if tracker == "google.com" {
+				return u.Path == "/url"
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// Resolve devolve a URL final do link. Se não for um rastreador, ou se não
+// for possível resolver, devolve a própria URL e resolved=false.
+func (r *Resolver) Resolve(rawURL string) (finalURL string, resolved bool) {
+	u, err := url.Parse(rawURL)
+	if err != nil || !IsTracker(u) {
+		return rawURL, false
+	}
+
+	if final, ok := r.cached(rawURL); ok {
+		return final, final != rawURL
+	}
+
+	final, ok := DecodeOffline(u)
+	if !ok && r.online {
+		final, err = r.follow(rawURL)
+		ok = err == nil
+		if err != nil {
+			log.Warnf("Failed to resolve tracker %s: %v", rawURL, err)
+		}
+	}
+
+	if !ok || final == "" {
+		return rawURL, false
+	}
+
+	r.store(rawURL, final)
+	return final, final != rawURL
+}
+
+// DecodeOffline tenta extrair o destino sem acessar a rede: parâmetros de
+// redirecionamento, segmentos de caminho URL-encoded (Amazon SES, TLDR) ou em
+// base64 (ConvertKit) e o payload zlib+base64 do Mailgun/Substack
+func DecodeOffline(u *url.URL) (string, bool) {
+	query := u.Query()
+	for _, param := range redirectParams {
+		if target := asHTTPURL(query.Get(param)); target != "" {
+			return target, true
+		}
+	}
+
+	for _, segment := range strings.Split(u.EscapedPath(), "/") {
+		if segment == "" {
+			continue
+		}
+
+		if unescaped, err := url.PathUnescape(segment); err == nil {
+			if target := asHTTPURL(unescaped); target != "" {
+				return target, true
+			}
+		}
+
+		if base64Segment.MatchString(segment) {
+			if target := decodeBase64Target(segment); target != "" {
+				return target, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// decodeBase64Target decodifica um segmento base64 que contém uma URL, direto
+// ou dentro de um JSON comprimido com zlib (prefixo "eJ")
+func decodeBase64Target(segment string) string {
+	segment = strings.TrimRight(segment, "=")
+	var data []byte
+	var err error
+	for _, enc := range []*base64.Encoding{base64.RawURLEncoding, base64.RawStdEncoding} {
+		if data, err = enc.DecodeString(segment); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		return ""
+	}
+
+	if target := asHTTPURL(string(data)); target != "" {
+		return target
+	}
+
+	zr, err := zlib.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return ""
+	}
+	defer zr.Close()
+
+	inflated, err := io.ReadAll(io.LimitReader(zr, 64<<10))
+	if err != nil {
+		return ""
+	}
+
+	var payload map[string]interface{}
+	if json.Unmarshal(inflated, &payload) != nil {
+		return ""
+	}
+	for _, key := range []string{"url", "u", "href", "target"} {
+		if value, ok := payload[key].(string); ok {
+			if target := asHTTPURL(value); target != "" {
+				return target
+			}
+		}
+	}
+	return ""
+}
+
+// asHTTPURL retorna o valor se ele for uma URL http(s) absoluta
+func asHTTPURL(value string) string {
+	value = strings.TrimSpace(value)
+	if !strings.HasPrefix(value, "http://") && !strings.HasPrefix(value, "https://") {
+		return ""
+	}
+	u, err := url.Parse(value)
+	if err != nil || u.Host == "" {
+		return ""
+	}
+	return value
+}
+
+// follow segue os redirecionamentos com HEAD, caindo para GET quando o
+// servidor não aceita HEAD
+func (r *Resolver) follow(rawURL string) (string, error) {
+	final, err := r.request(http.MethodHead, rawURL)
+	if err == nil {
+		return final, nil
+	}
+	return r.request(http.MethodGet, rawURL)
+}
+
+func (r *Resolver) request(method, rawURL string) (string, error) {
+	req, err := http.NewRequest(method, rawURL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("User-Agent", "Mozilla/5.0 (compatible; Gmail-Scanner/1.0)")
+
+	parsed, _ := url.Parse(rawURL)
+	r.throttle(parsed.Hostname())
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	// Com ErrUseLastResponse a resposta é o último redirect; o destino está no Location
+	if resp.StatusCode >= 300 && resp.StatusCode < 400 {
+		location, err := resp.Location()
+		if err != nil {
+			return "", fmt.Errorf("redirect without location: %w", err)
+		}
+		return location.String(), nil
+	}
+
+	if resp.StatusCode >= 400 {
+		return "", fmt.Errorf("bad status code: %d", resp.StatusCode)
+	}
+
+	return resp.Request.URL.String(), nil
+}
+
+// throttle espera o intervalo mínimo entre requisições ao mesmo host
+func (r *Resolver) throttle(host string) {
+	r.mu.Lock()
+	next := r.lastHit[host].Add(r.minWait)
+	now := time.Now()
+	if next.Before(now) {
+		next = now
+	}
+	r.lastHit[host] = next
+	r.mu.Unlock()
+
+	time.Sleep(time.Until(next))
+}
+
+func (r *Resolver) cached(trackerURL string) (string, bool) {
+	r.mu.Lock()
+	elem, ok := r.memCache[trackerURL]
+	if ok {
+		r.memOrder.MoveToFront(elem)
+		final := elem.Value.(*memEntry).finalURL
+		r.mu.Unlock()
+		return final, true
+	}
+	r.mu.Unlock()
+
+	if r.cache == nil {
+		return "", false
+	}
+
+	final, ok := r.cache.GetRedirect(trackerURL)
+	if ok {
+		r.remember(trackerURL, final)
+	}
+	return final, ok
+}
+
+func (r *Resolver) store(trackerURL, finalURL string) {
+	r.remember(trackerURL, finalURL)
+
+	if r.cache == nil {
+		return
+	}
+	if err := r.cache.SaveRedirect(trackerURL, finalURL); err != nil {
+		log.Warnf("Failed to cache redirect for %s: %v", trackerURL, err)
+	}
+}
+
+// remember guarda o redirecionamento em memória, descartando os menos usados
+// acima de maxMemEntries
+func (r *Resolver) remember(trackerURL, finalURL string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if elem, ok := r.memCache[trackerURL]; ok {
+		elem.Value.(*memEntry).finalURL = finalURL
+		r.memOrder.MoveToFront(elem)
+		return
+	}
+
+	r.memCache[trackerURL] = r.memOrder.PushFront(&memEntry{trackerURL: trackerURL, finalURL: finalURL})
+	for r.memOrder.Len() > maxMemEntries {
+		oldest := r.memOrder.Back()
+		r.memOrder.Remove(oldest)
+		delete(r.memCache, oldest.Value.(*memEntry).trackerURL)
+	}
+}
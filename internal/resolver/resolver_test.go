@@ -0,0 +1,72 @@
+package resolver
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestIsTracker(t *testing.T) {
+	tests := []struct {
+		url  string
+		want bool
+	}{
+		{"https://link.mail.beehiiv.com/ss/c/abc", true},
+		{"https://www.beehiiv.com/blog/post", false}, // Páginas do próprio beehiiv são artigos
+		{"https://newsletter.beehiiv.com/p/issue", false},
+		{"https://www.google.com/url?q=https://example.com", true},
+		{"https://www.google.com/search?q=x", false},
+		{"https://email.mg1.substack.com/c/abc", true},
+		{"https://blog.substack.com/p/post", false},
+		{"https://t.co/abc", true},
+		{"https://example.com/a", false},
+	}
+
+	for _, tt := range tests {
+		u, _ := url.Parse(tt.url)
+		if got := IsTracker(u); got != tt.want {
+			t.Errorf("IsTracker(%s) = %v, want %v", tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestResolveRefusesInternalAddresses(t *testing.T) {
+	log.SetLevel(logrus.ErrorLevel)
+	hit := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hit = true
+		http.Redirect(w, r, "https://example.com/article", http.StatusFound)
+	}))
+	defer server.Close()
+
+	// Rastreador num endereço local: o cliente protegido não conecta
+	r := New(nil, true)
+	if _, err := r.follow(server.URL + "/abc"); err == nil {
+		t.Error("resolver followed a tracker on a loopback address")
+	}
+	if hit {
+		t.Error("request reached the internal server")
+	}
+}
+
+func TestMemoryCacheIsBounded(t *testing.T) {
+	r := New(nil, false)
+	for i := 0; i < maxMemEntries+10; i++ {
+		r.store(fmt.Sprintf("https://bit.ly/%d", i), fmt.Sprintf("https://example.com/%d", i))
+	}
+
+	if len(r.memCache) != maxMemEntries || r.memOrder.Len() != maxMemEntries {
+		t.Fatalf("cache has %d entries, want %d", len(r.memCache), maxMemEntries)
+	}
+	// Os mais antigos saem primeiro
+	if _, ok := r.cached("https://bit.ly/0"); ok {
+		t.Error("oldest entry was not evicted")
+	}
+	if final, ok := r.cached(fmt.Sprintf("https://bit.ly/%d", maxMemEntries+9)); !ok || final != fmt.Sprintf("https://example.com/%d", maxMemEntries+9) {
+		t.Errorf("newest entry = %q, %v", final, ok)
+	}
+}
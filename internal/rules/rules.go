@@ -0,0 +1,209 @@
+package rules
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/gustavoflandal/gmail-scanner/internal/database"
+)
+
+// Campos que uma regra pode inspecionar
+const (
+	FieldDomain     = "domain"
+	FieldURL        = "url"
+	FieldNewsletter = "newsletter"
+	FieldTitle      = "title"
+	FieldFolder     = "folder"
+	FieldClass      = "class"
+)
+
+// Operadores de comparação suportados
+const (
+	OpEquals   = "equals"
+	OpContains = "contains"
+	OpPrefix   = "prefix"
+	OpSuffix   = "suffix"
+	OpRegex    = "regex"
+)
+
+// Candidate são os dados de um link usados na avaliação das regras
+type Candidate struct {
+	URL        string
+	Domain     string
+	Title      string
+	Newsletter string
+	Folder     string
+	Class      string
+}
+
+// CandidateFromArticle monta o candidato a partir de um artigo já salvo
+func CandidateFromArticle(article database.Article) Candidate {
+	return Candidate{
+		URL:        article.URL,
+		Domain:     article.Domain,
+		Title:      article.Title,
+		Newsletter: article.Newsletter,
+		Folder:     article.Folder,
+		Class:      article.Class,
+	}
+}
+
+// Result acumula as ações de todas as regras que casaram
+type Result struct {
+	Skip         bool
+	MarkRead     bool
+	AutoImport   bool
+	Tags         []string
+	MatchedRules []int64
+}
+
+// Matched indica se alguma regra casou
+func (r Result) Matched() bool {
+	return len(r.MatchedRules) > 0
+}
+
+// compiledRule é uma regra validada e pronta para avaliação
+type compiledRule struct {
+	rule  database.Rule
+	value string
+	re    *regexp.Regexp
+}
+
+// Engine avalia um conjunto de regras sobre links extraídos
+type Engine struct {
+	rules []compiledRule
+}
+
+// Validate verifica se a regra tem campo, operador e valor válidos
+func Validate(rule database.Rule) error {
+	_, err := compile(rule)
+	return err
+}
+
+// NewEngine compila as regras habilitadas, na ordem recebida
+func NewEngine(rules []database.Rule) (*Engine, error) {
+	engine := &Engine{}
+	for _, rule := range rules {
+		if !rule.Enabled {
+			continue
+		}
+		compiled, err := compile(rule)
+		if err != nil {
+			return nil, fmt.Errorf("rule %d (%s): %w", rule.ID, rule.Name, err)
+		}
+		engine.rules = append(engine.rules, compiled)
+	}
+	return engine, nil
+}
+
+// compile valida a regra e prepara o valor de comparação
+func compile(rule database.Rule) (compiledRule, error) {
+	switch rule.Field {
+	case FieldDomain, FieldURL, FieldNewsletter, FieldTitle, FieldFolder, FieldClass:
+	default:
+		return compiledRule{}, fmt.Errorf("invalid field: %s", rule.Field)
+	}
+
+	if rule.Value == "" {
+		return compiledRule{}, fmt.Errorf("empty value")
+	}
+
+	if err := database.ValidateTags(rule.Tags); err != nil {
+		return compiledRule{}, err
+	}
+
+	compiled := compiledRule{rule: rule, value: strings.ToLower(rule.Value)}
+
+	switch rule.Operator {
+	case OpEquals, OpContains, OpPrefix, OpSuffix:
+	case OpRegex:
+		re, err := regexp.Compile("(?i)" + rule.Value)
+		if err != nil {
+			return compiledRule{}, fmt.Errorf("invalid regex: %w", err)
+		}
+		compiled.re = re
+	default:
+		return compiledRule{}, fmt.Errorf("invalid operator: %s", rule.Operator)
+	}
+
+	return compiled, nil
+}
+
+// Len retorna a quantidade de regras ativas
+func (e *Engine) Len() int {
+	return len(e.rules)
+}
+
+// Evaluate aplica todas as regras ao candidato e combina as ações.
+// Uma regra com Skip encerra a avaliação, já que o link será descartado.
+func (e *Engine) Evaluate(c Candidate) Result {
+	var result Result
+	seenTags := make(map[string]bool)
+
+	for _, cr := range e.rules {
+		if !cr.matches(c) {
+			continue
+		}
+
+		result.MatchedRules = append(result.MatchedRules, cr.rule.ID)
+
+		if cr.rule.Skip {
+			result.Skip = true
+			return result
+		}
+
+		result.MarkRead = result.MarkRead || cr.rule.MarkRead
+		result.AutoImport = result.AutoImport || cr.rule.AutoImport
+		for _, tag := range cr.rule.Tags {
+			if !seenTags[tag] {
+				seenTags[tag] = true
+				result.Tags = append(result.Tags, tag)
+			}
+		}
+	}
+
+	return result
+}
+
+// matches compara o campo do candidato com o valor da regra (sem diferenciar maiúsculas)
+func (cr compiledRule) matches(c Candidate) bool {
+	var subject string
+	switch cr.rule.Field {
+	case FieldDomain:
+		subject = c.Domain
+	case FieldURL:
+		subject = c.URL
+	case FieldNewsletter:
+		subject = c.Newsletter
+	case FieldTitle:
+		subject = c.Title
+	case FieldFolder:
+		subject = c.Folder
+	case FieldClass:
+		subject = c.Class
+	}
+
+	if cr.re != nil {
+		return cr.re.MatchString(subject)
+	}
+
+	subject = strings.ToLower(subject)
+	switch cr.rule.Operator {
+	case OpEquals:
+		return subject == cr.value
+	case OpContains:
+		return strings.Contains(subject, cr.value)
+	case OpPrefix:
+		return strings.HasPrefix(subject, cr.value)
+	case OpSuffix:
+		// Para domínios, "suffix" respeita o limite do rótulo: example.com casa
+		// com blog.example.com, mas não com notexample.com
+		if cr.rule.Field == FieldDomain && subject != cr.value {
+			return strings.HasSuffix(subject, "."+strings.TrimPrefix(cr.value, "."))
+		}
+		return strings.HasSuffix(subject, cr.value)
+	}
+
+	return false
+}
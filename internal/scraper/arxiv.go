@@ -0,0 +1,191 @@
+package scraper
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"html"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+func init() {
+	Register(arxivExtractor{})
+}
+
+// arxivURL é a base do arXiv (servidor local nos testes)
+var arxivURL = "https://arxiv.org"
+
+// arxivPathPattern reconhece /abs/<id> e /pdf/<id>[.pdf], nos formatos novo
+// (2401.01234v2) e antigo (hep-th/9901001)
+var arxivPathPattern = regexp.MustCompile(`^/(?:abs|pdf)/((?:[a-z-]+(?:\.[A-Za-z]{2})?/\d{7})|(?:\d{4}\.\d{4,5}))(v\d+)?(?:\.pdf)?/?$`)
+
+// arxivExtractor busca artigos do arXiv: os metadados vêm da página do resumo e o
+// texto do PDF, que fica anexado ao item
+type arxivExtractor struct{}
+
+func (arxivExtractor) Name() string  { return "arxiv" }
+func (arxivExtractor) Priority() int { return 10 }
+func (arxivExtractor) Match(u *url.URL) bool {
+	return hostMatches(u, "arxiv.org") && arxivPaperID(u) != ""
+}
+
+func (arxivExtractor) Fetch(ctx context.Context, articleURL string) (*ArticleContent, error) {
+	parsedURL, _ := url.Parse(articleURL)
+	id := arxivPaperID(parsedURL)
+	if id == "" {
+		return nil, fmt.Errorf("not an arXiv paper URL: %s", articleURL)
+	}
+
+	absPage, err := defaultFetcher.fetchPage(ctx, arxivAbsURL(id), map[string]string{
+		"Accept":          "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8",
+		"Accept-Language": "en-US,en;q=0.9",
+	})
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, err
+		}
+		// Sem a página do resumo, o PDF sozinho ainda rende o artigo
+		log.Warnf("arXiv abstract page for %s failed (%v), fetching the PDF only", id, err)
+		return tryFetchWithHeaders(ctx, arxivPDFURL(id), map[string]string{"Accept": "application/pdf"}, extractGenericContent)
+	}
+
+	paper := parseArxivAbstract(absPage)
+	pdfPage, err := defaultFetcher.fetchPage(ctx, paper.pdfURL(id), map[string]string{"Accept": "application/pdf"})
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, err
+		}
+		log.Warnf("arXiv PDF for %s failed (%v), keeping only the abstract", id, err)
+	}
+
+	return paper.article(id, absPage, pdfPage)
+}
+
+// ExtractCached monta o artigo de novo com a página do resumo e o PDF do cache
+func (arxivExtractor) ExtractCached(articleURL *url.URL) (*ArticleContent, error) {
+	id := arxivPaperID(articleURL)
+	absPage, ok := defaultFetcher.cachedCopy(arxivAbsURL(id))
+	if !ok {
+		return nil, ErrNotCached
+	}
+
+	paper := parseArxivAbstract(absPage)
+	pdfPage, _ := defaultFetcher.cachedCopy(paper.pdfURL(id)) // Sem o PDF, fica só o resumo
+	return paper.article(id, absPage, pdfPage)
+}
+
+// arxivPaperID extrai o identificador (com a versão, se houver) da URL
+func arxivPaperID(u *url.URL) string {
+	if u == nil {
+		return ""
+	}
+	m := arxivPathPattern.FindStringSubmatch(u.Path)
+	if m == nil {
+		return ""
+	}
+	return m[1] + m[2]
+}
+
+func arxivAbsURL(id string) string { return arxivURL + "/abs/" + id }
+func arxivPDFURL(id string) string { return arxivURL + "/pdf/" + id }
+
+// arxivPaper são os metadados da página do resumo (meta tags citation_*)
+type arxivPaper struct {
+	title     string
+	authors   []string
+	published time.Time
+	abstract  string
+	pdf       string
+}
+
+func parseArxivAbstract(page *fetchedPage) arxivPaper {
+	var paper arxivPaper
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(page.Body))
+	if err != nil {
+		return paper
+	}
+
+	paper.title = strings.Join(strings.Fields(metaContent(doc, "meta[name='citation_title']")), " ")
+
+	var authors []string
+	doc.Find("meta[name='citation_author']").Each(func(i int, s *goquery.Selection) {
+		// "Sobrenome, Nome" -> "Nome Sobrenome"
+		name := strings.TrimSpace(s.AttrOr("content", ""))
+		if last, first, ok := strings.Cut(name, ","); ok {
+			name = strings.TrimSpace(first) + " " + strings.TrimSpace(last)
+		}
+		authors = append(authors, name)
+	})
+	paper.authors = cleanAuthors(authors)
+
+	for _, selector := range []string{"meta[name='citation_date']", "meta[name='citation_online_date']"} {
+		if t, err := time.Parse("2006/01/02", metaContent(doc, selector)); err == nil {
+			paper.published = t
+			break
+		}
+	}
+
+	paper.abstract = metaContent(doc, "meta[name='citation_abstract']")
+	if paper.abstract == "" {
+		abstract := doc.Find("blockquote.abstract").First()
+		abstract.Find(".descriptor").Remove()
+		paper.abstract = abstract.Text()
+	}
+	paper.abstract = strings.Join(strings.Fields(paper.abstract), " ")
+
+	paper.pdf = absoluteURL(page.URL, metaContent(doc, "meta[name='citation_pdf_url']"))
+	return paper
+}
+
+// pdfURL é o PDF declarado pela página ou o endereço padrão do arXiv
+func (p arxivPaper) pdfURL(id string) string {
+	if p.pdf != "" {
+		return p.pdf
+	}
+	return arxivPDFURL(id)
+}
+
+// article junta o resumo e o texto do PDF (quando foi baixado)
+func (p arxivPaper) article(id string, absPage, pdfPage *fetchedPage) (*ArticleContent, error) {
+	var content *ArticleContent
+	if pdfPage != nil && isPDFContentType(pdfPage.Header.Get("Content-Type")) {
+		var err error
+		if content, err = pdfArticle(pdfPage); err != nil {
+			log.Warnf("arXiv PDF for %s could not be read: %v", id, err)
+			content = nil
+		}
+	}
+
+	var body strings.Builder
+	if p.abstract != "" {
+		body.WriteString("<h2>Resumo</h2>\n<p>" + html.EscapeString(p.abstract) + "</p>\n")
+	}
+	if content != nil {
+		body.WriteString("<h2>Texto completo</h2>\n" + content.Content)
+	} else {
+		if p.abstract == "" {
+			return nil, fmt.Errorf("could not extract arXiv paper %s", id)
+		}
+		content = &ArticleContent{ContentType: "html"}
+	}
+
+	content.Content = body.String()
+	if p.title != "" {
+		content.Title = p.title
+	}
+	if len(p.authors) > 0 {
+		content.Metadata.Authors = p.authors
+	}
+	if !p.published.IsZero() {
+		content.Metadata.PublishedAt = p.published
+	}
+	content.Metadata.SiteName = "arXiv"
+	content.CanonicalURL = arxivAbsURL(id)
+	content.baseURL = absPage.URL
+	return content, nil
+}
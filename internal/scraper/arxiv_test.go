@@ -0,0 +1,96 @@
+package scraper
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestArxivPaperID(t *testing.T) {
+	tests := map[string]string{
+		"https://arxiv.org/abs/2401.01234":       "2401.01234",
+		"https://arxiv.org/abs/2401.01234v2":     "2401.01234v2",
+		"https://arxiv.org/pdf/2401.01234v2.pdf": "2401.01234v2",
+		"https://arxiv.org/pdf/2401.01234":       "2401.01234",
+		"https://arxiv.org/abs/hep-th/9901001":   "hep-th/9901001",
+		"https://arxiv.org/abs/math.AG/0601001":  "math.AG/0601001",
+		"https://arxiv.org/list/cs.CL/recent":    "",
+	}
+	for raw, want := range tests {
+		u, _ := url.Parse(raw)
+		if got := arxivPaperID(u); got != want {
+			t.Errorf("arxivPaperID(%s) = %q, want %q", raw, got, want)
+		}
+		if got := (arxivExtractor{}).Match(u); got != (want != "") {
+			t.Errorf("Match(%s) = %v", raw, got)
+		}
+	}
+}
+
+func TestArxivFetchesAbstractAndPDF(t *testing.T) {
+	log.SetLevel(logrus.ErrorLevel)
+	pdf := readPDFFixture(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/abs/2401.01234v2":
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte(`<html><head>
+				<meta name="citation_title" content="Lazy Parsing at Scale">
+				<meta name="citation_author" content="Souza, Ana">
+				<meta name="citation_author" content="Lima, Bruno">
+				<meta name="citation_date" content="2024/01/03">
+				<meta name="citation_pdf_url" content="/pdf/2401.01234v2">
+				</head><body><blockquote class="abstract"><span class="descriptor">Abstract:</span>
+				We show that parsers can   defer most of their work.</blockquote></body></html>`))
+		case "/pdf/2401.01234v2":
+			w.Header().Set("Content-Type", "application/pdf")
+			w.Write(pdf)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	previousFetcher, previousURL := defaultFetcher, arxivURL
+	SetDefaultFetcher(newTestFetcher(server, FetcherConfig{IgnoreRobots: true}))
+	arxivURL = server.URL
+	t.Cleanup(func() {
+		SetDefaultFetcher(previousFetcher)
+		arxivURL = previousURL
+	})
+
+	// A URL do PDF leva à página do resumo para os metadados
+	content, err := arxivExtractor{}.Fetch(context.Background(), "https://arxiv.org/pdf/2401.01234v2.pdf")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if content.Title != "Lazy Parsing at Scale" {
+		t.Errorf("title = %q", content.Title)
+	}
+	if strings.Join(content.Metadata.Authors, "|") != "Ana Souza|Bruno Lima" {
+		t.Errorf("authors = %q", content.Metadata.Authors)
+	}
+	if !content.Metadata.PublishedAt.Equal(time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("published = %v", content.Metadata.PublishedAt)
+	}
+	if content.CanonicalURL != server.URL+"/abs/2401.01234v2" {
+		t.Errorf("canonical URL = %s", content.CanonicalURL)
+	}
+	if !strings.Contains(content.Content, "<p>We show that parsers can defer most of their work.</p>") {
+		t.Errorf("abstract missing: %q", content.Content)
+	}
+	if !strings.Contains(content.Content, "Second page with five ligatures.") {
+		t.Errorf("PDF text missing: %q", content.Content)
+	}
+	if content.Attachment == nil || content.Attachment.Filename != "2401.01234v2.pdf" {
+		t.Errorf("attachment = %+v", content.Attachment)
+	}
+}
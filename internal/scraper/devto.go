@@ -0,0 +1,56 @@
+package scraper
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+func init() {
+	Register(devToExtractor{})
+}
+
+// devToExtractor busca artigos do Dev.to
+type devToExtractor struct{}
+
+func (devToExtractor) Name() string          { return "dev.to" }
+func (devToExtractor) Priority() int         { return 10 }
+func (devToExtractor) Match(u *url.URL) bool { return hostMatches(u, "dev.to") }
+
+func (devToExtractor) Fetch(ctx context.Context, articleURL string) (*ArticleContent, error) {
+	// Dev.to geralmente funciona bem com headers simples
+	return tryFetchWithHeaders(ctx, articleURL, map[string]string{
+		"Accept":          "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8",
+		"Accept-Language": "en-US,en;q=0.9",
+		"Accept-Encoding": "gzip, deflate",
+		"Connection":      "keep-alive",
+	}, extractDevToContent)
+}
+
+func (devToExtractor) ExtractContent(doc *goquery.Document) string {
+	return extractDevToContent(doc)
+}
+
+// extractDevToContent extrai conteúdo do Dev.to
+func extractDevToContent(doc *goquery.Document) string {
+	// Seletor principal do Dev.to
+	if content, _ := doc.Find("#article-body").Html(); content != "" {
+		return content
+	}
+
+	if content, _ := doc.Find(".crayons-article__body").Html(); content != "" {
+		return content
+	}
+
+	if content, _ := doc.Find(".crayons-article__main").Html(); content != "" {
+		return content
+	}
+
+	// Fallback
+	if content, _ := doc.Find("article").Html(); content != "" {
+		return content
+	}
+
+	return ""
+}
@@ -0,0 +1,184 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Extractor busca o conteúdo dos artigos de um site (ou família de sites).
+// Cada site fica em seu próprio arquivo e se registra no init com Register.
+type Extractor interface {
+	// Name identifica o extrator nos logs
+	Name() string
+	// Priority define a ordem de consulta: maior prioridade é testada primeiro
+	Priority() int
+	// Match informa se o extrator trata a URL
+	Match(u *url.URL) bool
+	// Fetch busca e extrai o conteúdo do artigo
+	Fetch(ctx context.Context, articleURL string) (*ArticleContent, error)
+}
+
+// ContentExtractor é implementado pelos extratores que localizam o conteúdo em
+// páginas HTML; permite extrair de novo a partir do cache, sem acessar a rede
+type ContentExtractor interface {
+	ExtractContent(doc *goquery.Document) string
+}
+
+// CachedExtractor é implementado pelos extratores cujo artigo junta mais de uma
+// página (ex.: o resumo do arXiv e o PDF); extrai de novo só com o cache
+type CachedExtractor interface {
+	ExtractCached(articleURL *url.URL) (*ArticleContent, error)
+}
+
+// Registry escolhe o extrator de cada URL entre os registrados
+type Registry struct {
+	mu         sync.RWMutex
+	extractors []Extractor
+	fallback   Extractor // Usado quando nenhum extrator reconhece a URL
+}
+
+// defaultRegistry contém os extratores embutidos, registrados pelos init de cada arquivo
+var defaultRegistry = NewRegistry(genericExtractor{})
+
+// NewRegistry cria um registro vazio com o extrator de fallback informado
+func NewRegistry(fallback Extractor) *Registry {
+	return &Registry{fallback: fallback}
+}
+
+// Register adiciona um extrator ao registro padrão
+func Register(e Extractor) {
+	defaultRegistry.Register(e)
+}
+
+// Register adiciona um extrator, mantendo a lista ordenada por prioridade
+// (empates são desfeitos pelo nome, para a ordem não depender dos init)
+func (r *Registry) Register(e Extractor) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.extractors = append(r.extractors, e)
+	sort.SliceStable(r.extractors, func(i, j int) bool {
+		a, b := r.extractors[i], r.extractors[j]
+		if a.Priority() != b.Priority() {
+			return a.Priority() > b.Priority()
+		}
+		return a.Name() < b.Name()
+	})
+}
+
+// Lookup retorna o primeiro extrator que reconhece a URL, ou o fallback
+func (r *Registry) Lookup(u *url.URL) Extractor {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, e := range r.extractors {
+		if e.Match(u) {
+			return e
+		}
+	}
+	return r.fallback
+}
+
+// Fetch busca o artigo com o extrator escolhido para a URL
+func (r *Registry) Fetch(ctx context.Context, articleURL string) (*ArticleContent, error) {
+	parsedURL, err := url.Parse(articleURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL: %w", err)
+	}
+
+	extractor := r.Lookup(parsedURL)
+	if extractor == nil {
+		return nil, fmt.Errorf("no extractor for %s", articleURL)
+	}
+
+	// Orçamento de tempo da importação inteira, somando as estratégias do extrator
+	ctx, cancel := context.WithTimeout(ctx, fetchBudget)
+	defer cancel()
+
+	log.Infof("Using %s extractor for: %s", extractor.Name(), articleURL)
+	content, err := extractor.Fetch(ctx, articleURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return finishContent(content, parsedURL), nil
+}
+
+// Reextract extrai de novo o artigo a partir da página guardada no cache do
+// fetcher, sem acessar a rede (para aplicar regras de extração melhores)
+func (r *Registry) Reextract(articleURL string) (*ArticleContent, error) {
+	parsedURL, err := url.Parse(articleURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL: %w", err)
+	}
+	// Artigos montados a partir de várias páginas têm extração própria
+	if extractor, ok := r.Lookup(parsedURL).(CachedExtractor); ok {
+		content, err := extractor.ExtractCached(parsedURL)
+		if err != nil {
+			return nil, err
+		}
+		log.Infof("Re-extracted article content (%d chars) from cached pages of %s", len(content.Content), articleURL)
+		return finishContent(content, parsedURL), nil
+	}
+
+	page, ok := defaultFetcher.cachedCopy(articleURL)
+	if !ok {
+		return nil, ErrNotCached
+	}
+
+	extract := contentFunc(extractGenericContent)
+	if extractor, ok := r.Lookup(page.URL).(ContentExtractor); ok {
+		extract = extractor.ExtractContent
+	}
+
+	content, err := articleFromPage(page, extract)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Infof("Re-extracted article content (%d chars) from cached %s", len(content.Content), page.URL)
+	return finishContent(content, parsedURL), nil
+}
+
+// finishContent sanitiza o HTML e calcula as estatísticas de leitura
+func finishContent(content *ArticleContent, articleURL *url.URL) *ArticleContent {
+	// Todo HTML passa pela allowlist antes de ser salvo e exibido
+	if content.ContentType == "html" {
+		baseURL := content.baseURL
+		if baseURL == nil {
+			baseURL = articleURL
+		}
+		content.Content = sanitizeHTML(content.Content, baseURL)
+	}
+
+	content.Metadata.setReadingStats(content.Content)
+	return content
+}
+
+// hostMatches verifica se o host da URL é um dos domínios ou subdomínio deles,
+// comparando por rótulos inteiros ("notdev.to" não casa com "dev.to")
+func hostMatches(u *url.URL, domains ...string) bool {
+	host := normalizedHost(u)
+	if host == "" || net.ParseIP(host) != nil {
+		return false
+	}
+
+	for _, domain := range domains {
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizedHost devolve o host da URL em minúsculas e sem o ponto final
+func normalizedHost(u *url.URL) string {
+	return strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+}
@@ -0,0 +1,36 @@
+package scraper
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestRegistryLookup(t *testing.T) {
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{"https://dev.to/user/post", "dev.to"},
+		{"https://notdev.to/post", "generic"},
+		{"https://medium.com/@user/post-123", "medium"},
+		{"https://blog.medium.com/post", "medium"},
+		{"https://towardsdatascience.com/post", "medium"},
+		{"https://mymedium.com/post", "generic"},
+		{"https://github.com/owner/repo", "github"},
+		{"https://gist.github.com/owner/123", "github"},
+		{"https://github.com.evil.example/owner/repo", "generic"},
+		{"https://newsletter.substack.com/p/post", "substack"},
+		{"https://SUBSTACK.COM./p/post", "substack"},
+		{"https://example.com/dev.to", "generic"},
+	}
+
+	for _, tt := range tests {
+		u, err := url.Parse(tt.url)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := defaultRegistry.Lookup(u).Name(); got != tt.want {
+			t.Errorf("Lookup(%s) = %s, want %s", tt.url, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,416 @@
+package scraper
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Fetcher faz todas as requisições do scraper: limita a taxa por host (token bucket),
+// respeita o robots.txt, identifica-se com um User-Agent fixo e repete requisições
+// que receberam 429/503 (respeitando Retry-After) ou falharam na rede, com backoff
+// exponencial e jitter.
+
+// DefaultUserAgent identifica o scanner para os sites acessados
+const DefaultUserAgent = "Mozilla/5.0 (compatible; Gmail-Scanner/1.0; +https://github.com/gustavoflandal/gmail-scanner)"
+
+const (
+	// DefaultRequestsPerSecond e DefaultBurst definem o token bucket de cada host
+	DefaultRequestsPerSecond = 1.0
+	DefaultBurst             = 3
+
+	// DefaultMaxRetries é o número de novas tentativas após 429/503 ou falha de rede
+	DefaultMaxRetries = 3
+
+	// backoffBase e backoffMax limitam a espera entre tentativas sem Retry-After
+	backoffBase = 1 * time.Second
+	backoffMax  = 30 * time.Second
+
+	// maxRetryAfter é a maior espera pedida pelo servidor que ainda vale aguardar
+	maxRetryAfter = 60 * time.Second
+)
+
+// errDisallowedByRobots indica URL bloqueada pelo robots.txt do site
+var errDisallowedByRobots = errors.New("disallowed by robots.txt")
+
+// FetcherConfig configura o Fetcher; valores zerados usam os padrões
+type FetcherConfig struct {
+	UserAgent         string
+	RequestsPerSecond float64
+	Burst             int
+	MaxRetries        int
+	IgnoreRobots      bool
+	Cache             *ResponseCache // nil desativa o cache de páginas
+}
+
+// Fetcher é compartilhado por todos os extratores (os buckets são por host, não por cliente)
+type Fetcher struct {
+	client     *http.Client
+	userAgent  string
+	rate       float64
+	burst      int
+	maxRetries int
+	robots     *robotsCache   // nil quando o robots.txt é ignorado
+	cache      *ResponseCache // nil quando o cache está desativado
+
+	mu      sync.Mutex
+	buckets map[string]*tokenBucket
+}
+
+// defaultFetcher é usado pelos extratores; main troca pela configuração do .env
+var defaultFetcher = NewFetcher(FetcherConfig{})
+
+// NewFetcher cria o fetcher com o cliente HTTP protegido (safehttp.go)
+func NewFetcher(cfg FetcherConfig) *Fetcher {
+	if cfg.UserAgent == "" {
+		cfg.UserAgent = DefaultUserAgent
+	}
+	if cfg.RequestsPerSecond <= 0 {
+		cfg.RequestsPerSecond = DefaultRequestsPerSecond
+	}
+	if cfg.Burst <= 0 {
+		cfg.Burst = DefaultBurst
+	}
+	if cfg.MaxRetries < 0 {
+		cfg.MaxRetries = 0
+	} else if cfg.MaxRetries == 0 {
+		cfg.MaxRetries = DefaultMaxRetries
+	}
+
+	f := &Fetcher{
+		client:     NewSafeClient(requestTimeout),
+		userAgent:  cfg.UserAgent,
+		rate:       cfg.RequestsPerSecond,
+		burst:      cfg.Burst,
+		maxRetries: cfg.MaxRetries,
+		cache:      cfg.Cache,
+		buckets:    make(map[string]*tokenBucket),
+	}
+	if !cfg.IgnoreRobots {
+		f.robots = newRobotsCache(f)
+	}
+	return f
+}
+
+// SetDefaultFetcher troca o fetcher usado pelos extratores (chamado na inicialização)
+func SetDefaultFetcher(f *Fetcher) {
+	defaultFetcher = f
+}
+
+// Do executa a requisição respeitando robots.txt, limite por host e novas tentativas
+func (f *Fetcher) Do(req *http.Request) (*http.Response, error) {
+	return f.do(req, true)
+}
+
+// doAPI executa requisições a APIs (ex.: api.github.com), que não são páginas
+// rastreadas e por isso não consultam o robots.txt
+func (f *Fetcher) doAPI(req *http.Request) (*http.Response, error) {
+	return f.do(req, false)
+}
+
+func (f *Fetcher) do(req *http.Request, checkRobots bool) (*http.Response, error) {
+	ctx := req.Context()
+
+	if checkRobots && f.robots != nil {
+		allowed, err := f.robots.allowed(ctx, req.URL)
+		if err != nil {
+			return nil, err
+		}
+		if !allowed {
+			return nil, fmt.Errorf("%w: %s", errDisallowedByRobots, req.URL)
+		}
+	}
+
+	bucket := f.bucketFor(req.URL.Hostname())
+
+	for attempt := 0; ; attempt++ {
+		if err := bucket.wait(ctx); err != nil {
+			return nil, err
+		}
+
+		attemptReq := req.Clone(ctx)
+		attemptReq.Header.Set("User-Agent", f.userAgent)
+
+		resp, err := f.client.Do(attemptReq)
+		retryable := err == nil && (resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable)
+		if err != nil {
+			retryable = isTransientError(err)
+		}
+		if !retryable || attempt >= f.maxRetries {
+			return resp, err
+		}
+
+		delay := backoffDelay(attempt)
+		if resp != nil {
+			if retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+				if retryAfter > maxRetryAfter {
+					log.Warnf("%s asked to retry after %s, giving up", req.URL.Host, retryAfter)
+					return resp, nil
+				}
+				delay = retryAfter
+			}
+			resp.Body.Close()
+			log.Infof("%s returned %d, retrying in %s (attempt %d/%d)", req.URL.Host, resp.StatusCode, delay.Round(time.Millisecond), attempt+1, f.maxRetries)
+		} else {
+			log.Infof("Request to %s failed (%v), retrying in %s (attempt %d/%d)", req.URL.Host, err, delay.Round(time.Millisecond), attempt+1, f.maxRetries)
+		}
+
+		// O host pediu para esperar: vale para todas as requisições a ele
+		bucket.pause(delay)
+	}
+}
+
+// fetchedPage é uma página HTML (ou um PDF) pronta para extração
+type fetchedPage struct {
+	URL    *url.URL // URL final, após os redirects
+	Header http.Header
+	Body   []byte // Já descomprimido
+}
+
+// fetchPage baixa a página HTML ou o PDF, revalidando a cópia do cache quando existe
+// (If-None-Match/If-Modified-Since) e guardando a nova versão
+func (f *Fetcher) fetchPage(ctx context.Context, targetURL string, headers map[string]string) (*fetchedPage, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", targetURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	var cached *cachedPage
+	if f.cache != nil {
+		if page, ok := f.cache.lookup(targetURL); ok {
+			cached = page
+			if page.ETag != "" {
+				req.Header.Set("If-None-Match", page.ETag)
+			}
+			if page.LastModified != "" {
+				req.Header.Set("If-Modified-Since", page.LastModified)
+			}
+		}
+	}
+
+	// Com falha de rede ou 5xx, a cópia do cache (mesmo antiga) é melhor que nada
+	resp, err := f.Do(req)
+	if err != nil {
+		if cached != nil && !errors.Is(err, errDisallowedByRobots) && ctx.Err() == nil {
+			log.Warnf("Fetch of %s failed (%v), using cached copy", targetURL, err)
+			return &fetchedPage{URL: cached.URL, Header: cached.Header, Body: cached.Body}, nil
+		}
+		return nil, fmt.Errorf("failed to fetch: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && cached != nil:
+		log.Infof("Cached copy of %s is still valid", targetURL)
+		f.cache.revalidated(targetURL, cached)
+		return &fetchedPage{URL: cached.URL, Header: cached.Header, Body: cached.Body}, nil
+	case resp.StatusCode >= 500 && cached != nil:
+		log.Warnf("%s returned %d, using cached copy", targetURL, resp.StatusCode)
+		return &fetchedPage{URL: cached.URL, Header: cached.Header, Body: cached.Body}, nil
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("bad status code: %d", resp.StatusCode)
+	}
+
+	// Só HTML e PDF são extraídos; recusar outros tipos antes de baixar o corpo.
+	// Servidores que mandam PDFs como octet-stream são conferidos pelo conteúdo.
+	contentType := resp.Header.Get("Content-Type")
+	sniff := contentType == "" || isOctetStream(contentType)
+	if !sniff && !isHTMLContentType(contentType) && !isPDFContentType(contentType) {
+		return nil, fmt.Errorf("unsupported content type %q", contentType)
+	}
+
+	// Ler o corpo (com suporte a gzip)
+	var reader io.Reader = resp.Body
+	if resp.Header.Get("Content-Encoding") == "gzip" {
+		gzReader, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create gzip reader: %w", err)
+		}
+		defer gzReader.Close()
+		reader = gzReader
+	}
+
+	body, err := readLimited(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if sniff {
+		contentType = http.DetectContentType(body)
+		if !isHTMLContentType(contentType) && !isPDFContentType(contentType) {
+			return nil, fmt.Errorf("unsupported content type %q", contentType)
+		}
+	}
+
+	// O corpo guardado já está descomprimido, com o tipo detectado
+	header := resp.Header.Clone()
+	header.Del("Content-Encoding")
+	header.Del("Content-Length")
+	header.Set("Content-Type", contentType)
+	if f.cache != nil {
+		f.cache.store(targetURL, resp.Request.URL, header, body)
+	}
+
+	return &fetchedPage{URL: resp.Request.URL, Header: header, Body: body}, nil
+}
+
+// cachedCopy devolve a página guardada no cache, sem acessar a rede
+func (f *Fetcher) cachedCopy(targetURL string) (*fetchedPage, bool) {
+	if f.cache == nil {
+		return nil, false
+	}
+	page, ok := f.cache.lookup(targetURL)
+	if !ok {
+		return nil, false
+	}
+	return &fetchedPage{URL: page.URL, Header: page.Header, Body: page.Body}, true
+}
+
+// setCrawlDelay reduz a taxa do host para o Crawl-delay declarado no robots.txt
+func (f *Fetcher) setCrawlDelay(host string, delay time.Duration) {
+	if delay <= 0 {
+		return
+	}
+	bucket := f.bucketFor(host)
+	bucket.mu.Lock()
+	defer bucket.mu.Unlock()
+	if rate := 1 / delay.Seconds(); rate < bucket.rate {
+		bucket.rate = rate
+		bucket.burst = 1
+		bucket.tokens = math.Min(bucket.tokens, 1)
+	}
+}
+
+// bucketFor retorna o token bucket do host, criando na primeira vez
+func (f *Fetcher) bucketFor(host string) *tokenBucket {
+	host = strings.ToLower(host)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	bucket, ok := f.buckets[host]
+	if !ok {
+		bucket = &tokenBucket{rate: f.rate, burst: float64(f.burst), tokens: float64(f.burst), last: time.Now()}
+		f.buckets[host] = bucket
+	}
+	return bucket
+}
+
+// tokenBucket libera rate requisições por segundo, acumulando até burst
+type tokenBucket struct {
+	mu          sync.Mutex
+	rate        float64
+	burst       float64
+	tokens      float64
+	last        time.Time
+	pausedUntil time.Time
+}
+
+// wait bloqueia até haver um token (ou o contexto ser cancelado)
+func (b *tokenBucket) wait(ctx context.Context) error {
+	for {
+		delay := b.reserve(time.Now())
+		if delay <= 0 {
+			return nil
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// reserve consome um token se houver; senão devolve quanto falta esperar
+func (b *tokenBucket) reserve(now time.Time) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if now.Before(b.pausedUntil) {
+		return b.pausedUntil.Sub(now)
+	}
+
+	b.tokens = math.Min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+	if b.tokens >= 1 {
+		b.tokens--
+		return 0
+	}
+	return time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+}
+
+// pause suspende o host pelo tempo informado
+func (b *tokenBucket) pause(d time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if until := time.Now().Add(d); until.After(b.pausedUntil) {
+		b.pausedUntil = until
+	}
+}
+
+// backoffDelay é a espera da tentativa: exponencial com jitter completo
+// (sorteada entre 0 e base*2^tentativa, limitada a backoffMax)
+func backoffDelay(attempt int) time.Duration {
+	ceiling := backoffBase << attempt
+	if ceiling <= 0 || ceiling > backoffMax {
+		ceiling = backoffMax
+	}
+	return time.Duration(rand.Int63n(int64(ceiling))) + time.Millisecond
+}
+
+// parseRetryAfter lê o Retry-After em segundos ou como data HTTP
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if delay := date.Sub(now); delay > 0 {
+			return delay, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+// isTransientError informa se a falha de rede merece nova tentativa
+// (endereço bloqueado, cancelamento e excesso de redirects não mudam ao repetir)
+func isTransientError(err error) bool {
+	if errors.Is(err, errBlockedAddress) || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return dnsErr.IsTimeout || dnsErr.IsTemporary
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	// Conexão recusada ou encerrada no meio da resposta
+	var opErr *net.OpError
+	return errors.As(err, &opErr) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, syscall.ECONNRESET)
+}
@@ -0,0 +1,202 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// newTestFetcher cria um fetcher que aceita o servidor local do teste
+// (o cliente padrão recusa loopback)
+func newTestFetcher(server *httptest.Server, cfg FetcherConfig) *Fetcher {
+	if cfg.RequestsPerSecond == 0 {
+		cfg.RequestsPerSecond = 1000
+	}
+	f := NewFetcher(cfg)
+	f.client = server.Client()
+	return f
+}
+
+func TestFetcherRetriesAfterServiceUnavailable(t *testing.T) {
+	log.SetLevel(logrus.ErrorLevel)
+
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			http.NotFound(w, r)
+			return
+		}
+		if calls.Add(1) < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		if got := r.Header.Get("User-Agent"); got != "TestAgent/1.0" {
+			t.Errorf("User-Agent = %q", got)
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	f := newTestFetcher(server, FetcherConfig{UserAgent: "TestAgent/1.0"})
+	req, _ := http.NewRequest("GET", server.URL+"/page", nil)
+	resp, err := f.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK || calls.Load() != 3 {
+		t.Errorf("status = %d after %d calls, want 200 after 3", resp.StatusCode, calls.Load())
+	}
+}
+
+func TestFetcherGivesUpOnLongRetryAfter(t *testing.T) {
+	log.SetLevel(logrus.ErrorLevel)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "3600")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	f := newTestFetcher(server, FetcherConfig{IgnoreRobots: true})
+	req, _ := http.NewRequest("GET", server.URL+"/page", nil)
+	resp, err := f.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("status = %d, want 429", resp.StatusCode)
+	}
+}
+
+func TestFetcherRobots(t *testing.T) {
+	log.SetLevel(logrus.ErrorLevel)
+
+	var robotsCalls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			robotsCalls.Add(1)
+			w.Write([]byte("User-agent: *\nDisallow: /private\nAllow: /private/ok\n"))
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	f := newTestFetcher(server, FetcherConfig{})
+	for path, allowed := range map[string]bool{"/public": true, "/private/x": false, "/private/ok": true} {
+		req, _ := http.NewRequest("GET", server.URL+path, nil)
+		resp, err := f.Do(req)
+		if allowed {
+			if err != nil {
+				t.Errorf("%s: %v", path, err)
+				continue
+			}
+			resp.Body.Close()
+		} else if !errors.Is(err, errDisallowedByRobots) {
+			t.Errorf("%s: got %v, want errDisallowedByRobots", path, err)
+		}
+	}
+	if robotsCalls.Load() != 1 {
+		t.Errorf("robots.txt fetched %d times, want 1 (cached)", robotsCalls.Load())
+	}
+}
+
+func TestParseRobots(t *testing.T) {
+	body := []byte(`# comentário
+User-agent: Googlebot
+Disallow: /
+
+User-agent: other
+User-agent: Gmail-Scanner
+Disallow: /drafts/
+Allow: /drafts/public$
+Disallow: /*.pdf$
+Crawl-delay: 2
+
+User-agent: *
+Disallow: /
+`)
+	rules := parseRobots(body, robotsAgent)
+
+	tests := map[string]bool{
+		"/":                   true,
+		"/posts/1":            true,
+		"/drafts/":            false,
+		"/drafts/x":           false,
+		"/drafts/public":      true,
+		"/drafts/public/x":    false,
+		"/paper.pdf":          false,
+		"/paper.pdf?download": true,
+		"/robots.txt":         true,
+	}
+	for path, want := range tests {
+		if got := rules.allows(path); got != want {
+			t.Errorf("allows(%s) = %v, want %v", path, got, want)
+		}
+	}
+	if rules.crawlDelay != 2*time.Second {
+		t.Errorf("crawlDelay = %s, want 2s", rules.crawlDelay)
+	}
+
+	// Sem grupo específico vale o "*"
+	wildcard := parseRobots([]byte("User-agent: *\nDisallow: /admin\n"), robotsAgent)
+	if wildcard.allows("/admin/x") || !wildcard.allows("/x") {
+		t.Error("wildcard group not applied")
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value string
+		want  time.Duration
+		ok    bool
+	}{
+		{"120", 2 * time.Minute, true},
+		{"0", 0, true},
+		{"Mon, 01 Jan 2024 12:00:30 GMT", 30 * time.Second, true},
+		{"Mon, 01 Jan 2024 11:00:00 GMT", 0, true},
+		{"-5", 0, false},
+		{"soon", 0, false},
+		{"", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseRetryAfter(tt.value, now)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("parseRetryAfter(%q) = %s, %v; want %s, %v", tt.value, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestTokenBucket(t *testing.T) {
+	start := time.Now()
+	b := &tokenBucket{rate: 2, burst: 2, tokens: 2, last: start}
+
+	// A rajada sai de imediato; a terceira espera meio segundo (2 por segundo)
+	if b.reserve(start) != 0 || b.reserve(start) != 0 {
+		t.Fatal("burst not available")
+	}
+	if wait := b.reserve(start); wait != 500*time.Millisecond {
+		t.Errorf("wait = %s, want 500ms", wait)
+	}
+	if wait := b.reserve(start.Add(500 * time.Millisecond)); wait != 0 {
+		t.Errorf("wait after refill = %s, want 0", wait)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	b.pause(time.Hour)
+	if err := b.wait(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("wait on paused bucket = %v, want context.Canceled", err)
+	}
+}
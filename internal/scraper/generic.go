@@ -0,0 +1,65 @@
+package scraper
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// genericExtractor é o fallback do registro: scraping genérico de qualquer site
+type genericExtractor struct{}
+
+func (genericExtractor) Name() string          { return "generic" }
+func (genericExtractor) Priority() int         { return 0 }
+func (genericExtractor) Match(u *url.URL) bool { return true }
+
+func (genericExtractor) Fetch(ctx context.Context, articleURL string) (*ArticleContent, error) {
+	content, err := tryFetchWithHeaders(ctx, articleURL, map[string]string{
+		"Accept":                    "text/html,application/xhtml+xml,application/xml;q=0.9,image/webp,*/*;q=0.8",
+		"Accept-Language":           "en-US,en;q=0.9,pt-BR;q=0.8",
+		"Accept-Encoding":           "gzip, deflate",
+		"Connection":                "keep-alive",
+		"Upgrade-Insecure-Requests": "1",
+	}, extractGenericContent)
+	if err != nil || content.mediumPostID == "" {
+		return content, err
+	}
+
+	// Publicação do Medium em domínio próprio: seguir as estratégias do Medium,
+	// usando a página já baixada como acesso direto
+	parsedURL, _ := url.Parse(articleURL)
+	rememberMediumPublication(parsedURL)
+	return fetchMediumArticle(ctx, parsedURL, content.mediumPostID, content)
+}
+
+func (genericExtractor) ExtractContent(doc *goquery.Document) string {
+	return extractGenericContent(doc)
+}
+
+// extractGenericContent escolhe o conteúdo principal por pontuação (readability.go);
+// o body inteiro só é usado quando a confiança no candidato é muito baixa
+func extractGenericContent(doc *goquery.Document) string {
+	readable := extractReadable(doc)
+	log.Debugf("Readable content confidence: %.2f", readable.Confidence)
+
+	if readable.Confidence >= lowConfidence {
+		var parts []string
+		readable.Node.Each(func(i int, s *goquery.Selection) {
+			if html, err := goquery.OuterHtml(s); err == nil {
+				parts = append(parts, html)
+			}
+		})
+		if content := strings.Join(parts, "\n"); content != "" {
+			return content
+		}
+	}
+
+	log.Infof("Low confidence in main content (%.2f), using whole body", readable.Confidence)
+	if content, _ := doc.Find("body").Html(); content != "" {
+		return content
+	}
+
+	return ""
+}
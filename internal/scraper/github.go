@@ -0,0 +1,603 @@
+package scraper
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"html"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+func init() {
+	Register(gitHubExtractor{})
+}
+
+// DefaultGitHubAPIURL é a API pública do GitHub
+const DefaultGitHubAPIURL = "https://api.github.com"
+
+// gitHubAPIURL é a base usada nas chamadas à API (GitHub Enterprise ou servidor local nos testes)
+var gitHubAPIURL = DefaultGitHubAPIURL
+
+// SetGitHubAPIURL troca a base da API do GitHub (chamado na inicialização)
+func SetGitHubAPIURL(base string) {
+	if base == "" {
+		base = DefaultGitHubAPIURL
+	}
+	gitHubAPIURL = strings.TrimRight(base, "/")
+}
+
+// gitHubExtractor busca conteúdo do GitHub pela API: README de repositórios,
+// arquivos, issues, pull requests, releases e gists. Outras páginas (e falhas
+// da API) caem no scraping do HTML.
+type gitHubExtractor struct{}
+
+func (gitHubExtractor) Name() string          { return "github" }
+func (gitHubExtractor) Priority() int         { return 10 }
+func (gitHubExtractor) Match(u *url.URL) bool { return hostMatches(u, "github.com") }
+
+func (gitHubExtractor) Fetch(ctx context.Context, githubURL string) (*ArticleContent, error) {
+	parsedURL, _ := url.Parse(githubURL)
+
+	if target, ok := parseGitHubURL(parsedURL); ok {
+		content, err := fetchGitHubTarget(ctx, target)
+		if err == nil {
+			return content, nil
+		}
+		if ctx.Err() != nil {
+			return nil, err
+		}
+		log.Warnf("GitHub API failed for %s: %v - falling back to page scraping", githubURL, err)
+	}
+
+	// Fallback: scraping normal
+	return tryFetchWithHeaders(ctx, githubURL, map[string]string{
+		"Accept":          "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8",
+		"Accept-Language": "en-US,en;q=0.9",
+	}, extractGitHubHTMLContent)
+}
+
+func (gitHubExtractor) ExtractContent(doc *goquery.Document) string {
+	return extractGitHubHTMLContent(doc)
+}
+
+// gitHubKind é o tipo de página do GitHub
+type gitHubKind int
+
+const (
+	gitHubRepoPage gitHubKind = iota
+	gitHubBlobPage
+	gitHubIssuePage
+	gitHubPullPage
+	gitHubReleasePage
+	gitHubGistPage
+)
+
+// gitHubTarget é a página do GitHub reconhecida na URL
+type gitHubTarget struct {
+	kind   gitHubKind
+	owner  string
+	repo   string
+	ref    string // Branch, tag ou commit (repo e blob); vazio = branch padrão
+	path   string // Diretório (repo) ou arquivo (blob)
+	number int    // Issue ou pull request
+	tag    string // Release; vazio = última
+	gistID string
+}
+
+// gitHubReservedPaths são seções do site que não são donos de repositórios
+var gitHubReservedPaths = map[string]bool{
+	"about": true, "apps": true, "collections": true, "enterprise": true, "events": true,
+	"explore": true, "features": true, "login": true, "marketplace": true, "new": true,
+	"notifications": true, "orgs": true, "organizations": true, "pricing": true, "pulls": true,
+	"issues": true, "search": true, "settings": true, "sponsors": true, "topics": true, "trending": true,
+}
+
+// gistIDPattern distingue o ID do gist (hexadecimal) do nome do usuário
+var gistIDPattern = regexp.MustCompile(`^[0-9a-f]{8,}$`)
+
+// parseGitHubURL reconhece as páginas que a API sabe entregar
+func parseGitHubURL(u *url.URL) (gitHubTarget, bool) {
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	host := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+
+	if host == "gist.github.com" {
+		// gist.github.com/<usuário>/<id> ou gist.github.com/<id>
+		id := parts[len(parts)-1]
+		if len(parts) > 2 || !gistIDPattern.MatchString(id) {
+			return gitHubTarget{}, false
+		}
+		return gitHubTarget{kind: gitHubGistPage, gistID: id}, true
+	}
+
+	if host != "github.com" && host != "www.github.com" {
+		return gitHubTarget{}, false
+	}
+	if len(parts) < 2 || gitHubReservedPaths[parts[0]] {
+		return gitHubTarget{}, false
+	}
+
+	target := gitHubTarget{owner: parts[0], repo: strings.TrimSuffix(parts[1], ".git")}
+	if len(parts) == 2 {
+		target.kind = gitHubRepoPage
+		return target, true
+	}
+
+	switch parts[2] {
+	case "tree":
+		if len(parts) >= 4 {
+			target.kind = gitHubRepoPage
+			target.ref = parts[3]
+			target.path = strings.Join(parts[4:], "/")
+			return target, true
+		}
+	case "blob":
+		if len(parts) >= 5 {
+			target.kind = gitHubBlobPage
+			target.ref = parts[3]
+			target.path = strings.Join(parts[4:], "/")
+			return target, true
+		}
+	case "issues", "pull":
+		if len(parts) >= 4 {
+			number, err := strconv.Atoi(parts[3])
+			if err != nil || number <= 0 {
+				return gitHubTarget{}, false
+			}
+			target.kind = gitHubIssuePage
+			if parts[2] == "pull" {
+				target.kind = gitHubPullPage
+			}
+			target.number = number
+			return target, true
+		}
+	case "releases":
+		switch {
+		case len(parts) >= 5 && parts[3] == "tag":
+			target.kind = gitHubReleasePage
+			target.tag = strings.Join(parts[4:], "/")
+			return target, true
+		case len(parts) == 4 && parts[3] == "latest":
+			target.kind = gitHubReleasePage
+			return target, true
+		}
+	}
+	return gitHubTarget{}, false
+}
+
+// fetchGitHubTarget busca a página reconhecida pela API
+func fetchGitHubTarget(ctx context.Context, target gitHubTarget) (*ArticleContent, error) {
+	switch target.kind {
+	case gitHubRepoPage:
+		return fetchGitHubReadme(ctx, target)
+	case gitHubBlobPage:
+		return fetchGitHubFile(ctx, target)
+	case gitHubIssuePage, gitHubPullPage:
+		return fetchGitHubIssue(ctx, target)
+	case gitHubReleasePage:
+		return fetchGitHubRelease(ctx, target)
+	case gitHubGistPage:
+		return fetchGitHubGist(ctx, target)
+	}
+	return nil, fmt.Errorf("unsupported GitHub page")
+}
+
+// gitHubUser é o autor nas respostas da API
+type gitHubUser struct {
+	Login string `json:"login"`
+}
+
+// gitHubContentFile é a resposta JSON de /readme e /contents
+type gitHubContentFile struct {
+	Name     string `json:"name"`
+	Path     string `json:"path"`
+	Content  string `json:"content"`
+	Encoding string `json:"encoding"`
+}
+
+// fetchGitHubReadme busca o README do repositório (ou do diretório) já renderizado
+func fetchGitHubReadme(ctx context.Context, target gitHubTarget) (*ArticleContent, error) {
+	apiPath := "/repos/" + escapePathSegments(target.owner, target.repo) + "/readme"
+	if target.path != "" {
+		apiPath += "/" + escapePathSegments(strings.Split(target.path, "/")...)
+	}
+	if target.ref != "" {
+		apiPath += "?ref=" + url.QueryEscape(target.ref)
+	}
+
+	body, err := gitHubAPI(ctx, apiPath, "application/vnd.github.html+json")
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := gitHubRenderedFile(body)
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(content) == "" {
+		return nil, fmt.Errorf("empty README")
+	}
+
+	// Links e imagens relativos do README apontam para arquivos do repositório
+	ref := target.ref
+	if ref == "" {
+		ref = "HEAD"
+	}
+	base := gitHubRepoURL(target, "raw", ref, target.path)
+
+	return &ArticleContent{
+		Title:       fmt.Sprintf("%s/%s README", target.owner, target.repo),
+		Content:     content,
+		ContentType: "html",
+		Metadata:    ArticleMetadata{SiteName: "GitHub"},
+		baseURL:     base,
+	}, nil
+}
+
+// fetchGitHubFile busca um arquivo do repositório: Markdown vem renderizado,
+// código vem em <pre><code class="language-..."> para o realce de sintaxe
+func fetchGitHubFile(ctx context.Context, target gitHubTarget) (*ArticleContent, error) {
+	apiPath := "/repos/" + escapePathSegments(target.owner, target.repo) + "/contents/" +
+		escapePathSegments(strings.Split(target.path, "/")...) + "?ref=" + url.QueryEscape(target.ref)
+
+	name := path.Base(target.path)
+	var content string
+	if isMarkdownFile(name) {
+		body, err := gitHubAPI(ctx, apiPath, "application/vnd.github.html+json")
+		if err != nil {
+			return nil, err
+		}
+		if content, err = gitHubRenderedFile(body); err != nil {
+			return nil, err
+		}
+	} else {
+		body, err := gitHubAPI(ctx, apiPath, "application/vnd.github.raw+json")
+		if err != nil {
+			return nil, err
+		}
+		if !utf8.Valid(body) || bytes.IndexByte(body, 0) >= 0 {
+			return nil, fmt.Errorf("binary file %s", target.path)
+		}
+		content = codeBlockHTML(string(body), languageForFile(name))
+	}
+
+	return &ArticleContent{
+		Title:       fmt.Sprintf("%s/%s: %s", target.owner, target.repo, target.path),
+		Content:     content,
+		ContentType: "html",
+		Metadata:    ArticleMetadata{SiteName: "GitHub"},
+		baseURL:     gitHubRepoURL(target, "raw", target.ref, path.Dir(target.path)),
+	}, nil
+}
+
+// gitHubIssue é a resposta de /issues/{número}, que também serve para pull requests
+type gitHubIssue struct {
+	Title     string     `json:"title"`
+	Number    int        `json:"number"`
+	State     string     `json:"state"`
+	Body      string     `json:"body"`
+	BodyHTML  string     `json:"body_html"`
+	User      gitHubUser `json:"user"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// fetchGitHubIssue busca a descrição de uma issue ou pull request
+func fetchGitHubIssue(ctx context.Context, target gitHubTarget) (*ArticleContent, error) {
+	apiPath := fmt.Sprintf("/repos/%s/issues/%d", escapePathSegments(target.owner, target.repo), target.number)
+	body, err := gitHubAPI(ctx, apiPath, "application/vnd.github.full+json")
+	if err != nil {
+		return nil, err
+	}
+
+	var issue gitHubIssue
+	if err := json.Unmarshal(body, &issue); err != nil {
+		return nil, fmt.Errorf("failed to decode issue response: %w", err)
+	}
+
+	content := issue.BodyHTML
+	if content == "" && issue.Body != "" {
+		content = markdownToHTML(issue.Body)
+	}
+	if strings.TrimSpace(content) == "" {
+		return nil, fmt.Errorf("issue #%d has no description", target.number)
+	}
+
+	kind := "Issue"
+	if target.kind == gitHubPullPage {
+		kind = "Pull Request"
+	}
+
+	return &ArticleContent{
+		Title:       fmt.Sprintf("%s · %s #%d · %s/%s", issue.Title, kind, target.number, target.owner, target.repo),
+		Content:     content,
+		ContentType: "html",
+		Metadata:    gitHubMetadata(issue.User, issue.CreatedAt, issue.UpdatedAt),
+		baseURL:     gitHubRepoURL(target),
+	}, nil
+}
+
+// gitHubRelease é a resposta de /releases
+type gitHubRelease struct {
+	Name        string     `json:"name"`
+	TagName     string     `json:"tag_name"`
+	Body        string     `json:"body"`
+	BodyHTML    string     `json:"body_html"`
+	Author      gitHubUser `json:"author"`
+	CreatedAt   time.Time  `json:"created_at"`
+	PublishedAt time.Time  `json:"published_at"`
+}
+
+// fetchGitHubRelease busca as notas de uma release (ou da última)
+func fetchGitHubRelease(ctx context.Context, target gitHubTarget) (*ArticleContent, error) {
+	apiPath := "/repos/" + escapePathSegments(target.owner, target.repo) + "/releases/latest"
+	if target.tag != "" {
+		apiPath = "/repos/" + escapePathSegments(target.owner, target.repo) + "/releases/tags/" + url.PathEscape(target.tag)
+	}
+	body, err := gitHubAPI(ctx, apiPath, "application/vnd.github.full+json")
+	if err != nil {
+		return nil, err
+	}
+
+	var release gitHubRelease
+	if err := json.Unmarshal(body, &release); err != nil {
+		return nil, fmt.Errorf("failed to decode release response: %w", err)
+	}
+
+	content := release.BodyHTML
+	if content == "" && release.Body != "" {
+		content = markdownToHTML(release.Body)
+	}
+	if strings.TrimSpace(content) == "" {
+		return nil, fmt.Errorf("release %s has no notes", release.TagName)
+	}
+
+	name := release.Name
+	if name == "" {
+		name = release.TagName
+	}
+	published := release.PublishedAt
+	if published.IsZero() {
+		published = release.CreatedAt
+	}
+
+	return &ArticleContent{
+		Title:       fmt.Sprintf("%s/%s %s", target.owner, target.repo, name),
+		Content:     content,
+		ContentType: "html",
+		Metadata:    gitHubMetadata(release.Author, published, time.Time{}),
+		baseURL:     gitHubRepoURL(target),
+	}, nil
+}
+
+// gitHubGist é a resposta de /gists/{id}
+type gitHubGist struct {
+	Description string     `json:"description"`
+	Owner       gitHubUser `json:"owner"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	Files       map[string]struct {
+		Filename string `json:"filename"`
+		Language string `json:"language"`
+		Content  string `json:"content"`
+	} `json:"files"`
+}
+
+// fetchGitHubGist busca os arquivos do gist, um bloco por arquivo
+func fetchGitHubGist(ctx context.Context, target gitHubTarget) (*ArticleContent, error) {
+	body, err := gitHubAPI(ctx, "/gists/"+url.PathEscape(target.gistID), "application/vnd.github+json")
+	if err != nil {
+		return nil, err
+	}
+
+	var gist gitHubGist
+	if err := json.Unmarshal(body, &gist); err != nil {
+		return nil, fmt.Errorf("failed to decode gist response: %w", err)
+	}
+	if len(gist.Files) == 0 {
+		return nil, fmt.Errorf("gist %s has no files", target.gistID)
+	}
+
+	// A API devolve os arquivos num objeto; o GitHub os exibe por nome
+	names := make([]string, 0, len(gist.Files))
+	for name := range gist.Files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var content strings.Builder
+	for _, name := range names {
+		file := gist.Files[name]
+		content.WriteString("<h2>" + html.EscapeString(file.Filename) + "</h2>\n")
+		if isMarkdownFile(file.Filename) {
+			content.WriteString(markdownToHTML(file.Content))
+		} else {
+			language := gistLanguage(file.Language)
+			if language == "" {
+				language = languageForFile(file.Filename)
+			}
+			content.WriteString(codeBlockHTML(file.Content, language))
+		}
+	}
+
+	title := gist.Description
+	if title == "" {
+		title = names[0]
+	}
+
+	return &ArticleContent{
+		Title:       title,
+		Content:     content.String(),
+		ContentType: "html",
+		Metadata:    gitHubMetadata(gist.Owner, gist.CreatedAt, gist.UpdatedAt),
+		baseURL:     &url.URL{Scheme: "https", Host: "gist.github.com", Path: "/" + target.gistID},
+	}, nil
+}
+
+// gitHubAPI faz um GET na API do GitHub e devolve o corpo da resposta
+func gitHubAPI(ctx context.Context, apiPath, accept string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", gitHubAPIURL+apiPath, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GitHub API request: %w", err)
+	}
+	req.Header.Set("Accept", accept)
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+
+	resp, err := defaultFetcher.doAPI(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GitHub API returned %d for %s", resp.StatusCode, apiPath)
+	}
+
+	body, err := readLimited(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read GitHub API response: %w", err)
+	}
+	return body, nil
+}
+
+// gitHubRenderedFile lê a resposta de /readme ou /contents pedida como HTML. Quando
+// a API devolve o JSON do arquivo (base64) em vez do HTML, o Markdown é convertido aqui.
+func gitHubRenderedFile(body []byte) (string, error) {
+	if !bytes.HasPrefix(bytes.TrimSpace(body), []byte("{")) {
+		return string(body), nil
+	}
+
+	var file gitHubContentFile
+	if err := json.Unmarshal(body, &file); err != nil {
+		return "", fmt.Errorf("failed to decode GitHub content response: %w", err)
+	}
+	text, err := decodeGitHubContent(file)
+	if err != nil {
+		return "", err
+	}
+	return renderGitHubFile(file.Name, text), nil
+}
+
+// decodeGitHubContent decodifica o arquivo devolvido pela API de conteúdo
+func decodeGitHubContent(file gitHubContentFile) (string, error) {
+	if file.Encoding != "base64" {
+		return file.Content, nil
+	}
+	// A API quebra o base64 em linhas
+	decoded, err := base64.StdEncoding.DecodeString(strings.NewReplacer("\n", "", "\r", "").Replace(file.Content))
+	if err != nil {
+		return "", fmt.Errorf("failed to decode %s: %w", file.Name, err)
+	}
+	return string(decoded), nil
+}
+
+// renderGitHubFile converte o arquivo em HTML: Markdown é renderizado e o resto
+// (README.rst, README.txt...) vira texto pré-formatado
+func renderGitHubFile(name, text string) string {
+	if isMarkdownFile(name) || path.Ext(name) == "" {
+		return markdownToHTML(text)
+	}
+	return codeBlockHTML(text, "")
+}
+
+// gitHubMetadata monta os metadados de issues, releases e gists
+func gitHubMetadata(author gitHubUser, published, modified time.Time) ArticleMetadata {
+	metadata := ArticleMetadata{SiteName: "GitHub", PublishedAt: published, ModifiedAt: modified}
+	if author.Login != "" {
+		metadata.Authors = []string{author.Login}
+	}
+	return metadata
+}
+
+// gitHubRepoURL monta a URL de uma página do repositório em github.com
+func gitHubRepoURL(target gitHubTarget, segments ...string) *url.URL {
+	p := path.Join(append([]string{"/", target.owner, target.repo}, segments...)...)
+	return &url.URL{Scheme: "https", Host: "github.com", Path: p + "/"}
+}
+
+// escapePathSegments escapa e junta os segmentos de um caminho da API
+func escapePathSegments(segments ...string) string {
+	escaped := make([]string, len(segments))
+	for i, segment := range segments {
+		escaped[i] = url.PathEscape(segment)
+	}
+	return strings.Join(escaped, "/")
+}
+
+// isMarkdownFile reconhece arquivos Markdown pela extensão
+func isMarkdownFile(name string) bool {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".md", ".markdown", ".mdown", ".mkd":
+		return true
+	}
+	return false
+}
+
+// fileLanguages associa extensões às classes language-* do realce de sintaxe
+var fileLanguages = map[string]string{
+	".go": "go", ".py": "python", ".rb": "ruby", ".rs": "rust", ".java": "java",
+	".kt": "kotlin", ".swift": "swift", ".c": "c", ".h": "c", ".cc": "cpp", ".cpp": "cpp",
+	".hpp": "cpp", ".cs": "csharp", ".php": "php", ".js": "javascript", ".mjs": "javascript",
+	".jsx": "jsx", ".ts": "typescript", ".tsx": "tsx", ".css": "css", ".scss": "scss",
+	".html": "html", ".xml": "xml", ".json": "json", ".yml": "yaml", ".yaml": "yaml",
+	".toml": "toml", ".sql": "sql", ".sh": "bash", ".bash": "bash", ".zsh": "bash",
+	".ps1": "powershell", ".lua": "lua", ".ex": "elixir", ".exs": "elixir", ".erl": "erlang",
+	".hs": "haskell", ".scala": "scala", ".clj": "clojure", ".dart": "dart", ".r": "r",
+	".proto": "protobuf", ".tf": "hcl", ".vue": "vue", ".zig": "zig",
+}
+
+// languageForFile devolve a linguagem do arquivo pelo nome ou extensão ("" se desconhecida)
+func languageForFile(name string) string {
+	switch strings.ToLower(name) {
+	case "dockerfile":
+		return "dockerfile"
+	case "makefile":
+		return "makefile"
+	}
+	return fileLanguages[strings.ToLower(path.Ext(name))]
+}
+
+// gistLanguage converte o nome de linguagem do GitHub ("Shell", "C++") numa classe
+func gistLanguage(language string) string {
+	language = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(language), " ", "-"))
+	if language != "" && !codeClassPattern.MatchString("language-"+language) {
+		return ""
+	}
+	return language
+}
+
+// extractGitHubHTMLContent extrai conteúdo HTML do GitHub
+func extractGitHubHTMLContent(doc *goquery.Document) string {
+	// README renderizado
+	if content, _ := doc.Find(".markdown-body").First().Html(); content != "" {
+		return content
+	}
+
+	// Box do README
+	if content, _ := doc.Find("#readme .Box-body").Html(); content != "" {
+		return content
+	}
+
+	// Arquivo markdown
+	if content, _ := doc.Find("[data-target='readme-toc.content']").Html(); content != "" {
+		return content
+	}
+
+	// Issues/PRs
+	if content, _ := doc.Find(".comment-body").First().Html(); content != "" {
+		return content
+	}
+
+	return ""
+}
@@ -0,0 +1,206 @@
+package scraper
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+// newGitHubStandIn sobe um servidor local no lugar da API do GitHub e aponta o
+// extrator para ele durante o teste
+func newGitHubStandIn(t *testing.T, routes map[string]http.HandlerFunc) {
+	t.Helper()
+	log.SetLevel(logrus.ErrorLevel)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handler, ok := routes[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		handler(w, r)
+	}))
+
+	previousFetcher, previousAPI := defaultFetcher, gitHubAPIURL
+	SetDefaultFetcher(newTestFetcher(server, FetcherConfig{IgnoreRobots: true}))
+	SetGitHubAPIURL(server.URL)
+	t.Cleanup(func() {
+		server.Close()
+		SetDefaultFetcher(previousFetcher)
+		gitHubAPIURL = previousAPI
+	})
+}
+
+func writeJSON(w http.ResponseWriter, value interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(w).Encode(value)
+}
+
+func TestParseGitHubURL(t *testing.T) {
+	tests := []struct {
+		url  string
+		want gitHubTarget
+		ok   bool
+	}{
+		{"https://github.com/golang/go", gitHubTarget{kind: gitHubRepoPage, owner: "golang", repo: "go"}, true},
+		{"https://github.com/golang/go/tree/master/src/net", gitHubTarget{kind: gitHubRepoPage, owner: "golang", repo: "go", ref: "master", path: "src/net"}, true},
+		{"https://github.com/golang/go/blob/go1.22/src/fmt/print.go", gitHubTarget{kind: gitHubBlobPage, owner: "golang", repo: "go", ref: "go1.22", path: "src/fmt/print.go"}, true},
+		{"https://github.com/golang/go/issues/42", gitHubTarget{kind: gitHubIssuePage, owner: "golang", repo: "go", number: 42}, true},
+		{"https://github.com/golang/go/pull/7/files", gitHubTarget{kind: gitHubPullPage, owner: "golang", repo: "go", number: 7}, true},
+		{"https://github.com/golang/go/releases/tag/v1.0.0", gitHubTarget{kind: gitHubReleasePage, owner: "golang", repo: "go", tag: "v1.0.0"}, true},
+		{"https://github.com/golang/go/releases/latest", gitHubTarget{kind: gitHubReleasePage, owner: "golang", repo: "go"}, true},
+		{"https://gist.github.com/someone/aa5a315d61ae9438b18d", gitHubTarget{kind: gitHubGistPage, gistID: "aa5a315d61ae9438b18d"}, true},
+		{"https://gist.github.com/someone", gitHubTarget{}, false},
+		{"https://github.com/golang/go/wiki", gitHubTarget{}, false},
+		{"https://github.com/topics/go", gitHubTarget{}, false},
+		{"https://github.com/golang", gitHubTarget{}, false},
+	}
+
+	for _, tt := range tests {
+		u, _ := url.Parse(tt.url)
+		got, ok := parseGitHubURL(u)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("parseGitHubURL(%q) = %+v, %v; want %+v, %v", tt.url, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestGitHubReadmeDecodesBase64Markdown(t *testing.T) {
+	markdown := "# Project\n\nA **fast** tool. See ![logo](docs/logo.png).\n\n```go\nfmt.Println(\"hi\")\n```\n"
+	newGitHubStandIn(t, map[string]http.HandlerFunc{
+		"/repos/acme/tool/readme": func(w http.ResponseWriter, r *http.Request) {
+			// Wrap em 60 colunas, como a API faz
+			encoded := base64.StdEncoding.EncodeToString([]byte(markdown))
+			writeJSON(w, map[string]string{
+				"name":     "README.md",
+				"encoding": "base64",
+				"content":  encoded[:60] + "\n" + encoded[60:],
+			})
+		},
+	})
+
+	content, err := defaultRegistry.Fetch(context.Background(), "https://github.com/acme/tool")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{
+		"<h1>Project</h1>",
+		"<strong>fast</strong>",
+		`src="https://github.com/acme/tool/raw/HEAD/docs/logo.png"`,
+		`<code class="language-go">`,
+	} {
+		if !strings.Contains(content.Content, want) {
+			t.Errorf("content missing %q:\n%s", want, content.Content)
+		}
+	}
+	if content.Title != "acme/tool README" {
+		t.Errorf("title = %q", content.Title)
+	}
+}
+
+func TestGitHubReadmeUsesRenderedHTML(t *testing.T) {
+	newGitHubStandIn(t, map[string]http.HandlerFunc{
+		"/repos/acme/tool/readme/docs": func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get("ref") != "v2" {
+				t.Errorf("ref = %q, want v2", r.URL.Query().Get("ref"))
+			}
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			w.Write([]byte(`<div id="readme"><article class="markdown-body"><h2>Docs</h2><p>Rendered by GitHub.</p></article></div>`))
+		},
+	})
+
+	content, err := defaultRegistry.Fetch(context.Background(), "https://github.com/acme/tool/tree/v2/docs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(content.Content, "<h2>Docs</h2>") || !strings.Contains(content.Content, "Rendered by GitHub.") {
+		t.Errorf("content = %q", content.Content)
+	}
+}
+
+func TestGitHubBlobKeepsLanguageClass(t *testing.T) {
+	newGitHubStandIn(t, map[string]http.HandlerFunc{
+		"/repos/acme/tool/contents/cmd/main.go": func(w http.ResponseWriter, r *http.Request) {
+			if !strings.Contains(r.Header.Get("Accept"), "raw") {
+				t.Errorf("Accept = %q, want raw media type", r.Header.Get("Accept"))
+			}
+			w.Write([]byte("package main\n\nfunc main() { println(1 < 2) }\n"))
+		},
+	})
+
+	content, err := defaultRegistry.Fetch(context.Background(), "https://github.com/acme/tool/blob/main/cmd/main.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// O realce sobrevive ao sanitizador
+	want := `<pre><code class="language-go"><span class="tok-keyword">package</span> main`
+	if !strings.Contains(content.Content, want) || !strings.Contains(content.Content, `<span class="tok-number">1</span> &lt; <span class="tok-number">2</span>`) {
+		t.Errorf("content = %q", content.Content)
+	}
+}
+
+func TestGitHubIssueBodyAndMetadata(t *testing.T) {
+	newGitHubStandIn(t, map[string]http.HandlerFunc{
+		"/repos/acme/tool/issues/12": func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(w, map[string]interface{}{
+				"title":      "Crash on empty input",
+				"number":     12,
+				"body":       "Steps:\n\n1. run\n2. crash",
+				"user":       map[string]string{"login": "octocat"},
+				"created_at": "2024-03-01T10:00:00Z",
+				"updated_at": "2024-03-02T10:00:00Z",
+			})
+		},
+	})
+
+	content, err := defaultRegistry.Fetch(context.Background(), "https://github.com/acme/tool/pull/12")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if content.Title != "Crash on empty input · Pull Request #12 · acme/tool" {
+		t.Errorf("title = %q", content.Title)
+	}
+	if !strings.Contains(content.Content, "<ol>") {
+		t.Errorf("markdown body not converted: %q", content.Content)
+	}
+	metadata := content.Metadata
+	if len(metadata.Authors) != 1 || metadata.Authors[0] != "octocat" || metadata.PublishedAt.IsZero() || metadata.ModifiedAt.IsZero() {
+		t.Errorf("metadata = %+v", metadata)
+	}
+}
+
+func TestGitHubGistRendersEveryFile(t *testing.T) {
+	newGitHubStandIn(t, map[string]http.HandlerFunc{
+		"/gists/aa5a315d61ae9438b18d": func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(w, map[string]interface{}{
+				"description": "Handy scripts",
+				"owner":       map[string]string{"login": "octocat"},
+				"files": map[string]interface{}{
+					"notes.md":  map[string]string{"filename": "notes.md", "language": "Markdown", "content": "## Notes"},
+					"deploy.sh": map[string]string{"filename": "deploy.sh", "language": "Shell", "content": "echo ok"},
+				},
+			})
+		},
+	})
+
+	content, err := defaultRegistry.Fetch(context.Background(), "https://gist.github.com/octocat/aa5a315d61ae9438b18d")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if content.Title != "Handy scripts" {
+		t.Errorf("title = %q", content.Title)
+	}
+	for _, want := range []string{"<h2>deploy.sh</h2>", `<code class="language-shell">echo ok</code>`, "<h2>Notes</h2>"} {
+		if !strings.Contains(content.Content, want) {
+			t.Errorf("content missing %q:\n%s", want, content.Content)
+		}
+	}
+}
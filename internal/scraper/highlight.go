@@ -0,0 +1,378 @@
+package scraper
+
+import (
+	"html"
+	"strings"
+	"unicode/utf8"
+)
+
+// Realce de sintaxe feito no servidor: o código vira spans com classes tok-*
+// (palavras-chave, strings, comentários, números e constantes), estilizadas pelo
+// frontend. É um tokenizador simples por família de linguagens, não um parser;
+// linguagens desconhecidas ficam só escapadas.
+
+// Classes dos tokens (as únicas aceitas em <span> pelo sanitizador)
+const (
+	tokKeyword  = "tok-keyword"
+	tokString   = "tok-string"
+	tokComment  = "tok-comment"
+	tokNumber   = "tok-number"
+	tokConstant = "tok-constant"
+)
+
+// tokenClasses são as classes de token mantidas pelo sanitizador
+var tokenClasses = map[string]bool{
+	tokKeyword: true, tokString: true, tokComment: true, tokNumber: true, tokConstant: true,
+}
+
+// gitHubTokenClasses traduz as classes do realce do GitHub (READMEs renderizados)
+var gitHubTokenClasses = map[string]string{
+	"pl-k":   tokKeyword,
+	"pl-s":   tokString,
+	"pl-pds": tokString,
+	"pl-sr":  tokString,
+	"pl-c":   tokComment,
+	"pl-c1":  tokConstant,
+}
+
+// syntax descreve a sintaxe léxica de uma família de linguagens
+type syntax struct {
+	lineComments  []string
+	blockComments [][2]string
+	quotes        string // Delimitadores de string de uma linha
+	multiline     string // Delimitadores de string que podem ocupar várias linhas
+	rawQuote      byte   // Delimitador sem escapes (` no Go)
+	tripleQuotes  bool   // """ e ''' do Python
+	charQuote     bool   // ' só delimita caracteres curtos ('a', '\n'), não lifetimes do Rust
+	ignoreCase    bool   // Palavras-chave sem diferenciar maiúsculas (SQL)
+	keywords      map[string]bool
+	constants     map[string]bool
+}
+
+func words(list string) map[string]bool {
+	set := make(map[string]bool)
+	for _, word := range strings.Fields(list) {
+		set[word] = true
+	}
+	return set
+}
+
+var (
+	cConstants      = words("true false null nullptr nil NULL undefined this self super None True False")
+	scriptConstants = words("true false nil null None True False undefined")
+
+	cLike = func(keywords string) *syntax {
+		return &syntax{
+			lineComments:  []string{"//"},
+			blockComments: [][2]string{{"/*", "*/"}},
+			quotes:        `"'`,
+			keywords:      words(keywords),
+			constants:     cConstants,
+		}
+	}
+	hashComment = func(keywords string) *syntax {
+		return &syntax{
+			lineComments: []string{"#"},
+			quotes:       `"'`,
+			keywords:     words(keywords),
+			constants:    scriptConstants,
+		}
+	}
+
+	jsKeywords = "async await break case catch class const continue debugger default delete do else export extends finally for from function if import in instanceof let new of return static switch throw try typeof var void while with yield"
+	tsKeywords = jsKeywords + " abstract as declare enum implements interface keyof namespace private protected public readonly type"
+)
+
+// syntaxes associa as classes language-* (ver fileLanguages) às sintaxes
+var syntaxes = map[string]*syntax{
+	"go": func() *syntax {
+		s := cLike("break case chan const continue default defer else fallthrough for func go goto if import interface map package range return select struct switch type var")
+		s.rawQuote = '`'
+		return s
+	}(),
+	"c":      cLike("auto break case char const continue default do double else enum extern float for goto if inline int long register return short signed sizeof static struct switch typedef union unsigned void volatile while"),
+	"cpp":    cLike("auto bool break case catch char class const constexpr continue default delete do double else enum explicit extern float for friend if inline int long namespace new operator private protected public return short signed sizeof static struct switch template throw try typedef typename union unsigned using virtual void volatile while"),
+	"java":   cLike("abstract boolean break byte case catch char class continue default do double else enum extends final finally float for if implements import instanceof int interface long new package private protected public return short static super switch synchronized throw throws try void volatile while var record"),
+	"csharp": cLike("abstract as async await base bool break case catch class const continue decimal default delegate do double else enum event explicit extern finally fixed float for foreach if implicit in int interface internal is lock long namespace new object operator out override params private protected public readonly ref return sealed short sizeof static string struct switch throw try typeof uint ulong using var virtual void volatile while"),
+	"kotlin": cLike("as break class continue do else false for fun if in interface is null object package return super this throw true try typealias val var when while data sealed override open private protected public internal companion import"),
+	"swift":  cLike("as break case catch class continue default defer do else enum extension fileprivate for func guard if import in init inout internal let private protocol public repeat return self static struct switch throw throws try var where while"),
+	"rust": func() *syntax {
+		s := cLike("as async await break const continue crate dyn else enum extern fn for if impl in let loop match mod move mut pub ref return self Self static struct super trait type unsafe use where while")
+		s.charQuote = true
+		return s
+	}(),
+	"scala":    cLike("abstract case catch class def do else extends final finally for if implicit import lazy match new object override package private protected return sealed super throw trait try type val var while with yield"),
+	"dart":     cLike("abstract as async await break case catch class const continue default do else enum extends final finally for if import in is late new required return static super switch throw try var void while with"),
+	"zig":      cLike("const var fn pub return if else while for break continue switch struct enum union error try catch defer errdefer comptime test unreachable"),
+	"protobuf": cLike("syntax package import option message enum service rpc returns repeated optional required oneof map reserved"),
+	"javascript": func() *syntax {
+		s := cLike(jsKeywords)
+		s.multiline = "`"
+		return s
+	}(),
+	"typescript": func() *syntax {
+		s := cLike(tsKeywords)
+		s.multiline = "`"
+		return s
+	}(),
+	"php": func() *syntax {
+		s := cLike("abstract and array as break case catch class clone const continue declare default do echo else elseif extends final finally fn for foreach function global if implements include interface namespace new or private protected public require return static switch throw trait try use var while yield")
+		s.lineComments = append(s.lineComments, "#")
+		return s
+	}(),
+	"python": func() *syntax {
+		s := hashComment("and as assert async await break class continue def del elif else except finally for from global if import in is lambda nonlocal not or pass raise return try while with yield")
+		s.tripleQuotes = true
+		return s
+	}(),
+	"ruby":       hashComment("alias and begin break case class def defined do else elsif end ensure for if in module next not or redo rescue retry return self super then undef unless until when while yield"),
+	"bash":       hashComment("case do done elif else esac export fi for function if in local return select then until while"),
+	"powershell": hashComment("begin break catch continue do else elseif end exit filter finally for foreach function if in param process return switch throw trap try until while"),
+	"elixir":     hashComment("after alias and catch cond def defmodule defp do else end fn for if import in not or quote raise receive require rescue try unless use when with"),
+	"r":          hashComment("break else for function if in next repeat return while"),
+	"yaml":       hashComment(""),
+	"toml":       hashComment(""),
+	"makefile":   hashComment("ifeq ifneq ifdef ifndef else endif include define endef export"),
+	"dockerfile": func() *syntax {
+		s := hashComment("FROM RUN CMD LABEL EXPOSE ENV ADD COPY ENTRYPOINT VOLUME USER WORKDIR ARG ONBUILD STOPSIGNAL HEALTHCHECK SHELL AS")
+		s.ignoreCase = true
+		return s
+	}(),
+	"hcl": func() *syntax {
+		s := hashComment("resource data variable output module provider locals terraform for in if")
+		s.lineComments = append(s.lineComments, "//")
+		s.blockComments = [][2]string{{"/*", "*/"}}
+		return s
+	}(),
+	"sql": {
+		lineComments:  []string{"--"},
+		blockComments: [][2]string{{"/*", "*/"}},
+		quotes:        `'"`,
+		ignoreCase:    true,
+		keywords:      words("add all alter and as asc begin between by case check column commit constraint create default delete desc distinct drop else end exists foreign from group having if in index inner insert into is join key left like limit not null offset on or order outer primary references right rollback select set table then union unique update values view when where with"),
+		constants:     words("true false null"),
+	},
+	"lua": {
+		lineComments:  []string{"--"},
+		blockComments: [][2]string{{"--[[", "]]"}},
+		quotes:        `"'`,
+		keywords:      words("and break do else elseif end for function goto if in local not or repeat return then until while"),
+		constants:     words("true false nil"),
+	},
+	"haskell": {
+		lineComments:  []string{"--"},
+		blockComments: [][2]string{{"{-", "-}"}},
+		quotes:        `"`,
+		keywords:      words("case class data deriving do else if import in infix instance let module newtype of then type where"),
+		constants:     words("True False Nothing"),
+	},
+	"json": {
+		quotes:    `"`,
+		constants: words("true false null"),
+	},
+	"css": {
+		blockComments: [][2]string{{"/*", "*/"}},
+		quotes:        `"'`,
+	},
+}
+
+func init() {
+	// Variantes que compartilham a sintaxe
+	for alias, language := range map[string]string{
+		"jsx": "javascript", "js": "javascript", "tsx": "typescript", "ts": "typescript",
+		"scss": "css", "shell": "bash", "sh": "bash", "zsh": "bash", "py": "python", "rb": "ruby",
+		"c++": "cpp", "c#": "csharp", "golang": "go", "yml": "yaml", "terraform": "hcl",
+		"vue": "javascript",
+	} {
+		if s, ok := syntaxes[language]; ok {
+			syntaxes[alias] = s
+		}
+	}
+}
+
+// highlightCode devolve o código escapado, com os tokens envolvidos em spans
+func highlightCode(code, language string) string {
+	s := syntaxes[strings.ToLower(language)]
+	if s == nil {
+		return html.EscapeString(code)
+	}
+
+	var out strings.Builder
+	emit := func(class, text string) {
+		if class == "" {
+			out.WriteString(html.EscapeString(text))
+			return
+		}
+		out.WriteString(`<span class="` + class + `">` + html.EscapeString(text) + "</span>")
+	}
+
+	plainStart := 0
+	flush := func(i int) {
+		if i > plainStart {
+			emit("", code[plainStart:i])
+		}
+	}
+
+	for i := 0; i < len(code); {
+		// Comentários
+		if end, ok := s.comment(code, i); ok {
+			flush(i)
+			emit(tokComment, code[i:end])
+			i, plainStart = end, end
+			continue
+		}
+
+		// Strings
+		if end, ok := s.stringAt(code, i); ok {
+			flush(i)
+			emit(tokString, code[i:end])
+			i, plainStart = end, end
+			continue
+		}
+
+		c := code[i]
+		prevIdent := i > 0 && isIdentChar(code[i-1])
+
+		// Números (não colados a um identificador, como em "utf8")
+		if c >= '0' && c <= '9' && !prevIdent {
+			end := i + 1
+			for end < len(code) && (isIdentChar(code[end]) || code[end] == '.') {
+				end++
+			}
+			flush(i)
+			emit(tokNumber, code[i:end])
+			i, plainStart = end, end
+			continue
+		}
+
+		// Palavras-chave e constantes
+		if isIdentStart(c) && !prevIdent {
+			end := i + 1
+			for end < len(code) && isIdentChar(code[end]) {
+				end++
+			}
+			word := code[i:end]
+			lookup := word
+			if s.ignoreCase {
+				lookup = strings.ToLower(word)
+			}
+			class := ""
+			switch {
+			case s.keywords[lookup] || (s.ignoreCase && s.keywords[strings.ToUpper(word)]):
+				class = tokKeyword
+			case s.constants[lookup]:
+				class = tokConstant
+			}
+			if class != "" {
+				flush(i)
+				emit(class, word)
+				plainStart = end
+			}
+			i = end
+			continue
+		}
+
+		i++
+	}
+	flush(len(code))
+	return out.String()
+}
+
+// comment reconhece um comentário começando em i e devolve onde ele termina
+func (s *syntax) comment(code string, i int) (int, bool) {
+	for _, block := range s.blockComments {
+		if strings.HasPrefix(code[i:], block[0]) {
+			if end := strings.Index(code[i+len(block[0]):], block[1]); end >= 0 {
+				return i + len(block[0]) + end + len(block[1]), true
+			}
+			return len(code), true
+		}
+	}
+	for _, prefix := range s.lineComments {
+		if strings.HasPrefix(code[i:], prefix) {
+			// "#" só abre comentário no início de palavra (não em "a#b" nem em "$#")
+			if prefix == "#" && i > 0 && !isCommentBoundary(code[i-1]) {
+				continue
+			}
+			if end := strings.IndexByte(code[i:], '\n'); end >= 0 {
+				return i + end, true
+			}
+			return len(code), true
+		}
+	}
+	return 0, false
+}
+
+// stringAt reconhece uma string começando em i e devolve onde ela termina
+func (s *syntax) stringAt(code string, i int) (int, bool) {
+	c := code[i]
+
+	if s.tripleQuotes && (strings.HasPrefix(code[i:], `"""`) || strings.HasPrefix(code[i:], "'''")) {
+		delim := code[i : i+3]
+		if end := strings.Index(code[i+3:], delim); end >= 0 {
+			return i + 3 + end + 3, true
+		}
+		return len(code), true
+	}
+
+	if s.rawQuote != 0 && c == s.rawQuote {
+		if end := strings.IndexByte(code[i+1:], c); end >= 0 {
+			return i + 1 + end + 1, true
+		}
+		return len(code), true
+	}
+
+	multiline := strings.IndexByte(s.multiline, c) >= 0
+	if !multiline && strings.IndexByte(s.quotes, c) < 0 {
+		return 0, false
+	}
+	// Apóstrofo colado a uma palavra não abre string (sufixos, lifetimes do Rust)
+	if c == '\'' && i > 0 && isIdentChar(code[i-1]) {
+		return 0, false
+	}
+
+	if s.charQuote && c == '\'' {
+		// Um único caractere ou um escape ('\u{1F600}' é o mais longo)
+		if i+1 < len(code) && code[i+1] != '\\' {
+			_, size := utf8.DecodeRuneInString(code[i+1:])
+			if strings.HasPrefix(code[i+1+size:], "'") {
+				return i + 1 + size + 1, true
+			}
+			return 0, false
+		}
+		if end := strings.IndexByte(code[min(i+2, len(code)):min(i+12, len(code))], '\''); end >= 0 {
+			return i + 2 + end + 1, true
+		}
+		return 0, false
+	}
+
+	for j := i + 1; j < len(code); j++ {
+		switch code[j] {
+		case '\\':
+			j++
+		case c:
+			return j + 1, true
+		case '\n':
+			if !multiline {
+				// String sem fechamento na linha: não é string (ex.: 'a em Rust)
+				return 0, false
+			}
+		}
+	}
+	if multiline {
+		return len(code), true
+	}
+	return 0, false
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isIdentChar(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+func isCommentBoundary(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == ';' || c == '(' || c == '{'
+}
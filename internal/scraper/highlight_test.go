@@ -0,0 +1,32 @@
+package scraper
+
+import "testing"
+
+func TestHighlightCode(t *testing.T) {
+	tests := []struct {
+		name, language, code, want string
+	}{
+		{"go", "go", "func f() string { return `a\\` + \"<b>\" } // fim",
+			`<span class="tok-keyword">func</span> f() string { <span class="tok-keyword">return</span> <span class="tok-string">` + "`a\\`" + `</span> + <span class="tok-string">&#34;&lt;b&gt;&#34;</span> } <span class="tok-comment">// fim</span>`},
+		{"escaped quote", "javascript", `const s = "a\"b"; let n = 0x1F`,
+			`<span class="tok-keyword">const</span> s = <span class="tok-string">&#34;a\&#34;b&#34;</span>; <span class="tok-keyword">let</span> n = <span class="tok-number">0x1F</span>`},
+		{"number inside identifier", "go", "utf8 := x2", "utf8 := x2"},
+		{"python triple quotes", "python", "def f():\n    \"\"\"doc\n    more\"\"\"\n    return None",
+			"<span class=\"tok-keyword\">def</span> f():\n    <span class=\"tok-string\">&#34;&#34;&#34;doc\n    more&#34;&#34;&#34;</span>\n    <span class=\"tok-keyword\">return</span> <span class=\"tok-constant\">None</span>"},
+		{"shell hash inside word", "shell", "echo a#b # nota",
+			`echo a#b <span class="tok-comment"># nota</span>`},
+		{"sql ignores case", "sql", "SELECT id FROM t -- x",
+			`<span class="tok-keyword">SELECT</span> id <span class="tok-keyword">FROM</span> t <span class="tok-comment">-- x</span>`},
+		{"rust lifetime", "rust", "fn f<'a>(x: &'a str)",
+			`<span class="tok-keyword">fn</span> f&lt;&#39;a&gt;(x: &amp;&#39;a str)`},
+		{"unknown language", "brainfuck", "<+>", "&lt;+&gt;"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := highlightCode(tt.code, tt.language); got != tt.want {
+				t.Errorf("highlightCode() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,339 @@
+package scraper
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ResponseCache guarda em disco as páginas baixadas (corpo e headers), pela URL
+// final após os redirects. Serve para revalidar com If-None-Match/If-Modified-Since
+// em vez de baixar de novo, e para extrair outra vez o conteúdo sem acessar a rede
+// quando as regras de extração mudam. O tamanho total é limitado, removendo as
+// páginas acessadas há mais tempo (LRU).
+type ResponseCache struct {
+	dir      string
+	maxBytes int64
+
+	mu      sync.Mutex
+	entries map[string]*cacheEntry // Por chave (hash da URL final)
+	aliases map[string]string      // URL pedida -> chave
+	lru     *list.List             // Chaves; a frente é a mais recente
+	size    int64
+}
+
+// ErrNotCached indica que a página não está no cache
+var ErrNotCached = errors.New("page not in cache")
+
+// cacheEntry é uma página no índice em memória
+type cacheEntry struct {
+	meta cacheMeta
+	elem *list.Element
+}
+
+// cacheMeta é gravado ao lado do corpo (<chave>.json)
+type cacheMeta struct {
+	URL          string      `json:"url"`          // URL final
+	RequestURLs  []string    `json:"request_urls"` // URLs pedidas que levaram a ela
+	Header       http.Header `json:"header"`
+	ETag         string      `json:"etag,omitempty"`
+	LastModified string      `json:"last_modified,omitempty"`
+	StoredAt     time.Time   `json:"stored_at"`
+	CheckedAt    time.Time   `json:"checked_at"` // Última revalidação
+	Size         int64       `json:"size"`
+}
+
+// cachedPage é uma página lida do cache
+type cachedPage struct {
+	URL          *url.URL
+	Header       http.Header
+	Body         []byte
+	ETag         string
+	LastModified string
+}
+
+// NewResponseCache abre (ou cria) o cache no diretório, com limite em bytes
+func NewResponseCache(dir string, maxBytes int64) (*ResponseCache, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	c := &ResponseCache{
+		dir:      dir,
+		maxBytes: maxBytes,
+		entries:  make(map[string]*cacheEntry),
+		aliases:  make(map[string]string),
+		lru:      list.New(),
+	}
+	if err := c.load(); err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.evict()
+	c.mu.Unlock()
+
+	log.Infof("HTTP cache loaded: %d pages, %d bytes", len(c.entries), c.size)
+	return c, nil
+}
+
+// load reconstrói o índice a partir dos arquivos; a data de modificação do .json
+// registra o último acesso (ordem do LRU)
+func (c *ResponseCache) load() error {
+	files, err := filepath.Glob(filepath.Join(c.dir, "*.json"))
+	if err != nil {
+		return fmt.Errorf("failed to list cache directory: %w", err)
+	}
+
+	type loaded struct {
+		key      string
+		meta     cacheMeta
+		accessed time.Time
+	}
+	var pages []loaded
+	for _, file := range files {
+		key := strings.TrimSuffix(filepath.Base(file), ".json")
+		info, err := os.Stat(file)
+		if err != nil {
+			continue
+		}
+		data, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		var meta cacheMeta
+		if err := json.Unmarshal(data, &meta); err != nil {
+			log.Warnf("Removing corrupt cache entry %s: %v", key, err)
+			c.removeFiles(key)
+			continue
+		}
+		if _, err := os.Stat(c.bodyPath(key)); err != nil {
+			c.removeFiles(key)
+			continue
+		}
+		pages = append(pages, loaded{key: key, meta: meta, accessed: info.ModTime()})
+	}
+
+	// Mais recentes primeiro, para PushBack montar a lista na ordem do LRU
+	sort.Slice(pages, func(i, j int) bool { return pages[i].accessed.After(pages[j].accessed) })
+	for _, page := range pages {
+		entry := &cacheEntry{meta: page.meta}
+		entry.elem = c.lru.PushBack(page.key)
+		c.entries[page.key] = entry
+		c.size += page.meta.Size
+		for _, requestURL := range page.meta.RequestURLs {
+			c.aliases[requestURL] = page.key
+		}
+		c.aliases[page.meta.URL] = page.key
+	}
+	return nil
+}
+
+// lookup busca a página pela URL pedida ou pela URL final
+func (c *ResponseCache) lookup(rawURL string) (*cachedPage, bool) {
+	c.mu.Lock()
+	key, ok := c.aliases[rawURL]
+	var entry *cacheEntry
+	if ok {
+		entry, ok = c.entries[key]
+	}
+	if !ok {
+		c.mu.Unlock()
+		return nil, false
+	}
+	meta := entry.meta
+	c.lru.MoveToFront(entry.elem)
+	c.mu.Unlock()
+
+	body, err := os.ReadFile(c.bodyPath(key))
+	if err != nil {
+		log.Warnf("Cached body for %s is unreadable: %v", meta.URL, err)
+		c.remove(key)
+		return nil, false
+	}
+	pageURL, err := url.Parse(meta.URL)
+	if err != nil {
+		c.remove(key)
+		return nil, false
+	}
+
+	now := time.Now()
+	os.Chtimes(c.metaPath(key), now, now)
+
+	return &cachedPage{
+		URL:          pageURL,
+		Header:       meta.Header,
+		Body:         body,
+		ETag:         meta.ETag,
+		LastModified: meta.LastModified,
+	}, true
+}
+
+// store grava a página baixada; requestURL é a URL pedida antes dos redirects
+func (c *ResponseCache) store(requestURL string, finalURL *url.URL, header http.Header, body []byte) {
+	key := cacheKey(finalURL.String())
+
+	header = header.Clone()
+	header.Del("Set-Cookie")
+
+	meta := cacheMeta{
+		URL:          finalURL.String(),
+		Header:       header,
+		ETag:         header.Get("ETag"),
+		LastModified: header.Get("Last-Modified"),
+		StoredAt:     time.Now(),
+		CheckedAt:    time.Now(),
+		Size:         int64(len(body)),
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Páginas maiores que o cache inteiro não são guardadas
+	if meta.Size > c.maxBytes {
+		return
+	}
+
+	if old, ok := c.entries[key]; ok {
+		meta.RequestURLs = old.meta.RequestURLs
+		c.size -= old.meta.Size
+		c.lru.Remove(old.elem)
+		delete(c.entries, key)
+	}
+	if requestURL != meta.URL && !containsString(meta.RequestURLs, requestURL) {
+		meta.RequestURLs = append(meta.RequestURLs, requestURL)
+	}
+
+	if err := writeFileAtomic(c.bodyPath(key), body); err != nil {
+		log.Warnf("Failed to cache %s: %v", meta.URL, err)
+		return
+	}
+	if err := c.writeMeta(key, meta); err != nil {
+		log.Warnf("Failed to cache %s: %v", meta.URL, err)
+		os.Remove(c.bodyPath(key))
+		return
+	}
+
+	entry := &cacheEntry{meta: meta, elem: c.lru.PushFront(key)}
+	c.entries[key] = entry
+	c.size += meta.Size
+	c.aliases[meta.URL] = key
+	for _, alias := range meta.RequestURLs {
+		c.aliases[alias] = key
+	}
+
+	c.evict()
+}
+
+// revalidated registra que o servidor confirmou a página (304) e associa a URL pedida
+func (c *ResponseCache) revalidated(requestURL string, page *cachedPage) {
+	key := cacheKey(page.URL.String())
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return
+	}
+	entry.meta.CheckedAt = time.Now()
+	if requestURL != entry.meta.URL && !containsString(entry.meta.RequestURLs, requestURL) {
+		entry.meta.RequestURLs = append(entry.meta.RequestURLs, requestURL)
+		c.aliases[requestURL] = key
+	}
+	if err := c.writeMeta(key, entry.meta); err != nil {
+		log.Warnf("Failed to update cache entry for %s: %v", entry.meta.URL, err)
+	}
+}
+
+// evict remove as páginas menos usadas até caber no limite (chamado com o lock)
+func (c *ResponseCache) evict() {
+	for c.size > c.maxBytes && c.lru.Len() > 0 {
+		key := c.lru.Back().Value.(string)
+		c.removeLocked(key)
+	}
+}
+
+// remove tira a página do índice e do disco
+func (c *ResponseCache) remove(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.removeLocked(key)
+}
+
+func (c *ResponseCache) removeLocked(key string) {
+	entry, ok := c.entries[key]
+	if !ok {
+		return
+	}
+	c.lru.Remove(entry.elem)
+	delete(c.entries, key)
+	c.size -= entry.meta.Size
+	delete(c.aliases, entry.meta.URL)
+	for _, alias := range entry.meta.RequestURLs {
+		if c.aliases[alias] == key {
+			delete(c.aliases, alias)
+		}
+	}
+	c.removeFiles(key)
+}
+
+func (c *ResponseCache) removeFiles(key string) {
+	os.Remove(c.bodyPath(key))
+	os.Remove(c.metaPath(key))
+}
+
+func (c *ResponseCache) writeMeta(key string, meta cacheMeta) error {
+	data, err := json.Marshal(meta)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(c.metaPath(key), data)
+}
+
+func (c *ResponseCache) bodyPath(key string) string { return filepath.Join(c.dir, key+".html") }
+func (c *ResponseCache) metaPath(key string) string { return filepath.Join(c.dir, key+".json") }
+
+// cacheKey deriva o nome dos arquivos da URL final
+func cacheKey(pageURL string) string {
+	sum := sha256.Sum256([]byte(pageURL))
+	return hex.EncodeToString(sum[:16])
+}
+
+// writeFileAtomic grava em arquivo temporário e renomeia (nunca deixa arquivo pela metade)
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "cache-*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
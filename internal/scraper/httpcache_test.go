@@ -0,0 +1,157 @@
+package scraper
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestResponseCacheLookupByRequestURL(t *testing.T) {
+	log.SetLevel(logrus.ErrorLevel)
+
+	cache, err := NewResponseCache(t.TempDir(), 1<<20)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	final, _ := url.Parse("https://example.com/post")
+	header := http.Header{"Etag": {`"v1"`}, "Set-Cookie": {"session=secret"}}
+	cache.store("https://example.com/p?id=1", final, header, []byte("<html>post</html>"))
+
+	for _, rawURL := range []string{"https://example.com/p?id=1", "https://example.com/post"} {
+		page, ok := cache.lookup(rawURL)
+		if !ok {
+			t.Fatalf("lookup(%q) missed", rawURL)
+		}
+		if page.URL.String() != final.String() || string(page.Body) != "<html>post</html>" || page.ETag != `"v1"` {
+			t.Errorf("lookup(%q) = %+v", rawURL, page)
+		}
+		if page.Header.Get("Set-Cookie") != "" {
+			t.Errorf("Set-Cookie was cached")
+		}
+	}
+}
+
+func TestResponseCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	log.SetLevel(logrus.ErrorLevel)
+
+	cache, err := NewResponseCache(t.TempDir(), 25)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body := []byte("0123456789")
+	for _, path := range []string{"/a", "/b"} {
+		pageURL, _ := url.Parse("https://example.com" + path)
+		cache.store(pageURL.String(), pageURL, http.Header{}, body)
+	}
+
+	// /a foi acessada por último: /b é quem sai quando /c entra
+	cache.lookup("https://example.com/a")
+	pageURL, _ := url.Parse("https://example.com/c")
+	cache.store(pageURL.String(), pageURL, http.Header{}, body)
+
+	for path, want := range map[string]bool{"/a": true, "/b": false, "/c": true} {
+		if _, ok := cache.lookup("https://example.com" + path); ok != want {
+			t.Errorf("%s cached = %v, want %v", path, ok, want)
+		}
+	}
+}
+
+func TestResponseCacheReloadsFromDisk(t *testing.T) {
+	log.SetLevel(logrus.ErrorLevel)
+
+	dir := t.TempDir()
+	cache, err := NewResponseCache(dir, 1<<20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	final, _ := url.Parse("https://example.com/post")
+	cache.store("https://example.com/short", final, http.Header{}, []byte("<p>body</p>"))
+
+	reopened, err := NewResponseCache(dir, 1<<20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	page, ok := reopened.lookup("https://example.com/short")
+	if !ok || string(page.Body) != "<p>body</p>" {
+		t.Errorf("page not restored from disk: %+v, %v", page, ok)
+	}
+}
+
+func TestFetchPageRevalidatesCachedCopy(t *testing.T) {
+	log.SetLevel(logrus.ErrorLevel)
+
+	var full, notModified atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		full.Add(1)
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte("<html><body><p>cached article</p></body></html>"))
+	}))
+	defer server.Close()
+
+	cache, err := NewResponseCache(t.TempDir(), 1<<20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := newTestFetcher(server, FetcherConfig{IgnoreRobots: true, Cache: cache})
+
+	for i := 0; i < 2; i++ {
+		page, err := f.fetchPage(context.Background(), server.URL+"/article", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(page.Body), "cached article") {
+			t.Errorf("fetch %d body = %q", i, page.Body)
+		}
+	}
+
+	if full.Load() != 1 || notModified.Load() != 1 {
+		t.Errorf("full responses = %d, 304 responses = %d, want 1 and 1", full.Load(), notModified.Load())
+	}
+}
+
+func TestFetchPageFallsBackToCacheOnServerError(t *testing.T) {
+	log.SetLevel(logrus.ErrorLevel)
+
+	var down atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if down.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte("<p>still here</p>"))
+	}))
+	defer server.Close()
+
+	cache, err := NewResponseCache(t.TempDir(), 1<<20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := newTestFetcher(server, FetcherConfig{IgnoreRobots: true, Cache: cache})
+
+	if _, err := f.fetchPage(context.Background(), server.URL+"/article", nil); err != nil {
+		t.Fatal(err)
+	}
+	down.Store(true)
+	page, err := f.fetchPage(context.Background(), server.URL+"/article", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(page.Body) != "<p>still here</p>" {
+		t.Errorf("body = %q, want cached copy", page.Body)
+	}
+}
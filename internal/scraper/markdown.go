@@ -0,0 +1,227 @@
+package scraper
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+// Conversão de Markdown para HTML usada quando o GitHub devolve o texto sem
+// renderizar (README em base64, corpo de issues e gists). Cobre o que aparece em
+// READMEs: títulos, parágrafos, listas, citações, blocos de código, imagens, links
+// e ênfase. HTML embutido passa adiante; o sanitizador decide o que fica.
+
+var (
+	headingPattern     = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	fencePattern       = regexp.MustCompile("^(```+|~~~+)\\s*([A-Za-z0-9_+#-]*)")
+	ruleLinePattern    = regexp.MustCompile(`^(\*\s*){3,}$|^(-\s*){3,}$|^(_\s*){3,}$`)
+	bulletItemPattern  = regexp.MustCompile(`^\s{0,3}[-*+]\s+(.*)$`)
+	orderedItemPattern = regexp.MustCompile(`^\s{0,3}(\d{1,9})[.)]\s+(.*)$`)
+
+	codeSpanPattern = regexp.MustCompile("(`+)(.+?)(`+)")
+	imagePattern    = regexp.MustCompile(`!\[([^\]]*)\]\(\s*([^)\s]+)(?:\s+"([^"]*)")?\s*\)`)
+	linkPattern     = regexp.MustCompile(`\[([^\]]+)\]\(\s*([^)\s]+)(?:\s+"([^"]*)")?\s*\)`)
+	autolinkPattern = regexp.MustCompile(`&lt;(https?://[^\s&]+)&gt;`)
+	strongPattern   = regexp.MustCompile(`\*\*(.+?)\*\*|__(.+?)__`)
+	emphasisPattern = regexp.MustCompile(`\*([^*\s][^*]*?)\*`)
+	strikePattern   = regexp.MustCompile(`~~(.+?)~~`)
+	htmlTagPattern  = regexp.MustCompile(`^</?[A-Za-z][A-Za-z0-9-]*(\s[^<>]*)?/?>|^<!--.*?-->`)
+)
+
+// markdownToHTML converte o Markdown para HTML
+func markdownToHTML(src string) string {
+	lines := strings.Split(strings.ReplaceAll(src, "\r\n", "\n"), "\n")
+	var out strings.Builder
+	renderMarkdownBlocks(&out, lines)
+	return out.String()
+}
+
+// renderMarkdownBlocks percorre as linhas agrupando-as em blocos
+func renderMarkdownBlocks(out *strings.Builder, lines []string) {
+	for i := 0; i < len(lines); {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case trimmed == "":
+			i++
+
+		case fencePattern.MatchString(trimmed):
+			match := fencePattern.FindStringSubmatch(trimmed)
+			fence := match[1]
+			var code []string
+			for i++; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), fence); i++ {
+				code = append(code, lines[i])
+			}
+			i++ // Fechamento da cerca
+			out.WriteString(codeBlockHTML(strings.Join(code, "\n"), match[2]))
+
+		case headingPattern.MatchString(trimmed):
+			match := headingPattern.FindStringSubmatch(trimmed)
+			level := string(rune('0' + len(match[1])))
+			out.WriteString("<h" + level + ">" + renderInline(match[2]) + "</h" + level + ">\n")
+			i++
+
+		case ruleLinePattern.MatchString(trimmed):
+			out.WriteString("<hr>\n")
+			i++
+
+		case strings.HasPrefix(trimmed, ">"):
+			var quoted []string
+			for ; i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), ">"); i++ {
+				quoted = append(quoted, strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(lines[i]), ">"), " "))
+			}
+			out.WriteString("<blockquote>\n")
+			renderMarkdownBlocks(out, quoted)
+			out.WriteString("</blockquote>\n")
+
+		case bulletItemPattern.MatchString(line) || orderedItemPattern.MatchString(line):
+			i = renderList(out, lines, i)
+
+		case strings.HasPrefix(trimmed, "<") && htmlTagPattern.MatchString(trimmed):
+			// Bloco HTML: segue até a próxima linha em branco
+			for ; i < len(lines) && strings.TrimSpace(lines[i]) != ""; i++ {
+				out.WriteString(lines[i] + "\n")
+			}
+
+		default:
+			var paragraph []string
+			for ; i < len(lines) && startsParagraphLine(lines[i]); i++ {
+				paragraph = append(paragraph, strings.TrimSpace(lines[i]))
+			}
+			out.WriteString("<p>" + renderInline(strings.Join(paragraph, "\n")) + "</p>\n")
+		}
+	}
+}
+
+// startsParagraphLine indica se a linha continua o parágrafo (não abre outro bloco)
+func startsParagraphLine(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	return trimmed != "" &&
+		!fencePattern.MatchString(trimmed) &&
+		!headingPattern.MatchString(trimmed) &&
+		!strings.HasPrefix(trimmed, ">") &&
+		!bulletItemPattern.MatchString(line) &&
+		!orderedItemPattern.MatchString(line)
+}
+
+// renderList converte uma lista (um nível; linhas recuadas continuam o item)
+// e devolve o índice da primeira linha depois dela
+func renderList(out *strings.Builder, lines []string, i int) int {
+	ordered := orderedItemPattern.MatchString(lines[i]) && !bulletItemPattern.MatchString(lines[i])
+	tag := "ul"
+	if ordered {
+		tag = "ol"
+	}
+	out.WriteString("<" + tag + ">\n")
+
+	var item []string
+	flush := func() {
+		if item != nil {
+			out.WriteString("<li>" + renderInline(strings.Join(item, "\n")) + "</li>\n")
+		}
+	}
+	for ; i < len(lines); i++ {
+		line := lines[i]
+		if ordered {
+			if match := orderedItemPattern.FindStringSubmatch(line); match != nil {
+				flush()
+				item = []string{match[2]}
+				continue
+			}
+		} else if match := bulletItemPattern.FindStringSubmatch(line); match != nil {
+			flush()
+			item = []string{match[1]}
+			continue
+		}
+		if strings.TrimSpace(line) == "" || !strings.HasPrefix(line, " ") {
+			break
+		}
+		item = append(item, strings.TrimSpace(line))
+	}
+	flush()
+
+	out.WriteString("</" + tag + ">\n")
+	return i
+}
+
+// codeBlockHTML monta o bloco de código com a classe language-* e os tokens realçados
+func codeBlockHTML(code, language string) string {
+	if language != "" {
+		return `<pre><code class="language-` + html.EscapeString(strings.ToLower(language)) + `">` +
+			highlightCode(code, language) + "</code></pre>\n"
+	}
+	return "<pre><code>" + html.EscapeString(code) + "</code></pre>\n"
+}
+
+// renderInline converte código, imagens, links e ênfase dentro de um bloco
+func renderInline(text string) string {
+	var out strings.Builder
+	for {
+		loc := codeSpanPattern.FindStringSubmatchIndex(text)
+		// A crase de fechamento precisa ter o mesmo tamanho da de abertura
+		if loc == nil || text[loc[2]:loc[3]] != text[loc[6]:loc[7]] {
+			out.WriteString(renderInlineText(text))
+			break
+		}
+		out.WriteString(renderInlineText(text[:loc[0]]))
+		out.WriteString("<code>" + html.EscapeString(strings.TrimSpace(text[loc[4]:loc[5]])) + "</code>")
+		text = text[loc[1]:]
+	}
+	return out.String()
+}
+
+// renderInlineText trata o texto fora de trechos de código
+func renderInlineText(text string) string {
+	text = escapeMarkdownText(text)
+	text = imagePattern.ReplaceAllStringFunc(text, func(m string) string {
+		match := imagePattern.FindStringSubmatch(m)
+		img := `<img src="` + escapeQuotes(match[2]) + `" alt="` + escapeQuotes(match[1]) + `"`
+		if match[3] != "" {
+			img += ` title="` + escapeQuotes(match[3]) + `"`
+		}
+		return img + ">"
+	})
+	text = linkPattern.ReplaceAllStringFunc(text, func(m string) string {
+		match := linkPattern.FindStringSubmatch(m)
+		link := `<a href="` + escapeQuotes(match[2]) + `"`
+		if match[3] != "" {
+			link += ` title="` + escapeQuotes(match[3]) + `"`
+		}
+		return link + ">" + match[1] + "</a>"
+	})
+	text = autolinkPattern.ReplaceAllString(text, `<a href="$1">$1</a>`)
+	text = strongPattern.ReplaceAllString(text, "<strong>$1$2</strong>")
+	text = emphasisPattern.ReplaceAllString(text, "<em>$1</em>")
+	text = strikePattern.ReplaceAllString(text, "<del>$1</del>")
+	return text
+}
+
+// escapeMarkdownText escapa &, < e > mas preserva tags HTML embutidas no texto
+func escapeMarkdownText(text string) string {
+	var out strings.Builder
+	for i := 0; i < len(text); {
+		switch text[i] {
+		case '<':
+			if tag := htmlTagPattern.FindString(text[i:]); tag != "" {
+				out.WriteString(tag)
+				i += len(tag)
+				continue
+			}
+			out.WriteString("&lt;")
+		case '>':
+			out.WriteString("&gt;")
+		case '&':
+			out.WriteString("&amp;")
+		default:
+			out.WriteByte(text[i])
+		}
+		i++
+	}
+	return out.String()
+}
+
+// escapeQuotes escapa as aspas de um valor usado em atributo
+func escapeQuotes(value string) string {
+	return strings.ReplaceAll(value, `"`, "&#34;")
+}
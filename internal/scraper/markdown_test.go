@@ -0,0 +1,34 @@
+package scraper
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMarkdownToHTML(t *testing.T) {
+	src := "# Title\n\nText with `a<b`, [a link](https://example.com/?a=1&b=2 \"Example\") and <https://auto.example>.\n\n" +
+		"- one\n- two\n  continued\n\n> quoted\n\n```python\nprint(\"<x>\")\n```\n\n<p align=\"center\"><img src=\"logo.png\"></p>\n"
+
+	got := markdownToHTML(src)
+	for _, want := range []string{
+		"<h1>Title</h1>",
+		"<code>a&lt;b</code>",
+		`<a href="https://example.com/?a=1&amp;b=2" title="Example">a link</a>`,
+		`<a href="https://auto.example">https://auto.example</a>`,
+		"<li>two\ncontinued</li>",
+		"<blockquote>\n<p>quoted</p>\n</blockquote>",
+		`<pre><code class="language-python">print(<span class="tok-string">&#34;&lt;x&gt;&#34;</span>)</code></pre>`,
+		`<p align="center"><img src="logo.png"></p>`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %q in:\n%s", want, got)
+		}
+	}
+}
+
+func TestMarkdownEscapesPlainText(t *testing.T) {
+	got := markdownToHTML("5 > 3 & 2 < 4")
+	if got != "<p>5 &gt; 3 &amp; 2 &lt; 4</p>\n" {
+		t.Errorf("got %q", got)
+	}
+}
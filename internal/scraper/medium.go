@@ -0,0 +1,357 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+func init() {
+	Register(mediumExtractor{})
+}
+
+// MediumDirect é a estratégia que acessa o próprio site, sem espelho
+const MediumDirect = "direct"
+
+// MediumDefaultFamily é a família usada pelos hosts sem cadeia própria (inclusive
+// publicações em domínio próprio detectadas pelas meta tags)
+const MediumDefaultFamily = "default"
+
+// MediumConfig define os espelhos que removem o paywall e a ordem em que as
+// estratégias são tentadas para cada família de hosts
+type MediumConfig struct {
+	// Mirrors associa o nome do espelho ao modelo da URL: {url} é a URL do artigo,
+	// {host} o host, {path} o caminho com a query (sem a barra inicial) e {id} o ID
+	// do post no Medium
+	Mirrors map[string]string
+
+	// Strategies associa a família (um domínio, que vale também para os subdomínios,
+	// ou "default") às estratégias na ordem de tentativa: nomes de espelhos ou "direct"
+	Strategies map[string][]string
+}
+
+// DefaultMediumConfig tenta o Freedium, o Scribe e por fim o acesso direto
+func DefaultMediumConfig() MediumConfig {
+	return MediumConfig{
+		Mirrors: map[string]string{
+			"freedium": "https://freedium.cfd/{url}",
+			"scribe":   "https://scribe.rip/p/{id}",
+		},
+		Strategies: map[string][]string{
+			MediumDefaultFamily: {"freedium", "scribe", MediumDirect},
+		},
+	}
+}
+
+// ParseMediumConfig monta a configuração a partir de espelhos
+// ("freedium=https://freedium.cfd/{url},meu=https://espelho.exemplo/{path}") e de
+// cadeias por família ("medium.com=scribe,direct;default=freedium,direct").
+// Espelhos e famílias informados substituem os padrões de mesmo nome.
+func ParseMediumConfig(mirrors, strategies string) (MediumConfig, error) {
+	cfg := DefaultMediumConfig()
+
+	for _, pair := range strings.Split(mirrors, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		name, template, found := strings.Cut(pair, "=")
+		name = strings.ToLower(strings.TrimSpace(name))
+		template = strings.TrimSpace(template)
+		if !found || name == "" || name == MediumDirect {
+			return cfg, fmt.Errorf("invalid mirror %q", pair)
+		}
+		if !strings.HasPrefix(template, "https://") && !strings.HasPrefix(template, "http://") {
+			return cfg, fmt.Errorf("invalid template for mirror %s: must be an http(s) URL", name)
+		}
+		cfg.Mirrors[name] = template
+	}
+
+	for _, family := range strings.Split(strategies, ";") {
+		family = strings.TrimSpace(family)
+		if family == "" {
+			continue
+		}
+		key, list, found := strings.Cut(family, "=")
+		key = strings.ToLower(strings.TrimSpace(key))
+		if !found || key == "" {
+			return cfg, fmt.Errorf("invalid strategy chain %q", family)
+		}
+
+		var chain []string
+		for _, name := range strings.Split(list, ",") {
+			name = strings.ToLower(strings.TrimSpace(name))
+			if name == "" {
+				continue
+			}
+			if _, ok := cfg.Mirrors[name]; !ok && name != MediumDirect {
+				return cfg, fmt.Errorf("unknown strategy %q for %s", name, key)
+			}
+			chain = append(chain, name)
+		}
+		if len(chain) == 0 {
+			return cfg, fmt.Errorf("empty strategy chain for %s", key)
+		}
+		cfg.Strategies[key] = chain
+	}
+
+	return cfg, nil
+}
+
+var (
+	mediumMu     sync.RWMutex
+	mediumConfig = DefaultMediumConfig()
+
+	// mediumPublications são os domínios próprios já reconhecidos como publicações do Medium
+	mediumPublications = make(map[string]bool)
+
+	// mediumStats acompanha o resultado de cada estratégia
+	mediumStats = newStrategyTracker()
+)
+
+// ConfigureMedium troca os espelhos e as cadeias de estratégias (chamado na inicialização)
+func ConfigureMedium(cfg MediumConfig) {
+	mediumMu.Lock()
+	defer mediumMu.Unlock()
+	mediumConfig = cfg
+}
+
+// MediumStrategyStats devolve o sucesso e a falha acumulados de cada estratégia
+func MediumStrategyStats() []StrategyStats {
+	return mediumStats.snapshot()
+}
+
+// mediumDomains são os domínios atendidos sempre pelo extrator do Medium
+var mediumDomains = []string{"medium.com", "towardsdatascience.com", "levelup.gitconnected.com", "betterprogramming.pub"}
+
+// mediumExtractor busca artigos do Medium e das publicações hospedadas nele,
+// seguindo a cadeia de estratégias (espelhos e acesso direto) configurada
+type mediumExtractor struct{}
+
+func (mediumExtractor) Name() string  { return "medium" }
+func (mediumExtractor) Priority() int { return 10 }
+
+func (mediumExtractor) Match(u *url.URL) bool {
+	if hostMatches(u, mediumDomains...) {
+		return true
+	}
+
+	mediumMu.RLock()
+	defer mediumMu.RUnlock()
+	if mediumPublications[normalizedHost(u)] {
+		return true
+	}
+	for family := range mediumConfig.Strategies {
+		if family != MediumDefaultFamily && hostMatches(u, family) {
+			return true
+		}
+	}
+	return false
+}
+
+func (mediumExtractor) Fetch(ctx context.Context, articleURL string) (*ArticleContent, error) {
+	parsedURL, _ := url.Parse(articleURL)
+	return fetchMediumArticle(ctx, parsedURL, mediumPostIDFromURL(parsedURL), nil)
+}
+
+func (mediumExtractor) ExtractContent(doc *goquery.Document) string {
+	return extractMediumContent(doc)
+}
+
+// fetchMediumArticle tenta as estratégias da família do host até uma trazer o
+// artigo completo. direct é a página já baixada pelo extrator genérico, quando foi
+// ele quem reconheceu a publicação; nesse caso ela é usada como o acesso direto.
+func fetchMediumArticle(ctx context.Context, articleURL *url.URL, postID string, direct *ArticleContent) (*ArticleContent, error) {
+	mirrors, chain := mediumChain(articleURL)
+
+	for _, strategy := range mediumStats.order(chain) {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		var content *ArticleContent
+		var err error
+		minLength := 500
+
+		if strategy == MediumDirect {
+			minLength = 200
+			content = direct
+			if content == nil {
+				content, err = tryFetchWithHeaders(ctx, articleURL.String(), map[string]string{
+					"Accept":                    "text/html,application/xhtml+xml,application/xml;q=0.9,image/avif,image/webp,*/*;q=0.8",
+					"Accept-Language":           "en-US,en;q=0.9",
+					"Accept-Encoding":           "gzip",
+					"Upgrade-Insecure-Requests": "1",
+					"Sec-Fetch-Dest":            "document",
+					"Sec-Fetch-Mode":            "navigate",
+					"Sec-Fetch-Site":            "cross-site",
+					"Referer":                   "https://www.google.com/",
+				}, extractMediumContent)
+			}
+		} else {
+			mirrorURL, ok := expandMirrorTemplate(mirrors[strategy], articleURL, postID)
+			if !ok {
+				log.Debugf("Skipping %s for %s: post ID unknown", strategy, articleURL)
+				continue
+			}
+			content, err = tryFetchWithHeaders(ctx, mirrorURL, map[string]string{
+				"Accept":          "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8",
+				"Accept-Language": "en-US,en;q=0.9",
+				"Accept-Encoding": "gzip",
+				"Referer":         "https://www.google.com/",
+			}, extractMediumContent)
+			if content != nil {
+				content.CanonicalURL = "" // A URL canônica seria a do espelho
+			}
+		}
+
+		if err == nil && content != nil && len(content.Content) > minLength {
+			mediumStats.record(strategy, true)
+			log.Infof("Fetched Medium article via %s: %s", strategy, articleURL)
+			return content, nil
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		mediumStats.record(strategy, false)
+		if err == nil {
+			err = fmt.Errorf("content too short")
+		}
+		log.Warnf("Medium strategy %s failed for %s: %v", strategy, articleURL, err)
+	}
+
+	// A página da publicação, mesmo com o paywall, é melhor que nada
+	if direct != nil {
+		return direct, nil
+	}
+	return nil, fmt.Errorf("could not fetch Medium article after trying %d strategies", len(chain))
+}
+
+// mediumChain devolve os espelhos e a cadeia da família mais específica do host
+func mediumChain(u *url.URL) (map[string]string, []string) {
+	mediumMu.RLock()
+	defer mediumMu.RUnlock()
+
+	best := MediumDefaultFamily
+	for family := range mediumConfig.Strategies {
+		if family == MediumDefaultFamily || !hostMatches(u, family) {
+			continue
+		}
+		if best == MediumDefaultFamily || len(family) > len(best) {
+			best = family
+		}
+	}
+
+	chain := mediumConfig.Strategies[best]
+	if len(chain) == 0 {
+		chain = []string{MediumDirect}
+	}
+	return mediumConfig.Mirrors, chain
+}
+
+// expandMirrorTemplate monta a URL do espelho; false quando o modelo usa {id} e o
+// ID do post não é conhecido
+func expandMirrorTemplate(template string, articleURL *url.URL, postID string) (string, bool) {
+	if template == "" || (strings.Contains(template, "{id}") && postID == "") {
+		return "", false
+	}
+
+	path := strings.TrimPrefix(articleURL.EscapedPath(), "/")
+	if articleURL.RawQuery != "" {
+		path += "?" + articleURL.RawQuery
+	}
+	return strings.NewReplacer(
+		"{url}", articleURL.String(),
+		"{host}", articleURL.Host,
+		"{path}", path,
+		"{id}", postID,
+	).Replace(template), true
+}
+
+var (
+	// mediumPostIDPattern é o ID hexadecimal no fim do slug ("titulo-do-post-1a2b3c4d5e6f")
+	mediumPostIDPattern = regexp.MustCompile(`(?:^|-)([0-9a-f]{8,12})$`)
+
+	// mediumAppURLPattern é o deep link do app do Medium nas meta tags ("medium://p/1a2b3c4d5e6f")
+	mediumAppURLPattern = regexp.MustCompile(`^medium://p/([0-9a-f]{8,12})$`)
+)
+
+// mediumPostIDFromURL extrai o ID do post do caminho (/p/<id> ou fim do slug)
+func mediumPostIDFromURL(u *url.URL) string {
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	last := segments[len(segments)-1]
+	if match := mediumPostIDPattern.FindStringSubmatch(last); match != nil {
+		return match[1]
+	}
+	return ""
+}
+
+// detectMediumPost reconhece páginas servidas pelo Medium (inclusive publicações em
+// domínio próprio) pelo deep link do app em al:android:url ou twitter:app:url:*, e
+// devolve o ID do post
+func detectMediumPost(doc *goquery.Document) string {
+	var postID string
+	doc.Find("meta[property='al:android:url'], meta[property='al:ios:url'], meta[name^='twitter:app:url'], meta[property^='twitter:app:url']").
+		EachWithBreak(func(i int, s *goquery.Selection) bool {
+			if match := mediumAppURLPattern.FindStringSubmatch(strings.TrimSpace(s.AttrOr("content", ""))); match != nil {
+				postID = match[1]
+				return false
+			}
+			return true
+		})
+	return postID
+}
+
+// rememberMediumPublication faz o extrator do Medium atender o domínio daqui em diante
+func rememberMediumPublication(u *url.URL) {
+	host := normalizedHost(u)
+	mediumMu.Lock()
+	defer mediumMu.Unlock()
+	if !mediumPublications[host] {
+		log.Infof("Detected Medium publication on custom domain: %s", host)
+		mediumPublications[host] = true
+	}
+}
+
+// extractMediumContent extrai conteúdo do Medium e proxies
+func extractMediumContent(doc *goquery.Document) string {
+	// Tentar seletores específicos do Scribe.rip (mais limpo)
+	if content, _ := doc.Find(".main-content article").Html(); content != "" && len(content) > 200 {
+		return content
+	}
+
+	// Freedium
+	if content, _ := doc.Find(".main-content").Html(); content != "" && len(content) > 200 {
+		return content
+	}
+
+	// Medium original - section com o artigo
+	if content, _ := doc.Find("article section").Html(); content != "" && len(content) > 200 {
+		return content
+	}
+
+	// Medium - article tag
+	if content, _ := doc.Find("article").Html(); content != "" && len(content) > 200 {
+		return content
+	}
+
+	// Tentar pegar por parágrafos do Medium
+	var paragraphs []string
+	doc.Find("article p, article h1, article h2, article h3, article pre, article code, article ul, article ol, article blockquote, article figure").Each(func(i int, s *goquery.Selection) {
+		if html, _ := s.Html(); html != "" {
+			tagName := goquery.NodeName(s)
+			paragraphs = append(paragraphs, fmt.Sprintf("<%s>%s</%s>", tagName, html, tagName))
+		}
+	})
+
+	if len(paragraphs) > 3 {
+		return strings.Join(paragraphs, "\n")
+	}
+
+	return ""
+}
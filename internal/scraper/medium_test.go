@@ -0,0 +1,185 @@
+package scraper
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+// fullArticle tem texto suficiente para a estratégia ser considerada um sucesso
+var fullArticle = `<html><head><title>Post</title></head><body><div class="main-content"><article>` +
+	strings.Repeat("<p>The whole article is available through this mirror, paragraph after paragraph.</p>", 10) +
+	`</article></div></body></html>`
+
+// useMediumStandIn aponta o fetcher para o servidor do teste e troca a configuração
+// do Medium, restaurando tudo no fim
+func useMediumStandIn(t *testing.T, server *httptest.Server, cfg MediumConfig) {
+	t.Helper()
+	log.SetLevel(logrus.ErrorLevel)
+
+	previousFetcher := defaultFetcher
+	mediumMu.RLock()
+	previousConfig := mediumConfig
+	mediumMu.RUnlock()
+	previousStats := mediumStats
+
+	SetDefaultFetcher(newTestFetcher(server, FetcherConfig{IgnoreRobots: true}))
+	ConfigureMedium(cfg)
+	mediumStats = newStrategyTracker()
+
+	t.Cleanup(func() {
+		SetDefaultFetcher(previousFetcher)
+		ConfigureMedium(previousConfig)
+		mediumStats = previousStats
+		mediumMu.Lock()
+		mediumPublications = make(map[string]bool)
+		mediumMu.Unlock()
+	})
+}
+
+func TestParseMediumConfig(t *testing.T) {
+	cfg, err := ParseMediumConfig(
+		"mirror=https://mirror.example/{path}",
+		"medium.com=mirror,scribe,direct; towardsdatascience.com=direct",
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Mirrors["mirror"] != "https://mirror.example/{path}" || cfg.Mirrors["freedium"] == "" {
+		t.Errorf("mirrors = %v", cfg.Mirrors)
+	}
+	if got := strings.Join(cfg.Strategies["medium.com"], ","); got != "mirror,scribe,direct" {
+		t.Errorf("medium.com chain = %s", got)
+	}
+	if len(cfg.Strategies[MediumDefaultFamily]) == 0 {
+		t.Errorf("default chain was dropped")
+	}
+
+	for _, invalid := range [][2]string{
+		{"direct=https://x.example/{url}", ""},
+		{"bad=ftp://x.example/{url}", ""},
+		{"", "medium.com=nowhere"},
+		{"", "medium.com="},
+	} {
+		if _, err := ParseMediumConfig(invalid[0], invalid[1]); err == nil {
+			t.Errorf("ParseMediumConfig(%q, %q) accepted invalid input", invalid[0], invalid[1])
+		}
+	}
+}
+
+func TestExpandMirrorTemplate(t *testing.T) {
+	articleURL, _ := url.Parse("https://blog.example.com/my-post-1a2b3c4d5e6f?source=rss")
+	postID := mediumPostIDFromURL(articleURL)
+	if postID != "1a2b3c4d5e6f" {
+		t.Fatalf("post ID = %q", postID)
+	}
+
+	tests := map[string]string{
+		"https://freedium.cfd/{url}":  "https://freedium.cfd/https://blog.example.com/my-post-1a2b3c4d5e6f?source=rss",
+		"https://scribe.rip/p/{id}":   "https://scribe.rip/p/1a2b3c4d5e6f",
+		"https://mirror.test/{path}":  "https://mirror.test/my-post-1a2b3c4d5e6f?source=rss",
+		"https://{host}.mirror.test/": "https://blog.example.com.mirror.test/",
+	}
+	for template, want := range tests {
+		if got, ok := expandMirrorTemplate(template, articleURL, postID); !ok || got != want {
+			t.Errorf("expand(%q) = %q, %v; want %q", template, got, ok, want)
+		}
+	}
+
+	if _, ok := expandMirrorTemplate("https://scribe.rip/p/{id}", articleURL, ""); ok {
+		t.Errorf("template with {id} expanded without a post ID")
+	}
+}
+
+func TestMediumDemotesFailingMirror(t *testing.T) {
+	var brokenHits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		if strings.HasPrefix(r.URL.Path, "/broken/") {
+			brokenHits.Add(1)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte(fullArticle))
+	}))
+	defer server.Close()
+
+	useMediumStandIn(t, server, MediumConfig{
+		Mirrors: map[string]string{
+			"broken": server.URL + "/broken/{id}",
+			"good":   server.URL + "/good/{id}",
+		},
+		Strategies: map[string][]string{MediumDefaultFamily: {"broken", "good"}},
+	})
+
+	for i := 0; i < demoteAfterFailures+1; i++ {
+		content, err := defaultRegistry.Fetch(context.Background(), "https://medium.com/@someone/a-post-1a2b3c4d5e6f")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if content.CanonicalURL != "" {
+			t.Errorf("mirror canonical URL kept: %s", content.CanonicalURL)
+		}
+	}
+
+	// Depois de três falhas seguidas o espelho quebrado vai para o fim da fila
+	if brokenHits.Load() != demoteAfterFailures {
+		t.Errorf("broken mirror hit %d times, want %d", brokenHits.Load(), demoteAfterFailures)
+	}
+	for _, s := range MediumStrategyStats() {
+		switch s.Name {
+		case "broken":
+			if !s.Demoted || s.Failures != demoteAfterFailures {
+				t.Errorf("broken stats = %+v", s)
+			}
+		case "good":
+			if s.Demoted || s.Successes != demoteAfterFailures+1 || s.SuccessRate != 1 {
+				t.Errorf("good stats = %+v", s)
+			}
+		}
+	}
+}
+
+func TestGenericDetectsMediumPublication(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		if strings.HasPrefix(r.URL.Path, "/mirror/") {
+			if r.URL.Path != "/mirror/1a2b3c4d5e6f" {
+				t.Errorf("mirror path = %s", r.URL.Path)
+			}
+			w.Write([]byte(fullArticle))
+			return
+		}
+		// Página da publicação com o paywall: só o começo do texto
+		w.Write([]byte(`<html><head><title>Post</title>
+			<meta property="al:android:url" content="medium://p/1a2b3c4d5e6f">
+			<meta name="twitter:app:url:iphone" content="medium://p/1a2b3c4d5e6f">
+			</head><body><article><p>Only the first paragraph is visible to visitors.</p></article></body></html>`))
+	}))
+	defer server.Close()
+
+	useMediumStandIn(t, server, MediumConfig{
+		Mirrors:    map[string]string{"mirror": server.URL + "/mirror/{id}"},
+		Strategies: map[string][]string{MediumDefaultFamily: {MediumDirect, "mirror"}},
+	})
+
+	articleURL := server.URL + "/the-post"
+	content, err := defaultRegistry.Fetch(context.Background(), articleURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(content.Content, "whole article") {
+		t.Errorf("content not fetched through the mirror: %q", content.Content)
+	}
+
+	u, _ := url.Parse(articleURL)
+	if !(mediumExtractor{}).Match(u) {
+		t.Errorf("publication domain was not remembered")
+	}
+}
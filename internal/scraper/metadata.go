@@ -0,0 +1,355 @@
+package scraper
+
+import (
+	"encoding/json"
+	"math"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// wordsPerMinute é a velocidade de leitura usada para estimar o tempo de leitura
+const wordsPerMinute = 230
+
+// maxAuthors limita a lista de autores (páginas de papers listam dezenas)
+const maxAuthors = 10
+
+// ArticleMetadata reúne os metadados declarados pela página. Campos vazios
+// (ou datas zeradas) indicam que a página não os declarou.
+type ArticleMetadata struct {
+	Authors     []string
+	PublishedAt time.Time
+	ModifiedAt  time.Time
+	SiteName    string
+	LeadImage   string // URL absoluta
+	Language    string // Código BCP 47, ex.: "en", "pt-BR"
+	WordCount   int
+	ReadingTime int // Minutos
+}
+
+// dateLayouts são os formatos de data aceitos nos metadados
+var dateLayouts = []string{
+	time.RFC3339Nano,
+	time.RFC3339,
+	"2006-01-02T15:04:05Z0700",
+	"2006-01-02T15:04:05.000Z0700",
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+	time.RFC1123Z,
+	time.RFC1123,
+	"January 2, 2006",
+	"Jan 2, 2006",
+}
+
+// extractMetadata lê os metadados da página, por ordem de confiabilidade:
+// JSON-LD (Article/NewsArticle/BlogPosting), OpenGraph, Twitter cards, <meta> e <time>.
+// Precisa ser chamado antes de removeUnwantedElements, que apaga os <script>.
+func extractMetadata(doc *goquery.Document, pageURL *url.URL) ArticleMetadata {
+	var meta ArticleMetadata
+	ld := findJSONLDArticle(doc)
+
+	// Autores (cada fonte é limpa antes de decidir se a próxima é consultada, pois
+	// article:author costuma trazer só a URL do perfil)
+	meta.Authors = cleanAuthors(jsonLDNames(ld["author"]))
+	for _, selector := range []string{"meta[name='author']", "meta[property='author']", "meta[property='article:author']", "meta[name='dc.creator']"} {
+		if len(meta.Authors) > 0 {
+			break
+		}
+		var authors []string
+		doc.Find(selector).Each(func(i int, s *goquery.Selection) {
+			authors = append(authors, s.AttrOr("content", ""))
+		})
+		meta.Authors = cleanAuthors(authors)
+	}
+	if len(meta.Authors) == 0 && metaContent(doc, "meta[name='twitter:label1']") == "Written by" {
+		meta.Authors = cleanAuthors([]string{metaContent(doc, "meta[name='twitter:data1']")})
+	}
+	if len(meta.Authors) == 0 {
+		var authors []string
+		doc.Find("[rel='author'], [itemprop='author'] [itemprop='name']").Each(func(i int, s *goquery.Selection) {
+			authors = append(authors, s.Text())
+		})
+		meta.Authors = cleanAuthors(authors)
+	}
+
+	// Datas
+	meta.PublishedAt = firstDate(
+		jsonLDString(ld["datePublished"]),
+		metaContent(doc, "meta[property='article:published_time']"),
+		metaContent(doc, "meta[itemprop='datePublished']"),
+		metaContent(doc, "meta[name='date']"),
+		metaContent(doc, "meta[name='dc.date']"),
+		doc.Find("time[itemprop='datePublished']").First().AttrOr("datetime", ""),
+		doc.Find("time[pubdate]").First().AttrOr("datetime", ""),
+		doc.Find("article time[datetime]").First().AttrOr("datetime", ""),
+		doc.Find("time[datetime]").First().AttrOr("datetime", ""),
+	)
+	meta.ModifiedAt = firstDate(
+		jsonLDString(ld["dateModified"]),
+		metaContent(doc, "meta[property='article:modified_time']"),
+		metaContent(doc, "meta[property='og:updated_time']"),
+		metaContent(doc, "meta[itemprop='dateModified']"),
+		doc.Find("time[itemprop='dateModified']").First().AttrOr("datetime", ""),
+	)
+
+	// Nome do site
+	meta.SiteName = firstNonEmpty(
+		jsonLDName(ld["publisher"]),
+		metaContent(doc, "meta[property='og:site_name']"),
+		metaContent(doc, "meta[name='application-name']"),
+	)
+
+	// Imagem principal
+	meta.LeadImage = absoluteURL(pageURL, firstNonEmpty(
+		jsonLDImage(ld["image"]),
+		metaContent(doc, "meta[property='og:image:secure_url']"),
+		metaContent(doc, "meta[property='og:image']"),
+		metaContent(doc, "meta[name='twitter:image']"),
+		metaContent(doc, "meta[name='twitter:image:src']"),
+		metaContent(doc, "meta[property='twitter:image']"),
+	))
+
+	// Idioma
+	meta.Language = normalizeLanguage(firstNonEmpty(
+		jsonLDString(ld["inLanguage"]),
+		doc.Find("html").AttrOr("lang", ""),
+		metaContent(doc, "meta[http-equiv='content-language']"),
+		metaContent(doc, "meta[property='og:locale']"),
+	))
+
+	return meta
+}
+
+// setReadingStats conta as palavras do conteúdo e estima o tempo de leitura
+func (m *ArticleMetadata) setReadingStats(content string) {
+	if content == "" {
+		return
+	}
+	text := content
+	if doc, err := goquery.NewDocumentFromReader(strings.NewReader(content)); err == nil {
+		text = doc.Text()
+	}
+
+	m.WordCount = len(strings.Fields(text))
+	if m.WordCount > 0 {
+		m.ReadingTime = int(math.Ceil(float64(m.WordCount) / wordsPerMinute))
+	}
+}
+
+// findJSONLDArticle devolve o primeiro objeto JSON-LD do tipo artigo da página
+// (procurando também em listas e em @graph)
+func findJSONLDArticle(doc *goquery.Document) map[string]interface{} {
+	var found map[string]interface{}
+	doc.Find("script[type='application/ld+json']").EachWithBreak(func(i int, s *goquery.Selection) bool {
+		raw := strings.TrimSpace(s.Text())
+		raw = strings.TrimSuffix(strings.TrimPrefix(raw, "<!--"), "-->")
+
+		var data interface{}
+		if err := json.Unmarshal([]byte(raw), &data); err != nil {
+			log.Debugf("Ignoring invalid JSON-LD: %v", err)
+			return true
+		}
+		found = findArticleObject(data, 0)
+		return found == nil
+	})
+	return found
+}
+
+// findArticleObject percorre o JSON-LD atrás de um objeto cujo @type seja um artigo
+func findArticleObject(data interface{}, depth int) map[string]interface{} {
+	if depth > 5 {
+		return nil
+	}
+	switch value := data.(type) {
+	case []interface{}:
+		for _, item := range value {
+			if obj := findArticleObject(item, depth+1); obj != nil {
+				return obj
+			}
+		}
+	case map[string]interface{}:
+		if isArticleType(value["@type"]) {
+			return value
+		}
+		if graph, ok := value["@graph"]; ok {
+			return findArticleObject(graph, depth+1)
+		}
+		if entity, ok := value["mainEntity"]; ok {
+			return findArticleObject(entity, depth+1)
+		}
+	}
+	return nil
+}
+
+// isArticleType reconhece Article e seus subtipos (NewsArticle, TechArticle, BlogPosting...)
+func isArticleType(t interface{}) bool {
+	switch value := t.(type) {
+	case string:
+		return strings.HasSuffix(value, "Article") || value == "BlogPosting" ||
+			value == "SocialMediaPosting" || value == "Report"
+	case []interface{}:
+		for _, item := range value {
+			if isArticleType(item) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// jsonLDString lê um valor textual do JSON-LD (o primeiro, se for lista)
+func jsonLDString(v interface{}) string {
+	switch value := v.(type) {
+	case string:
+		return strings.TrimSpace(value)
+	case []interface{}:
+		if len(value) > 0 {
+			return jsonLDString(value[0])
+		}
+	case map[string]interface{}:
+		// Language como objeto: {"@type": "Language", "name": "English", "alternateName": "en"}
+		return firstNonEmpty(jsonLDString(value["alternateName"]), jsonLDString(value["name"]))
+	}
+	return ""
+}
+
+// jsonLDName lê o nome de uma Person/Organization (objeto ou texto)
+func jsonLDName(v interface{}) string {
+	switch value := v.(type) {
+	case string:
+		return strings.TrimSpace(value)
+	case map[string]interface{}:
+		return jsonLDString(value["name"])
+	case []interface{}:
+		if len(value) > 0 {
+			return jsonLDName(value[0])
+		}
+	}
+	return ""
+}
+
+// jsonLDNames lê a lista de autores (texto, objeto ou lista de ambos)
+func jsonLDNames(v interface{}) []string {
+	if list, ok := v.([]interface{}); ok {
+		var names []string
+		for _, item := range list {
+			if name := jsonLDName(item); name != "" {
+				names = append(names, name)
+			}
+		}
+		return names
+	}
+	if name := jsonLDName(v); name != "" {
+		return []string{name}
+	}
+	return nil
+}
+
+// jsonLDImage lê a URL da imagem (texto, ImageObject ou lista)
+func jsonLDImage(v interface{}) string {
+	switch value := v.(type) {
+	case string:
+		return strings.TrimSpace(value)
+	case map[string]interface{}:
+		return firstNonEmpty(jsonLDString(value["url"]), jsonLDString(value["contentUrl"]))
+	case []interface{}:
+		if len(value) > 0 {
+			return jsonLDImage(value[0])
+		}
+	}
+	return ""
+}
+
+// cleanAuthors remove vazios, URLs de perfil, prefixos "By" e duplicados
+func cleanAuthors(authors []string) []string {
+	seen := make(map[string]bool)
+	var cleaned []string
+	for _, author := range authors {
+		author = strings.Join(strings.Fields(author), " ")
+		for _, prefix := range []string{"By ", "by ", "Por ", "por "} {
+			author = strings.TrimPrefix(author, prefix)
+		}
+		if author == "" || strings.HasPrefix(author, "http://") || strings.HasPrefix(author, "https://") {
+			continue
+		}
+		key := strings.ToLower(author)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		cleaned = append(cleaned, author)
+		if len(cleaned) == maxAuthors {
+			break
+		}
+	}
+	return cleaned
+}
+
+// firstDate devolve a primeira data interpretável entre os candidatos
+func firstDate(candidates ...string) time.Time {
+	for _, candidate := range candidates {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "" {
+			continue
+		}
+		for _, layout := range dateLayouts {
+			if t, err := time.Parse(layout, candidate); err == nil {
+				return t
+			}
+		}
+	}
+	return time.Time{}
+}
+
+// normalizeLanguage converte "pt_BR", "en-us" etc. para o formato BCP 47 ("pt-BR", "en-US")
+func normalizeLanguage(lang string) string {
+	lang = strings.TrimSpace(strings.ReplaceAll(lang, "_", "-"))
+	if lang == "" {
+		return ""
+	}
+	parts := strings.Split(lang, "-")
+	parts[0] = strings.ToLower(parts[0])
+	if len(parts) > 1 && len(parts[1]) == 2 {
+		parts[1] = strings.ToUpper(parts[1])
+	}
+	return strings.Join(parts, "-")
+}
+
+// absoluteURL resolve a URL em relação à página; vazio se não for HTTP(S)
+func absoluteURL(pageURL *url.URL, ref string) string {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return ""
+	}
+	parsed, err := url.Parse(ref)
+	if err != nil {
+		return ""
+	}
+	if pageURL != nil {
+		parsed = pageURL.ResolveReference(parsed)
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return ""
+	}
+	return parsed.String()
+}
+
+// metaContent lê o atributo content do primeiro <meta> do seletor
+func metaContent(doc *goquery.Document, selector string) string {
+	return strings.TrimSpace(doc.Find(selector).First().AttrOr("content", ""))
+}
+
+// firstNonEmpty devolve o primeiro valor não vazio
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			return value
+		}
+	}
+	return ""
+}
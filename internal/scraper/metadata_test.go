@@ -0,0 +1,75 @@
+package scraper
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestExtractMetadata(t *testing.T) {
+	log.SetLevel(logrus.ErrorLevel)
+	pageURL, _ := url.Parse("https://blog.example.com/posts/1")
+
+	tests := []struct {
+		page string
+		want ArticleMetadata
+	}{
+		{
+			// JSON-LD tem precedência sobre OpenGraph e <meta>
+			page: "metadata-jsonld.html",
+			want: ArticleMetadata{
+				Authors:     []string{"Ana Souza", "John Doe"},
+				PublishedAt: time.Date(2024, 3, 5, 9, 30, 0, 0, time.UTC),
+				ModifiedAt:  time.Date(2024, 3, 6, 0, 0, 0, 0, time.UTC),
+				SiteName:    "Example Engineering",
+				LeadImage:   "https://cdn.example.com/lead.jpg",
+				Language:    "pt-BR",
+			},
+		},
+		{
+			// Sem JSON-LD válido: OpenGraph, Twitter card e <time datetime>
+			page: "metadata-meta.html",
+			want: ArticleMetadata{
+				Authors:     []string{"Maria Silva"},
+				PublishedAt: time.Date(2023, 11, 20, 14, 0, 0, 0, time.UTC),
+				SiteName:    "Some Blog",
+				LeadImage:   "https://blog.example.com/images/cover.png",
+				Language:    "en",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.page, func(t *testing.T) {
+			got := extractMetadata(loadTestPage(t, tt.page), pageURL)
+			if !got.PublishedAt.Equal(tt.want.PublishedAt) || !got.ModifiedAt.Equal(tt.want.ModifiedAt) {
+				t.Errorf("dates = %v / %v, want %v / %v", got.PublishedAt, got.ModifiedAt, tt.want.PublishedAt, tt.want.ModifiedAt)
+			}
+			got.PublishedAt, got.ModifiedAt = tt.want.PublishedAt, tt.want.ModifiedAt
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("extractMetadata() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetReadingStats(t *testing.T) {
+	var meta ArticleMetadata
+	content := "<p>" + repeatWords("word", 500) + "</p><p>" + repeatWords("more", 10) + "</p>"
+	meta.setReadingStats(content)
+	if meta.WordCount != 510 || meta.ReadingTime != 3 {
+		t.Errorf("WordCount = %d, ReadingTime = %d, want 510 and 3", meta.WordCount, meta.ReadingTime)
+	}
+}
+
+func repeatWords(word string, n int) string {
+	words := make([]byte, 0, n*(len(word)+1))
+	for i := 0; i < n; i++ {
+		words = append(words, word...)
+		words = append(words, ' ')
+	}
+	return string(words)
+}
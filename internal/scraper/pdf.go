@@ -0,0 +1,1316 @@
+package scraper
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"math"
+	"mime"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// Extração de texto de PDFs sem dependências externas: os objetos são localizados
+// pelo cabeçalho "n g obj" (inclusive dentro de object streams), os streams
+// FlateDecode são descomprimidos e os operadores de texto de cada página são
+// interpretados, usando o ToUnicode das fontes quando existe. PDFs criptografados e
+// páginas digitalizadas (texto em imagem) não têm texto a extrair.
+
+const (
+	// maxPDFPages limita as páginas lidas (livros inteiros não cabem num artigo)
+	maxPDFPages = 500
+
+	// maxPDFDepth limita recursão em referências, árvores de páginas e XObjects
+	maxPDFDepth = 32
+
+	// maxPDFPageBytes e maxPDFDocumentBytes limitam o conteúdo descomprimido de
+	// uma página (streams e XObjects) e do documento inteiro
+	maxPDFPageBytes     = maxBodyBytes * 4
+	maxPDFDocumentBytes = maxBodyBytes * 10
+)
+
+var (
+	errPDFEncrypted = errors.New("encrypted PDF")
+	errPDFNoText    = errors.New("PDF has no extractable text")
+	errPDFTooLarge  = errors.New("PDF content exceeds the decoding limit")
+)
+
+// pdfDocument é o resultado da extração
+type pdfDocument struct {
+	Title      string
+	Authors    []string
+	Created    time.Time
+	Modified   time.Time
+	Paragraphs []string
+	Pages      int
+}
+
+// Tipos dos objetos do PDF
+type (
+	pdfName    string
+	pdfString  string // Bytes crus; o significado depende da fonte ou do contexto
+	pdfKeyword string
+	pdfDict    map[string]interface{}
+	pdfRef     struct{ num, gen int }
+	pdfStream  struct {
+		dict pdfDict
+		data []byte // Ainda codificado (ver decode)
+	}
+	pdfDelim string // ]  >>  (fim de array ou dicionário)
+)
+
+// pdfObjectHeader localiza os objetos no arquivo ("12 0 obj")
+var pdfObjectHeader = regexp.MustCompile(`(\d+)\s+(\d+)\s+obj\b`)
+
+// pdfFile é o PDF carregado: objetos por número e o trailer
+type pdfFile struct {
+	objects map[int]interface{}
+	trailer pdfDict
+	fonts   map[interface{}]*pdfFont
+	decoded int64 // Bytes já descomprimidos (ver maxPDFDocumentBytes)
+}
+
+// pdfPageState acompanha a leitura de uma página
+type pdfPageState struct {
+	forms   map[int]bool // XObjects já executados (um form que chama a si mesmo ou se repete)
+	decoded int64
+}
+
+// extractPDF lê o texto e os metadados do PDF
+func extractPDF(data []byte) (*pdfDocument, error) {
+	if !bytes.HasPrefix(bytes.TrimLeft(data, "\x00\t\n\r "), []byte("%PDF-")) {
+		return nil, fmt.Errorf("not a PDF file")
+	}
+
+	f := loadPDF(data)
+	if _, encrypted := f.trailer["Encrypt"]; encrypted {
+		return nil, errPDFEncrypted
+	}
+
+	doc := &pdfDocument{}
+	if info, ok := f.resolve(f.trailer["Info"]).(pdfDict); ok {
+		doc.Title = cleanPDFTitle(decodePDFTextString(f.str(info["Title"])))
+		doc.Authors = splitPDFAuthors(decodePDFTextString(f.str(info["Author"])))
+		doc.Created = parsePDFDate(decodePDFTextString(f.str(info["CreationDate"])))
+		doc.Modified = parsePDFDate(decodePDFTextString(f.str(info["ModDate"])))
+	}
+
+	var text pdfTextWriter
+	root, _ := f.resolve(f.trailer["Root"]).(pdfDict)
+	if root == nil {
+		root = f.findCatalog()
+	}
+	if root != nil {
+		visited := make(map[int]bool)
+		f.walkPages(root["Pages"], nil, 0, visited, func(page pdfDict, resources pdfDict) bool {
+			doc.Pages++
+			text.paragraphBreak()
+			state := &pdfPageState{forms: make(map[int]bool)}
+			f.runContent(f.pageContent(page, state), resources, &text, state, 0)
+			return doc.Pages < maxPDFPages
+		})
+	}
+
+	doc.Paragraphs = text.paragraphs()
+	if len(doc.Paragraphs) == 0 {
+		return doc, errPDFNoText
+	}
+	if doc.Title == "" {
+		doc.Title = firstLine(doc.Paragraphs[0], 200)
+	}
+	return doc, nil
+}
+
+// pdfArticle monta o artigo a partir do PDF baixado, anexando o arquivo original.
+// PDFs digitalizados, sem texto, ainda são importados pelo anexo.
+func pdfArticle(page *fetchedPage) (*ArticleContent, error) {
+	doc, err := extractPDF(page.Body)
+	if err != nil && !errors.Is(err, errPDFNoText) {
+		return nil, fmt.Errorf("failed to extract PDF text: %w", err)
+	}
+
+	filename := pdfFilename(page)
+	content := pdfTextToHTML(doc.Paragraphs)
+	if err != nil {
+		log.Warnf("PDF %s has no extractable text", page.URL)
+		content = "<p>Este PDF não tem texto extraível (provavelmente é digitalizado). O arquivo original está anexado.</p>"
+	}
+
+	title := doc.Title
+	if title == "" {
+		title = strings.TrimSuffix(filename, path.Ext(filename))
+	}
+
+	return &ArticleContent{
+		Title:       title,
+		Content:     content,
+		ContentType: "html",
+		Metadata: ArticleMetadata{
+			Authors:     doc.Authors,
+			PublishedAt: doc.Created,
+			ModifiedAt:  doc.Modified,
+		},
+		Attachment: &Attachment{
+			Filename:    filename,
+			ContentType: "application/pdf",
+			Data:        page.Body,
+		},
+		baseURL: page.URL,
+	}, nil
+}
+
+// pdfFilename usa o nome do Content-Disposition ou o fim do caminho da URL
+func pdfFilename(page *fetchedPage) string {
+	name := ""
+	if _, params, err := mime.ParseMediaType(page.Header.Get("Content-Disposition")); err == nil {
+		name = params["filename"]
+	}
+	if name == "" && page.URL != nil {
+		name = path.Base(page.URL.Path)
+	}
+
+	name = strings.TrimSpace(path.Base(strings.ReplaceAll(name, "\\", "/")))
+	if name == "" || name == "." || name == "/" {
+		return "document.pdf"
+	}
+	if !strings.EqualFold(path.Ext(name), ".pdf") {
+		name += ".pdf"
+	}
+	return name
+}
+
+// pdfTextToHTML converte os parágrafos extraídos em HTML
+func pdfTextToHTML(paragraphs []string) string {
+	var out strings.Builder
+	for _, paragraph := range paragraphs {
+		out.WriteString("<p>")
+		out.WriteString(html.EscapeString(paragraph))
+		out.WriteString("</p>\n")
+	}
+	return out.String()
+}
+
+// loadPDF indexa todos os objetos do arquivo. Atualizações incrementais ficam no
+// fim, então a última definição de um número vence.
+func loadPDF(data []byte) *pdfFile {
+	f := &pdfFile{objects: make(map[int]interface{}), fonts: make(map[interface{}]*pdfFont)}
+	var objectStreams []pdfStream
+
+	end := 0
+	for _, m := range pdfObjectHeader.FindAllSubmatchIndex(data, -1) {
+		// Ignorar cabeçalhos que aparecem dentro de streams já lidos
+		if m[0] < end {
+			continue
+		}
+		num, _ := strconv.Atoi(string(data[m[2]:m[3]]))
+		lex := &pdfLexer{data: data, pos: m[1]}
+		obj, ok := lex.object()
+		if !ok {
+			continue
+		}
+		end = lex.pos
+		f.objects[num] = obj
+
+		if stream, ok := obj.(pdfStream); ok {
+			switch stream.dict["Type"] {
+			case pdfName("ObjStm"):
+				objectStreams = append(objectStreams, stream)
+			case pdfName("XRef"):
+				// PDF 1.5+: o dicionário do xref stream faz as vezes de trailer
+				f.mergeTrailer(stream.dict)
+			}
+		}
+	}
+
+	// Trailers clássicos; o último (atualização mais recente) vence
+	for idx := 0; ; {
+		i := bytes.Index(data[idx:], []byte("trailer"))
+		if i < 0 {
+			break
+		}
+		lex := &pdfLexer{data: data, pos: idx + i + len("trailer")}
+		if dict, ok := lex.object(); ok {
+			if d, ok := dict.(pdfDict); ok {
+				f.mergeTrailer(d)
+			}
+		}
+		idx += i + len("trailer")
+	}
+
+	for _, stream := range objectStreams {
+		f.loadObjectStream(stream)
+	}
+	return f
+}
+
+func (f *pdfFile) mergeTrailer(dict pdfDict) {
+	if f.trailer == nil {
+		f.trailer = pdfDict{}
+	}
+	for _, key := range []string{"Root", "Info", "Encrypt"} {
+		if value, ok := dict[key]; ok {
+			f.trailer[key] = value
+		}
+	}
+}
+
+// loadObjectStream lê os objetos comprimidos dentro de um /Type /ObjStm
+func (f *pdfFile) loadObjectStream(stream pdfStream) {
+	data, err := f.decode(stream)
+	if err != nil {
+		return
+	}
+	count := int(f.num(stream.dict["N"]))
+	first := int(f.num(stream.dict["First"]))
+	if first <= 0 || first > len(data) {
+		return
+	}
+
+	header := &pdfLexer{data: data[:first]}
+	for i := 0; i < count; i++ {
+		numObj, ok1 := header.object()
+		offsetObj, ok2 := header.object()
+		num, isNum := numObj.(float64)
+		offset, isOffset := offsetObj.(float64)
+		if !ok1 || !ok2 || !isNum || !isOffset {
+			return
+		}
+		pos := first + int(offset)
+		if pos < first || pos >= len(data) {
+			continue
+		}
+		if _, exists := f.objects[int(num)]; exists {
+			continue
+		}
+		lex := &pdfLexer{data: data, pos: pos}
+		if obj, ok := lex.object(); ok {
+			f.objects[int(num)] = obj
+		}
+	}
+}
+
+// findCatalog procura o catálogo quando o trailer não foi encontrado
+func (f *pdfFile) findCatalog() pdfDict {
+	for _, obj := range f.objects {
+		if dict, ok := obj.(pdfDict); ok && dict["Type"] == pdfName("Catalog") {
+			return dict
+		}
+	}
+	return nil
+}
+
+// resolve segue referências indiretas
+func (f *pdfFile) resolve(obj interface{}) interface{} {
+	for i := 0; i < maxPDFDepth; i++ {
+		ref, ok := obj.(pdfRef)
+		if !ok {
+			return obj
+		}
+		obj = f.objects[ref.num]
+	}
+	return nil
+}
+
+func (f *pdfFile) dict(obj interface{}) pdfDict {
+	switch v := f.resolve(obj).(type) {
+	case pdfDict:
+		return v
+	case pdfStream:
+		return v.dict
+	}
+	return nil
+}
+
+func (f *pdfFile) num(obj interface{}) float64 {
+	n, _ := f.resolve(obj).(float64)
+	return n
+}
+
+func (f *pdfFile) str(obj interface{}) string {
+	s, _ := f.resolve(obj).(pdfString)
+	return string(s)
+}
+
+// walkPages percorre a árvore de páginas, herdando /Resources dos nós pais. Cada
+// objeto é visitado uma vez (/Kids repetidos ou cíclicos não multiplicam a
+// árvore) e a travessia termina quando visit devolve false.
+func (f *pdfFile) walkPages(node interface{}, inherited pdfDict, depth int, visited map[int]bool, visit func(page, resources pdfDict) bool) bool {
+	if ref, ok := node.(pdfRef); ok {
+		if visited[ref.num] {
+			return true
+		}
+		visited[ref.num] = true
+	}
+	dict, ok := f.resolve(node).(pdfDict)
+	if !ok || depth > maxPDFDepth {
+		return true
+	}
+
+	resources := inherited
+	if own := f.dict(dict["Resources"]); own != nil {
+		resources = own
+	}
+
+	if kids, ok := f.resolve(dict["Kids"]).([]interface{}); ok {
+		for _, kid := range kids {
+			if !f.walkPages(kid, resources, depth+1, visited, visit) {
+				return false
+			}
+		}
+		return true
+	}
+	if dict["Type"] == pdfName("Page") || dict["Contents"] != nil {
+		return visit(dict, resources)
+	}
+	return true
+}
+
+// pageContent devolve os content streams da página, já decodificados e concatenados
+func (f *pdfFile) pageContent(page pdfDict, state *pdfPageState) []byte {
+	var streams []interface{}
+	switch contents := f.resolve(page["Contents"]).(type) {
+	case pdfStream:
+		streams = append(streams, contents)
+	case []interface{}:
+		streams = contents
+	}
+
+	// Os streams de uma página formam um único conteúdo; um operador pode começar
+	// num stream e terminar no seguinte
+	var combined []byte
+	for _, s := range streams {
+		if state.decoded >= maxPDFPageBytes {
+			break
+		}
+		if stream, ok := f.resolve(s).(pdfStream); ok {
+			if data, err := f.decode(stream); err == nil {
+				state.decoded += int64(len(data))
+				combined = append(append(combined, data...), '\n')
+			}
+		}
+	}
+	return combined
+}
+
+// decode aplica os filtros do stream (FlateDecode, ASCIIHexDecode, ASCII85Decode),
+// descontando o resultado do limite do documento
+func (f *pdfFile) decode(stream pdfStream) ([]byte, error) {
+	remaining := maxPDFDocumentBytes - f.decoded
+	if remaining <= 0 {
+		return nil, errPDFTooLarge
+	}
+
+	var filters []interface{}
+	switch filter := f.resolve(stream.dict["Filter"]).(type) {
+	case pdfName:
+		filters = []interface{}{filter}
+	case []interface{}:
+		filters = filter
+	}
+
+	data := stream.data
+	for _, filter := range filters {
+		var err error
+		switch f.resolve(filter) {
+		case pdfName("FlateDecode"), pdfName("Fl"):
+			data, err = inflatePDF(data, remaining)
+		case pdfName("ASCIIHexDecode"), pdfName("AHx"):
+			data, err = decodePDFHex(data)
+		case pdfName("ASCII85Decode"), pdfName("A85"):
+			data, err = decodeASCII85(data)
+		default:
+			return nil, fmt.Errorf("unsupported PDF filter %v", filter)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if int64(len(data)) > remaining {
+		return nil, errPDFTooLarge
+	}
+	f.decoded += int64(len(data))
+	return data, nil
+}
+
+// inflatePDF descomprime; streams truncados ou com lixo no fim ainda rendem o que
+// foi possível ler
+func inflatePDF(data []byte, limit int64) ([]byte, error) {
+	r, err := zlib.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	out, err := io.ReadAll(io.LimitReader(r, min(limit, maxBodyBytes*4)))
+	if err != nil && len(out) == 0 {
+		return nil, err
+	}
+	return out, nil
+}
+
+func decodePDFHex(data []byte) ([]byte, error) {
+	var digits []byte
+	for _, c := range data {
+		if c == '>' {
+			break
+		}
+		if isPDFHexDigit(c) {
+			digits = append(digits, c)
+		}
+	}
+	if len(digits)%2 == 1 {
+		digits = append(digits, '0')
+	}
+	return hex.DecodeString(string(digits))
+}
+
+func decodeASCII85(data []byte) ([]byte, error) {
+	var out []byte
+	var group [5]byte
+	n := 0
+	for _, c := range bytes.TrimPrefix(bytes.TrimSpace(data), []byte("<~")) {
+		switch {
+		case c == '~':
+			goto done
+		case c == 'z' && n == 0:
+			out = append(out, 0, 0, 0, 0)
+		case c >= '!' && c <= 'u':
+			group[n] = c - '!'
+			n++
+			if n == 5 {
+				var v uint32
+				for _, d := range group {
+					v = v*85 + uint32(d)
+				}
+				out = binary.BigEndian.AppendUint32(out, v)
+				n = 0
+			}
+		}
+	}
+done:
+	if n > 1 {
+		for i := n; i < 5; i++ {
+			group[i] = 84
+		}
+		var v uint32
+		for _, d := range group {
+			v = v*85 + uint32(d)
+		}
+		var buf [4]byte
+		binary.BigEndian.PutUint32(buf[:], v)
+		out = append(out, buf[:n-1]...)
+	}
+	return out, nil
+}
+
+// runContent interpreta os operadores de texto de um content stream
+func (f *pdfFile) runContent(data []byte, resources pdfDict, text *pdfTextWriter, state *pdfPageState, depth int) {
+	if depth > maxPDFDepth {
+		return
+	}
+
+	fonts := f.dict(resources["Font"])
+	xobjects := f.dict(resources["XObject"])
+
+	var font *pdfFont
+	var fontSize, leading float64 = 1, 0
+	var scale float64 = 1    // Escala vertical da matriz de texto
+	var lineY, lastY float64 // Posição da linha atual e da última linha escrita
+	started := false
+
+	newLine := func(y float64) {
+		lineY = y
+		if !started {
+			return
+		}
+		gap := math.Abs(lastY - y)
+		size := math.Abs(fontSize * scale)
+		switch {
+		case gap > size*1.7:
+			text.paragraphBreak()
+		case gap > size*0.5:
+			text.lineBreak()
+		}
+	}
+
+	lex := &pdfLexer{data: data}
+	var operands []interface{}
+	for {
+		obj, ok := lex.object()
+		if !ok {
+			return
+		}
+		op, isOp := obj.(pdfKeyword)
+		if !isOp {
+			operands = append(operands, obj)
+			continue
+		}
+
+		number := func(i int) float64 {
+			if i < len(operands) {
+				n, _ := operands[i].(float64)
+				return n
+			}
+			return 0
+		}
+
+		switch op {
+		case "BT":
+			scale = 1
+		case "Tf":
+			if len(operands) >= 2 {
+				if name, ok := operands[0].(pdfName); ok {
+					font = f.font(fonts[string(name)])
+				}
+				fontSize = number(1)
+			}
+		case "TL":
+			leading = number(0)
+		case "Td", "TD":
+			if op == "TD" {
+				leading = -number(1)
+			}
+			if ty := number(1); ty != 0 {
+				newLine(lineY + ty*scale)
+			} else if number(0) > 0 {
+				text.space()
+			}
+		case "Tm":
+			if len(operands) >= 6 {
+				if d := number(3); d != 0 {
+					scale = d
+				}
+				if y := number(5); y != lineY {
+					newLine(y)
+				} else {
+					text.space()
+				}
+			}
+		case "T*":
+			newLine(lineY - leading*scale)
+		case "Tj", "'", "\"":
+			if op != "Tj" {
+				newLine(lineY - leading*scale)
+			}
+			if len(operands) > 0 {
+				if s, ok := operands[len(operands)-1].(pdfString); ok {
+					text.write(font.decode(s))
+					started, lastY = true, lineY
+				}
+			}
+		case "TJ":
+			if len(operands) > 0 {
+				items, _ := operands[0].([]interface{})
+				for _, item := range items {
+					switch v := item.(type) {
+					case pdfString:
+						text.write(font.decode(v))
+					case float64:
+						// Deslocamentos grandes (milésimos de em) separam palavras
+						if v < -200 {
+							text.space()
+						}
+					}
+				}
+				started, lastY = true, lineY
+			}
+		case "Do":
+			if len(operands) > 0 {
+				// Cada form roda uma vez por página: chamadas repetidas ou recursivas
+				// cresceriam exponencialmente
+				name, _ := operands[0].(pdfName)
+				ref, isRef := xobjects[string(name)].(pdfRef)
+				if isRef && !state.forms[ref.num] && state.decoded < maxPDFPageBytes {
+					state.forms[ref.num] = true
+					if form, ok := f.resolve(ref).(pdfStream); ok && form.dict["Subtype"] == pdfName("Form") {
+						formResources := f.dict(form.dict["Resources"])
+						if formResources == nil {
+							formResources = resources
+						}
+						if content, err := f.decode(form); err == nil {
+							state.decoded += int64(len(content))
+							f.runContent(content, formResources, text, state, depth+1)
+						}
+					}
+				}
+			}
+		case "BI":
+			lex.skipInlineImage()
+		}
+		operands = operands[:0]
+	}
+}
+
+// pdfTextWriter junta o texto em parágrafos
+type pdfTextWriter struct {
+	done    []string
+	current strings.Builder
+	line    strings.Builder
+}
+
+func (w *pdfTextWriter) write(s string) {
+	w.line.WriteString(s)
+}
+
+func (w *pdfTextWriter) space() {
+	line := w.line.String()
+	if line != "" && !strings.HasSuffix(line, " ") {
+		w.line.WriteByte(' ')
+	}
+}
+
+// lineBreak termina a linha; palavras hifenizadas no fim da linha são reunidas
+func (w *pdfTextWriter) lineBreak() {
+	line := strings.TrimSpace(w.line.String())
+	w.line.Reset()
+	if line == "" {
+		return
+	}
+
+	paragraph := w.current.String()
+	switch {
+	case paragraph == "":
+	case strings.HasSuffix(paragraph, "-") && len(paragraph) > 1 && isLowerStart(line):
+		w.current.Reset()
+		w.current.WriteString(strings.TrimSuffix(paragraph, "-"))
+	default:
+		w.current.WriteByte(' ')
+	}
+	w.current.WriteString(line)
+}
+
+func (w *pdfTextWriter) paragraphBreak() {
+	w.lineBreak()
+	if paragraph := strings.Join(strings.Fields(w.current.String()), " "); paragraph != "" {
+		w.done = append(w.done, paragraph)
+	}
+	w.current.Reset()
+}
+
+func (w *pdfTextWriter) paragraphs() []string {
+	w.paragraphBreak()
+	return w.done
+}
+
+func isLowerStart(s string) bool {
+	r, _ := utf8.DecodeRuneInString(s)
+	return r >= 'a' && r <= 'z'
+}
+
+// pdfFont converte os códigos das strings de texto em Unicode
+type pdfFont struct {
+	codeBytes   int               // 1 (fontes simples) ou 2 (Type0/CID)
+	toUnicode   map[uint32]string // CMap ToUnicode, quando existe
+	differences map[byte]string   // /Encoding /Differences das fontes simples
+}
+
+// font carrega (e guarda) a fonte do dicionário de recursos
+func (f *pdfFile) font(ref interface{}) *pdfFont {
+	key := ref
+	if _, isRef := ref.(pdfRef); !isRef {
+		key = fmt.Sprintf("%p", f.dict(ref))
+	}
+	if font, ok := f.fonts[key]; ok {
+		return font
+	}
+
+	font := &pdfFont{codeBytes: 1}
+	dict := f.dict(ref)
+	if dict != nil {
+		if dict["Subtype"] == pdfName("Type0") {
+			font.codeBytes = 2
+		}
+		if cmap, ok := f.resolve(dict["ToUnicode"]).(pdfStream); ok {
+			if data, err := f.decode(cmap); err == nil {
+				font.toUnicode, font.codeBytes = parseToUnicode(data, font.codeBytes)
+			}
+		}
+		if encoding := f.dict(dict["Encoding"]); encoding != nil {
+			font.differences = f.parseDifferences(encoding["Differences"])
+		}
+	}
+	f.fonts[key] = font
+	return font
+}
+
+// parseDifferences lê /Differences [código /nome /nome ... código /nome ...]
+func (f *pdfFile) parseDifferences(obj interface{}) map[byte]string {
+	items, ok := f.resolve(obj).([]interface{})
+	if !ok {
+		return nil
+	}
+	differences := make(map[byte]string)
+	code := 0
+	for _, item := range items {
+		switch v := item.(type) {
+		case float64:
+			code = int(v)
+		case pdfName:
+			if code >= 0 && code < 256 {
+				if s, ok := glyphNameToUnicode(string(v)); ok {
+					differences[byte(code)] = s
+				}
+			}
+			code++
+		}
+	}
+	return differences
+}
+
+// decode converte a string conforme a fonte; sem ToUnicode, fontes simples são
+// lidas como WinAnsi (que coincide com ASCII e Latin-1 no que importa)
+func (font *pdfFont) decode(s pdfString) string {
+	if font == nil {
+		font = &pdfFont{codeBytes: 1}
+	}
+
+	var out strings.Builder
+	raw := []byte(s)
+	for i := 0; i+font.codeBytes <= len(raw); i += font.codeBytes {
+		var code uint32
+		for _, b := range raw[i : i+font.codeBytes] {
+			code = code<<8 | uint32(b)
+		}
+		if mapped, ok := font.toUnicode[code]; ok {
+			out.WriteString(mapped)
+			continue
+		}
+		if font.codeBytes != 1 {
+			continue // CID sem ToUnicode: não há como saber o caractere
+		}
+		if mapped, ok := font.differences[byte(code)]; ok {
+			out.WriteString(mapped)
+			continue
+		}
+		out.WriteString(winAnsiToUnicode(byte(code)))
+	}
+	return out.String()
+}
+
+// parseToUnicode lê os mapeamentos bfchar e bfrange do CMap
+func parseToUnicode(data []byte, defaultBytes int) (map[uint32]string, int) {
+	mapping := make(map[uint32]string)
+	codeBytes := defaultBytes
+
+	lex := &pdfLexer{data: data}
+	var operands []interface{}
+	for {
+		obj, ok := lex.object()
+		if !ok {
+			break
+		}
+		op, isOp := obj.(pdfKeyword)
+		if !isOp {
+			operands = append(operands, obj)
+			continue
+		}
+
+		switch op {
+		case "endcodespacerange":
+			if len(operands) > 0 {
+				if s, ok := operands[0].(pdfString); ok && len(s) > 0 {
+					codeBytes = len(s)
+				}
+			}
+		case "endbfchar":
+			for i := 0; i+1 < len(operands); i += 2 {
+				src, ok1 := operands[i].(pdfString)
+				dst, ok2 := operands[i+1].(pdfString)
+				if ok1 && ok2 {
+					mapping[pdfCode(src)] = decodeUTF16BE([]byte(dst))
+				}
+			}
+		case "endbfrange":
+			for i := 0; i+2 < len(operands); i += 3 {
+				lo, ok1 := operands[i].(pdfString)
+				hi, ok2 := operands[i+1].(pdfString)
+				if !ok1 || !ok2 {
+					continue
+				}
+				start, end := pdfCode(lo), pdfCode(hi)
+				if end < start || end-start > 0xFFFF {
+					continue
+				}
+				switch dst := operands[i+2].(type) {
+				case pdfString:
+					// O último caractere do destino avança junto com o código
+					base := []rune(decodeUTF16BE([]byte(dst)))
+					if len(base) == 0 {
+						continue
+					}
+					for code := start; code <= end; code++ {
+						r := append([]rune{}, base...)
+						r[len(r)-1] += rune(code - start)
+						mapping[code] = string(r)
+					}
+				case []interface{}:
+					for j, item := range dst {
+						if s, ok := item.(pdfString); ok && start+uint32(j) <= end {
+							mapping[start+uint32(j)] = decodeUTF16BE([]byte(s))
+						}
+					}
+				}
+			}
+		}
+		operands = operands[:0]
+	}
+	return mapping, codeBytes
+}
+
+func pdfCode(s pdfString) uint32 {
+	var code uint32
+	for _, b := range []byte(s) {
+		code = code<<8 | uint32(b)
+	}
+	return code
+}
+
+func decodeUTF16BE(b []byte) string {
+	units := make([]uint16, 0, len(b)/2)
+	for i := 0; i+1 < len(b); i += 2 {
+		units = append(units, uint16(b[i])<<8|uint16(b[i+1]))
+	}
+	return string(utf16.Decode(units))
+}
+
+// decodePDFTextString decodifica strings de metadados (UTF-16BE com BOM, UTF-8
+// com BOM ou PDFDocEncoding)
+func decodePDFTextString(s string) string {
+	b := []byte(s)
+	switch {
+	case bytes.HasPrefix(b, []byte{0xFE, 0xFF}):
+		return strings.TrimSpace(decodeUTF16BE(b[2:]))
+	case bytes.HasPrefix(b, []byte{0xEF, 0xBB, 0xBF}):
+		return strings.TrimSpace(string(b[3:]))
+	}
+	var out strings.Builder
+	for _, c := range b {
+		out.WriteString(winAnsiToUnicode(c))
+	}
+	return strings.TrimSpace(out.String())
+}
+
+// winAnsiSpecials são os caracteres de 0x80 a 0x9F do WinAnsi que diferem do Latin-1
+var winAnsiSpecials = map[byte]string{
+	0x80: "€", 0x85: "…", 0x91: "‘", 0x92: "’", 0x93: "“", 0x94: "”",
+	0x95: "•", 0x96: "–", 0x97: "—", 0x99: "™",
+}
+
+// winAnsiToUnicode converte um byte; os códigos 0x0B-0x0F são as ligaduras das
+// fontes do TeX (OT1), comuns em artigos acadêmicos
+func winAnsiToUnicode(b byte) string {
+	switch {
+	case b >= 0x0B && b <= 0x0F:
+		return [...]string{"ff", "fi", "fl", "ffi", "ffl"}[b-0x0B]
+	case b < 0x20:
+		return ""
+	case b < 0x80:
+		return string(rune(b))
+	case b < 0xA0:
+		return winAnsiSpecials[b]
+	}
+	return string(rune(b))
+}
+
+// glyphNames cobre os nomes de glifo mais comuns em /Differences
+var glyphNames = map[string]string{
+	"space": " ", "exclam": "!", "quotedbl": "\"", "numbersign": "#", "dollar": "$",
+	"percent": "%", "ampersand": "&", "quotesingle": "'", "parenleft": "(", "parenright": ")",
+	"asterisk": "*", "plus": "+", "comma": ",", "hyphen": "-", "period": ".", "slash": "/",
+	"zero": "0", "one": "1", "two": "2", "three": "3", "four": "4", "five": "5", "six": "6",
+	"seven": "7", "eight": "8", "nine": "9", "colon": ":", "semicolon": ";", "less": "<",
+	"equal": "=", "greater": ">", "question": "?", "at": "@", "bracketleft": "[",
+	"backslash": "\\", "bracketright": "]", "underscore": "_", "braceleft": "{", "bar": "|",
+	"braceright": "}", "quoteleft": "‘", "quoteright": "’", "quotedblleft": "“",
+	"quotedblright": "”", "endash": "–", "emdash": "—", "bullet": "•", "ellipsis": "…",
+	"fi": "fi", "fl": "fl", "ff": "ff", "ffi": "ffi", "ffl": "ffl", "dotlessi": "ı",
+}
+
+func glyphNameToUnicode(name string) (string, bool) {
+	if s, ok := glyphNames[name]; ok {
+		return s, true
+	}
+	if len(name) == 1 {
+		return name, true
+	}
+	if strings.HasPrefix(name, "uni") && len(name) == 7 {
+		if code, err := strconv.ParseUint(name[3:], 16, 32); err == nil {
+			return string(rune(code)), true
+		}
+	}
+	return "", false
+}
+
+// cleanPDFTitle descarta títulos que são só o nome do arquivo de origem
+func cleanPDFTitle(title string) string {
+	lower := strings.ToLower(title)
+	for _, generated := range []string{"untitled", "microsoft word - ", ".doc", ".tex", ".dvi", ".pdf"} {
+		if strings.Contains(lower, generated) {
+			return ""
+		}
+	}
+	return title
+}
+
+// splitPDFAuthors separa a lista de autores do /Author
+func splitPDFAuthors(author string) []string {
+	var authors []string
+	for _, part := range regexp.MustCompile(`\s*(?:;|,|&|\band\b)\s*`).Split(author, -1) {
+		if part = strings.TrimSpace(part); part != "" {
+			authors = append(authors, part)
+		}
+	}
+	return authors
+}
+
+// parsePDFDate lê datas no formato D:AAAAMMDDHHmmSS+HH'mm'
+func parsePDFDate(s string) time.Time {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "D:")
+	if len(s) < 4 {
+		return time.Time{}
+	}
+
+	digits := s
+	zone := ""
+	if i := strings.IndexAny(s, "Z+-"); i >= 0 {
+		digits, zone = s[:i], s[i:]
+	}
+	layout := "20060102150405"
+	if len(digits) > len(layout) {
+		digits = digits[:len(layout)]
+	}
+	t, err := time.Parse(layout[:len(digits)], digits)
+	if err != nil {
+		return time.Time{}
+	}
+
+	zone = strings.ReplaceAll(zone, "'", "")
+	if len(zone) >= 3 && zone[0] != 'Z' {
+		hours, _ := strconv.Atoi(zone[1:3])
+		minutes := 0
+		if len(zone) >= 5 {
+			minutes, _ = strconv.Atoi(zone[3:5])
+		}
+		offset := hours*3600 + minutes*60
+		if zone[0] == '-' {
+			offset = -offset
+		}
+		t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, time.FixedZone("", offset))
+	}
+	return t
+}
+
+// firstLine devolve o início do texto, até o limite de caracteres
+func firstLine(s string, limit int) string {
+	runes := []rune(s)
+	if len(runes) <= limit {
+		return s
+	}
+	return strings.TrimSpace(string(runes[:limit])) + "…"
+}
+
+// pdfLexer lê objetos da sintaxe do PDF
+type pdfLexer struct {
+	data []byte
+	pos  int
+}
+
+func isPDFWhitespace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f' || c == 0
+}
+
+func isPDFDelimiter(c byte) bool {
+	return strings.IndexByte("()<>[]{}/%", c) >= 0
+}
+
+func isPDFHexDigit(c byte) bool {
+	return (c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
+
+// skipSpace pula espaços e comentários
+func (l *pdfLexer) skipSpace() {
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		if isPDFWhitespace(c) {
+			l.pos++
+			continue
+		}
+		if c == '%' {
+			for l.pos < len(l.data) && l.data[l.pos] != '\n' && l.data[l.pos] != '\r' {
+				l.pos++
+			}
+			continue
+		}
+		return
+	}
+}
+
+// regular lê uma sequência de caracteres regulares (nomes, números, operadores)
+func (l *pdfLexer) regular() string {
+	start := l.pos
+	for l.pos < len(l.data) && !isPDFWhitespace(l.data[l.pos]) && !isPDFDelimiter(l.data[l.pos]) {
+		l.pos++
+	}
+	return string(l.data[start:l.pos])
+}
+
+// object lê o próximo objeto; false no fim dos dados
+func (l *pdfLexer) object() (interface{}, bool) {
+	l.skipSpace()
+	if l.pos >= len(l.data) {
+		return nil, false
+	}
+
+	c := l.data[l.pos]
+	switch {
+	case c == '/':
+		l.pos++
+		return pdfName(decodePDFName(l.regular())), true
+	case c == '(':
+		return l.literalString(), true
+	case c == '<' && l.pos+1 < len(l.data) && l.data[l.pos+1] == '<':
+		l.pos += 2
+		return l.dictOrStream(), true
+	case c == '<':
+		l.pos++
+		start := l.pos
+		for l.pos < len(l.data) && l.data[l.pos] != '>' {
+			l.pos++
+		}
+		decoded, _ := decodePDFHex(l.data[start:l.pos])
+		if l.pos < len(l.data) {
+			l.pos++ // >
+		}
+		return pdfString(decoded), true
+	case c == '>' && l.pos+1 < len(l.data) && l.data[l.pos+1] == '>':
+		l.pos += 2
+		return pdfDelim(">>"), true
+	case c == '[':
+		l.pos++
+		var items []interface{}
+		for {
+			item, ok := l.object()
+			if !ok || item == pdfDelim("]") {
+				return items, true
+			}
+			if item == pdfKeyword("endobj") {
+				return items, true
+			}
+			items = append(items, item)
+		}
+	case c == ']':
+		l.pos++
+		return pdfDelim("]"), true
+	case c == '{' || c == '}' || c == ')' || c == '>':
+		l.pos++
+		return pdfKeyword(string(c)), true
+	}
+
+	token := l.regular()
+	if token == "" {
+		l.pos++
+		return pdfKeyword(string(c)), true
+	}
+
+	if n, err := strconv.ParseFloat(token, 64); err == nil {
+		// "n g R" é uma referência indireta
+		if n == math.Trunc(n) && n >= 0 {
+			save := l.pos
+			l.skipSpace()
+			gen := l.regular()
+			l.skipSpace()
+			if g, err := strconv.Atoi(gen); err == nil && l.pos < len(l.data) && l.data[l.pos] == 'R' &&
+				(l.pos+1 == len(l.data) || isPDFWhitespace(l.data[l.pos+1]) || isPDFDelimiter(l.data[l.pos+1])) {
+				l.pos++
+				return pdfRef{num: int(n), gen: g}, true
+			}
+			l.pos = save
+		}
+		return n, true
+	}
+
+	switch token {
+	case "true":
+		return true, true
+	case "false":
+		return false, true
+	case "null":
+		return nil, true
+	}
+	return pdfKeyword(token), true
+}
+
+// dictOrStream lê o dicionário (depois de "<<") e, se vier em seguida, o stream
+func (l *pdfLexer) dictOrStream() interface{} {
+	dict := pdfDict{}
+	for {
+		key, ok := l.object()
+		if !ok || key == pdfDelim(">>") || key == pdfKeyword("endobj") {
+			break
+		}
+		name, isName := key.(pdfName)
+		if !isName {
+			continue
+		}
+		value, ok := l.object()
+		if !ok {
+			break
+		}
+		if value == pdfDelim(">>") {
+			break
+		}
+		dict[string(name)] = value
+	}
+
+	save := l.pos
+	l.skipSpace()
+	if !bytes.HasPrefix(l.data[l.pos:], []byte("stream")) {
+		l.pos = save
+		return dict
+	}
+	l.pos += len("stream")
+	if l.pos < len(l.data) && l.data[l.pos] == '\r' {
+		l.pos++
+	}
+	if l.pos < len(l.data) && l.data[l.pos] == '\n' {
+		l.pos++
+	}
+
+	start := l.pos
+	end := -1
+	if length, ok := dict["Length"].(float64); ok && length >= 0 && start+int(length) <= len(l.data) {
+		// /Length direto: conferir se "endstream" vem logo depois
+		after := bytes.TrimLeft(l.data[start+int(length):], "\r\n ")
+		if bytes.HasPrefix(after, []byte("endstream")) {
+			end = start + int(length)
+		}
+	}
+	if end < 0 {
+		i := bytes.Index(l.data[start:], []byte("endstream"))
+		if i < 0 {
+			l.pos = len(l.data)
+			return pdfStream{dict: dict, data: l.data[start:]}
+		}
+		end = start + i
+		// O EOL antes de "endstream" não faz parte dos dados
+		for end > start && (l.data[end-1] == '\n' || l.data[end-1] == '\r') {
+			end--
+		}
+	}
+
+	l.pos = end
+	if i := bytes.Index(l.data[end:], []byte("endstream")); i >= 0 {
+		l.pos = end + i + len("endstream")
+	}
+	return pdfStream{dict: dict, data: l.data[start:end]}
+}
+
+// literalString lê (...) com parênteses aninhados e escapes
+func (l *pdfLexer) literalString() pdfString {
+	l.pos++ // (
+	var out []byte
+	depth := 1
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		l.pos++
+		switch c {
+		case '(':
+			depth++
+			out = append(out, c)
+		case ')':
+			depth--
+			if depth == 0 {
+				return pdfString(out)
+			}
+			out = append(out, c)
+		case '\\':
+			if l.pos >= len(l.data) {
+				break
+			}
+			e := l.data[l.pos]
+			l.pos++
+			switch e {
+			case 'n':
+				out = append(out, '\n')
+			case 'r':
+				out = append(out, '\r')
+			case 't':
+				out = append(out, '\t')
+			case 'b':
+				out = append(out, '\b')
+			case 'f':
+				out = append(out, '\f')
+			case '\r':
+				if l.pos < len(l.data) && l.data[l.pos] == '\n' {
+					l.pos++
+				}
+			case '\n':
+				// Continuação de linha
+			default:
+				if e >= '0' && e <= '7' {
+					v := int(e - '0')
+					for i := 0; i < 2 && l.pos < len(l.data) && l.data[l.pos] >= '0' && l.data[l.pos] <= '7'; i++ {
+						v = v*8 + int(l.data[l.pos]-'0')
+						l.pos++
+					}
+					out = append(out, byte(v))
+				} else {
+					out = append(out, e)
+				}
+			}
+		default:
+			out = append(out, c)
+		}
+	}
+	return pdfString(out)
+}
+
+// skipInlineImage pula os dados binários de uma imagem embutida (BI ... ID dados EI)
+func (l *pdfLexer) skipInlineImage() {
+	i := bytes.Index(l.data[l.pos:], []byte("ID"))
+	if i < 0 {
+		l.pos = len(l.data)
+		return
+	}
+	l.pos += i + 2
+	for {
+		j := bytes.Index(l.data[l.pos:], []byte("EI"))
+		if j < 0 {
+			l.pos = len(l.data)
+			return
+		}
+		at := l.pos + j
+		l.pos = at + 2
+		if at > 0 && isPDFWhitespace(l.data[at-1]) &&
+			(l.pos >= len(l.data) || isPDFWhitespace(l.data[l.pos])) {
+			return
+		}
+	}
+}
+
+// decodePDFName resolve os escapes #xx dos nomes
+func decodePDFName(name string) string {
+	if !strings.Contains(name, "#") {
+		return name
+	}
+	var out strings.Builder
+	for i := 0; i < len(name); i++ {
+		if name[i] == '#' && i+2 < len(name) && isPDFHexDigit(name[i+1]) && isPDFHexDigit(name[i+2]) {
+			v, _ := strconv.ParseUint(name[i+1:i+3], 16, 8)
+			out.WriteByte(byte(v))
+			i += 2
+			continue
+		}
+		out.WriteByte(name[i])
+	}
+	return out.String()
+}
@@ -0,0 +1,182 @@
+package scraper
+
+import (
+	"bytes"
+	"compress/zlib"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+func readPDFFixture(t *testing.T) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", "paper.pdf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestExtractPDF(t *testing.T) {
+	doc, err := extractPDF(readPDFFixture(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Metadados do /Info, guardado num object stream e referenciado pelo xref stream
+	if doc.Title != "Análise preguiçosa de documentos" {
+		t.Errorf("title = %q", doc.Title)
+	}
+	if strings.Join(doc.Authors, "|") != "Ana Souza|Bruno Lima" {
+		t.Errorf("authors = %q", doc.Authors)
+	}
+	want := time.Date(2024, 3, 15, 15, 0, 0, 0, time.UTC)
+	if !doc.Created.Equal(want) {
+		t.Errorf("created = %v, want %v", doc.Created, want)
+	}
+	if doc.Pages != 2 {
+		t.Errorf("pages = %d", doc.Pages)
+	}
+
+	wantParagraphs := []string{
+		// Kerning do TJ vira espaço, a hifenização no fim da linha é desfeita e
+		// \351 é lido como WinAnsi
+		"Lazy parsers defer work until a value is read, so they can skip most of a document and still answer queries quickly at the café.",
+		"A second paragraph starts after a larger gap.",
+		// Fonte Type0 com ToUnicode (inclusive a ligadura mapeada para "fi")
+		"Second page with five ligatures.",
+	}
+	if strings.Join(doc.Paragraphs, "\n") != strings.Join(wantParagraphs, "\n") {
+		t.Errorf("paragraphs =\n%s", strings.Join(doc.Paragraphs, "\n"))
+	}
+}
+
+func TestExtractPDFRejectsEncrypted(t *testing.T) {
+	data := []byte("%PDF-1.4\n1 0 obj << /Type /Catalog >> endobj\ntrailer << /Root 1 0 R /Encrypt << /Filter /Standard >> >>\n%%EOF")
+	if _, err := extractPDF(data); err != errPDFEncrypted {
+		t.Errorf("err = %v, want errPDFEncrypted", err)
+	}
+}
+
+func TestFetchPDFArticle(t *testing.T) {
+	log.SetLevel(logrus.ErrorLevel)
+	pdf := readPDFFixture(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Servidor que não declara o tipo: o PDF é reconhecido pelo conteúdo
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Header().Set("Content-Disposition", `attachment; filename="lazy-parsing"`)
+		w.Write(pdf)
+	}))
+	defer server.Close()
+
+	previous := defaultFetcher
+	SetDefaultFetcher(newTestFetcher(server, FetcherConfig{IgnoreRobots: true}))
+	t.Cleanup(func() { SetDefaultFetcher(previous) })
+
+	content, err := defaultRegistry.Fetch(context.Background(), server.URL+"/download?id=42")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if content.Title != "Análise preguiçosa de documentos" {
+		t.Errorf("title = %q", content.Title)
+	}
+	if !strings.Contains(content.Content, "<p>A second paragraph starts after a larger gap.</p>") {
+		t.Errorf("content = %q", content.Content)
+	}
+	if content.Metadata.WordCount == 0 {
+		t.Errorf("reading stats were not computed")
+	}
+
+	attachment := content.Attachment
+	if attachment == nil {
+		t.Fatal("PDF was not attached")
+	}
+	if attachment.Filename != "lazy-parsing.pdf" || attachment.ContentType != "application/pdf" || len(attachment.Data) != len(pdf) {
+		t.Errorf("attachment = %s %s (%d bytes)", attachment.Filename, attachment.ContentType, len(attachment.Data))
+	}
+}
+
+// hostilePDF monta uma árvore de páginas em que cada nível lista o mesmo filho
+// duas vezes (2^depth caminhos), o último nó aponta de volta para a raiz e a
+// página executa um form que chama a si mesmo duas vezes
+func hostilePDF(depth int) []byte {
+	var b strings.Builder
+	b.WriteString("%PDF-1.4\n1 0 obj << /Type /Catalog /Pages 2 0 R >> endobj\n")
+	for i := 0; i < depth; i++ {
+		fmt.Fprintf(&b, "%d 0 obj << /Type /Pages /Kids [%d 0 R %d 0 R 2 0 R] >> endobj\n", i+2, i+3, i+3)
+	}
+	page := depth + 2
+	fmt.Fprintf(&b, "%d 0 obj << /Type /Page /Resources << /Font << /F1 %d 0 R >> /XObject << /X1 %d 0 R >> >> /Contents %d 0 R >> endobj\n",
+		page, page+1, page+2, page+3)
+	fmt.Fprintf(&b, "%d 0 obj << /Type /Font /Subtype /Type1 /BaseFont /Helvetica >> endobj\n", page+1)
+	form := "BT /F1 12 Tf (loop) Tj ET /X1 Do /X1 Do"
+	fmt.Fprintf(&b, "%d 0 obj << /Type /XObject /Subtype /Form /Resources << /Font << /F1 %d 0 R >> /XObject << /X1 %d 0 R >> >> /Length %d >>\nstream\n%s\nendstream\nendobj\n",
+		page+2, page+1, page+2, len(form), form)
+	content := "BT /F1 12 Tf 72 700 Td (Only page) Tj ET /X1 Do"
+	fmt.Fprintf(&b, "%d 0 obj << /Length %d >>\nstream\n%s\nendstream\nendobj\n", page+3, len(content), content)
+	b.WriteString("trailer << /Root 1 0 R >>\n%%EOF")
+	return []byte(b.String())
+}
+
+func TestExtractPDFDuplicatedPageTree(t *testing.T) {
+	done := make(chan struct{})
+	var doc *pdfDocument
+	var err error
+	go func() {
+		defer close(done)
+		doc, err = extractPDF(hostilePDF(28))
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("extractPDF did not finish on a duplicated page tree")
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A página é lida uma vez e o form recursivo roda uma vez
+	if doc.Pages != 1 {
+		t.Errorf("pages = %d, want 1", doc.Pages)
+	}
+	if got := strings.Join(doc.Paragraphs, "\n"); !strings.Contains(got, "Only page") || strings.Count(got, "loop") != 1 {
+		t.Errorf("paragraphs = %q", got)
+	}
+}
+
+func TestPDFContentsRepeatedStreamIsCapped(t *testing.T) {
+	var compressed bytes.Buffer
+	zw := zlib.NewWriter(&compressed)
+	zw.Write(make([]byte, maxBodyBytes))
+	zw.Close()
+
+	// /Contents repete o mesmo stream: o total por página e por documento é limitado
+	f := &pdfFile{objects: map[int]interface{}{
+		5: pdfStream{dict: pdfDict{"Filter": pdfName("FlateDecode")}, data: compressed.Bytes()},
+	}}
+	contents := make([]interface{}, 100)
+	for i := range contents {
+		contents[i] = pdfRef{num: 5}
+	}
+
+	first := f.pageContent(pdfDict{"Contents": contents}, &pdfPageState{})
+	if len(first) > maxPDFPageBytes+maxBodyBytes+len(contents) {
+		t.Errorf("page content = %d bytes, want at most about %d", len(first), maxPDFPageBytes)
+	}
+	for i := 0; i < 5; i++ {
+		f.pageContent(pdfDict{"Contents": contents}, &pdfPageState{})
+	}
+	if f.decoded > maxPDFDocumentBytes {
+		t.Errorf("decoded = %d bytes, want at most %d", f.decoded, maxPDFDocumentBytes)
+	}
+}
@@ -0,0 +1,265 @@
+package scraper
+
+import (
+	"math"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+// Extração do conteúdo principal por pontuação, no estilo do Readability da Mozilla:
+// cada parágrafo pontua o pai e os avós, a pontuação é penalizada pela densidade de
+// links e os irmãos do melhor candidato que parecem fazer parte do texto são incluídos.
+
+const (
+	// minParagraphLength ignora parágrafos curtos demais para indicar conteúdo
+	minParagraphLength = 25
+
+	// lowConfidence abaixo deste valor o resultado não é confiável e o body inteiro é usado
+	lowConfidence = 0.2
+)
+
+var (
+	// unlikelyCandidatePattern reconhece blocos que quase nunca são o artigo (por class/id)
+	unlikelyCandidatePattern = regexp.MustCompile(`(?i)-ad-|ai2html|banner|breadcrumbs|combx|comment|community|cover-wrap|disqus|extra|footer|gdpr|header|legends|menu|related|remark|replies|rss|shoutbox|sidebar|skyscraper|social|sponsor|supplemental|ad-break|agegate|pagination|pager|popup|yom-remote`)
+
+	// maybeCandidatePattern salva da remoção blocos que também parecem conteúdo
+	maybeCandidatePattern = regexp.MustCompile(`(?i)and|article|body|column|content|main|shadow`)
+
+	// positiveWeightPattern e negativeWeightPattern ajustam a pontuação pelo class/id
+	positiveWeightPattern = regexp.MustCompile(`(?i)article|body|content|entry|hentry|h-entry|main|page|pagination|post|text|blog|story`)
+	negativeWeightPattern = regexp.MustCompile(`(?i)-ad-|hidden|^hid$| hid$| hid |^hid |banner|combx|comment|com-|contact|foot|footer|footnote|gdpr|masthead|media|meta|outbrain|promo|related|scroll|share|shoutbox|sidebar|skyscraper|sponsor|shopping|tags|tool|widget`)
+
+	// sentenceEndPattern identifica parágrafos curtos que terminam uma frase
+	sentenceEndPattern = regexp.MustCompile(`\.( |$)`)
+)
+
+// blockTags são os elementos que impedem uma <div> de ser tratada como parágrafo
+var blockTags = map[string]bool{
+	"a": true, "blockquote": true, "dl": true, "div": true, "img": true, "ol": true,
+	"p": true, "pre": true, "table": true, "ul": true, "section": true, "article": true,
+	"figure": true, "h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+}
+
+// readableContent é o resultado da extração: o melhor candidato (com os irmãos
+// incluídos, na ordem do documento) e a confiança de que ele é o artigo, entre 0 e 1
+type readableContent struct {
+	Node       *goquery.Selection
+	Confidence float64
+}
+
+// extractReadable pontua os blocos do documento e devolve o conteúdo principal.
+// Remove do documento os blocos que não podem ser o artigo.
+func extractReadable(doc *goquery.Document) readableContent {
+	removeUnlikelyCandidates(doc)
+
+	scores := make(map[*html.Node]float64)
+	var candidates []*html.Node
+
+	doc.Find("p, pre, td, blockquote, div").Each(func(i int, s *goquery.Selection) {
+		if goquery.NodeName(s) == "div" && hasBlockChildren(s) {
+			return
+		}
+
+		text := normalizedText(s)
+		if len(text) < minParagraphLength {
+			return
+		}
+
+		// Um ponto pelo parágrafo, um por vírgula e até três pelo tamanho
+		score := 1.0 + float64(strings.Count(text, ",")) + math.Min(float64(len(text))/100, 3)
+
+		level := 0
+		for ancestor := s.Parent(); ancestor.Length() > 0 && level < 3; ancestor = ancestor.Parent() {
+			node := ancestor.Get(0)
+			if node.Type != html.ElementNode || node.Data == "body" || node.Data == "html" {
+				break
+			}
+			if _, ok := scores[node]; !ok {
+				scores[node] = initialScore(ancestor)
+				candidates = append(candidates, node)
+			}
+
+			// O pai recebe a pontuação inteira, o avô metade e os demais um terço por nível
+			divider := 1.0
+			switch level {
+			case 0:
+			case 1:
+				divider = 2
+			default:
+				divider = float64(level) * 3
+			}
+			scores[node] += score / divider
+			level++
+		}
+	})
+
+	// Pontuação final: penalizar blocos com muitos links (menus, listas de links)
+	var top *html.Node
+	var topScore float64
+	for _, node := range candidates {
+		sel := doc.FindNodes(node)
+		scores[node] *= 1 - linkDensity(sel)
+		if top == nil || scores[node] > topScore {
+			top = node
+			topScore = scores[node]
+		}
+	}
+
+	if top == nil {
+		return readableContent{Node: doc.Find("body"), Confidence: 0}
+	}
+
+	// Subir para o pai quando ele concentra quase a mesma pontuação (artigo dividido
+	// em vários blocos irmãos, cada um com parte dos parágrafos)
+	for parent := top.Parent; parent != nil && parent.Type == html.ElementNode && parent.Data != "body"; parent = parent.Parent {
+		parentScore, ok := scores[parent]
+		if !ok || parentScore < topScore*0.75 {
+			break
+		}
+		top = parent
+		topScore = parentScore
+	}
+
+	content := mergeSiblings(doc, top, topScore, scores)
+	return readableContent{Node: content, Confidence: readableConfidence(content, topScore)}
+}
+
+// removeUnlikelyCandidates remove blocos cujo class/id indica que não são o artigo
+func removeUnlikelyCandidates(doc *goquery.Document) {
+	doc.Find("*").Each(func(i int, s *goquery.Selection) {
+		switch goquery.NodeName(s) {
+		case "html", "body", "article", "main", "a":
+			return
+		}
+		matchString := s.AttrOr("class", "") + " " + s.AttrOr("id", "")
+		if strings.TrimSpace(matchString) == "" {
+			return
+		}
+		if unlikelyCandidatePattern.MatchString(matchString) && !maybeCandidatePattern.MatchString(matchString) {
+			s.Remove()
+		}
+	})
+}
+
+// initialScore pontua o candidato pelo tipo de elemento e pelo class/id
+func initialScore(s *goquery.Selection) float64 {
+	var score float64
+	switch goquery.NodeName(s) {
+	case "article":
+		score = 10
+	case "div", "main", "section":
+		score = 5
+	case "pre", "td", "blockquote":
+		score = 3
+	case "address", "ol", "ul", "dl", "dd", "dt", "li", "form":
+		score = -3
+	case "h1", "h2", "h3", "h4", "h5", "h6", "th":
+		score = -5
+	}
+	return score + classWeight(s)
+}
+
+// classWeight soma ou subtrai pontos conforme o class e o id do elemento
+func classWeight(s *goquery.Selection) float64 {
+	var weight float64
+	for _, attr := range []string{"class", "id"} {
+		value := s.AttrOr(attr, "")
+		if value == "" {
+			continue
+		}
+		if negativeWeightPattern.MatchString(value) {
+			weight -= 25
+		}
+		if positiveWeightPattern.MatchString(value) {
+			weight += 25
+		}
+	}
+	if s.AttrOr("itemprop", "") == "articleBody" || s.AttrOr("role", "") == "main" {
+		weight += 25
+	}
+	return weight
+}
+
+// mergeSiblings junta ao melhor candidato os irmãos que parecem continuar o texto
+func mergeSiblings(doc *goquery.Document, top *html.Node, topScore float64, scores map[*html.Node]float64) *goquery.Selection {
+	threshold := math.Max(10, topScore*0.2)
+	topClass := doc.FindNodes(top).AttrOr("class", "")
+
+	var nodes []*html.Node
+	for sibling := top.Parent.FirstChild; sibling != nil; sibling = sibling.NextSibling {
+		if sibling == top {
+			nodes = append(nodes, sibling)
+			continue
+		}
+		if sibling.Type != html.ElementNode {
+			continue
+		}
+
+		sel := doc.FindNodes(sibling)
+		bonus := 0.0
+		if topClass != "" && sel.AttrOr("class", "") == topClass {
+			bonus = topScore * 0.2
+		}
+
+		if score, ok := scores[sibling]; ok && score+bonus >= threshold {
+			nodes = append(nodes, sibling)
+			continue
+		}
+
+		if sibling.Data == "p" {
+			text := normalizedText(sel)
+			density := linkDensity(sel)
+			if (len(text) > 80 && density < 0.25) ||
+				(len(text) > 0 && density == 0 && sentenceEndPattern.MatchString(text)) {
+				nodes = append(nodes, sibling)
+			}
+		}
+	}
+
+	return doc.FindNodes(nodes...)
+}
+
+// readableConfidence estima se o conteúdo é mesmo o artigo: combina o tamanho do
+// texto, a quantidade de parágrafos, a densidade de links e a pontuação do candidato
+func readableConfidence(content *goquery.Selection, score float64) float64 {
+	textLength := float64(len(normalizedText(content)))
+	paragraphs := float64(content.Find("p").Length() + content.Filter("p").Length())
+
+	lengthFactor := math.Min(textLength/1500, 1)
+	paragraphFactor := math.Min(paragraphs/5, 1)
+	densityFactor := 1 - linkDensity(content)
+	scoreFactor := math.Min(math.Max(score, 0)/40, 1)
+
+	return lengthFactor*0.35 + paragraphFactor*0.25 + densityFactor*0.2 + scoreFactor*0.2
+}
+
+// hasBlockChildren verifica se a <div> contém elementos de bloco
+func hasBlockChildren(s *goquery.Selection) bool {
+	for child := s.Get(0).FirstChild; child != nil; child = child.NextSibling {
+		if child.Type == html.ElementNode && blockTags[child.Data] {
+			return true
+		}
+	}
+	return false
+}
+
+// linkDensity é a fração do texto da seleção que está dentro de links
+func linkDensity(s *goquery.Selection) float64 {
+	textLength := len(normalizedText(s))
+	if textLength == 0 {
+		return 0
+	}
+	linkLength := 0
+	s.Find("a").Each(func(i int, a *goquery.Selection) {
+		linkLength += len(normalizedText(a))
+	})
+	return math.Min(float64(linkLength)/float64(textLength), 1)
+}
+
+// normalizedText devolve o texto da seleção com os espaços colapsados
+func normalizedText(s *goquery.Selection) string {
+	return strings.Join(strings.Fields(s.Text()), " ")
+}
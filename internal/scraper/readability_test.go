@@ -0,0 +1,59 @@
+package scraper
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/sirupsen/logrus"
+)
+
+func loadTestPage(t *testing.T, name string) *goquery.Document {
+	t.Helper()
+	raw, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(string(raw)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return doc
+}
+
+func TestExtractReadable(t *testing.T) {
+	log.SetLevel(logrus.ErrorLevel)
+
+	doc := loadTestPage(t, "blog-post.html")
+	readable := extractReadable(doc)
+	if readable.Confidence < lowConfidence {
+		t.Fatalf("confidence = %.2f, want >= %.2f", readable.Confidence, lowConfidence)
+	}
+
+	text := normalizedText(readable.Node)
+	for _, want := range []string{"Escape analysis is the part", "-gcflags=-m", "small arrays with constant size"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("main content is missing %q", want)
+		}
+	}
+	for _, unwanted := range []string{"Great post", "Copyright", "Archive", "performance"} {
+		if strings.Contains(text, unwanted) {
+			t.Errorf("main content contains %q", unwanted)
+		}
+	}
+}
+
+func TestExtractGenericContentLowConfidence(t *testing.T) {
+	log.SetLevel(logrus.ErrorLevel)
+
+	// Sem parágrafos de texto, a pontuação não é confiável e o body inteiro é mantido
+	doc := loadTestPage(t, "link-page.html")
+	content := extractGenericContent(doc)
+	for _, want := range []string{"Release 1.0 for Linux", "Checksums are available"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("fallback content is missing %q", want)
+		}
+	}
+}
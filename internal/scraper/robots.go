@@ -0,0 +1,247 @@
+package scraper
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// robotsTTL é a validade do robots.txt em cache
+	robotsTTL = 24 * time.Hour
+
+	// robotsErrorTTL é a validade quando o robots.txt não pôde ser lido (5xx)
+	robotsErrorTTL = 10 * time.Minute
+
+	// maxRobotsBytes segue o limite mínimo da RFC 9309 (500 KiB)
+	maxRobotsBytes = 500 << 10
+
+	// robotsAgent é o token do produto procurado nos grupos User-agent
+	robotsAgent = "gmail-scanner"
+)
+
+// robotsCache guarda as regras do robots.txt de cada origem (esquema + host)
+type robotsCache struct {
+	fetcher *Fetcher
+
+	mu      sync.Mutex
+	entries map[string]*robotsEntry
+}
+
+// robotsEntry são as regras de uma origem; o mutex evita buscas simultâneas
+type robotsEntry struct {
+	mu      sync.Mutex
+	rules   *robotsRules
+	expires time.Time
+}
+
+// robotsRules são as regras do grupo que se aplica ao scanner
+type robotsRules struct {
+	disallowAll bool
+	rules       []robotsRule
+	crawlDelay  time.Duration
+}
+
+// robotsRule é uma linha Allow/Disallow
+type robotsRule struct {
+	allow   bool
+	pattern string
+}
+
+func newRobotsCache(f *Fetcher) *robotsCache {
+	return &robotsCache{fetcher: f, entries: make(map[string]*robotsEntry)}
+}
+
+// allowed informa se o robots.txt do site permite acessar a URL
+func (c *robotsCache) allowed(ctx context.Context, target *url.URL) (bool, error) {
+	origin := strings.ToLower(target.Scheme + "://" + target.Host)
+
+	c.mu.Lock()
+	entry, ok := c.entries[origin]
+	if !ok {
+		entry = &robotsEntry{}
+		c.entries[origin] = entry
+	}
+	c.mu.Unlock()
+
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+
+	if entry.rules == nil || time.Now().After(entry.expires) {
+		rules, ttl, err := c.fetch(ctx, origin)
+		if err != nil {
+			return false, err
+		}
+		entry.rules = rules
+		entry.expires = time.Now().Add(ttl)
+		c.fetcher.setCrawlDelay(target.Hostname(), rules.crawlDelay)
+	}
+
+	path := target.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	if target.RawQuery != "" {
+		path += "?" + target.RawQuery
+	}
+	return entry.rules.allows(path), nil
+}
+
+// fetch baixa e interpreta o robots.txt da origem, seguindo a RFC 9309:
+// 4xx libera tudo, 5xx bloqueia tudo (por pouco tempo)
+func (c *robotsCache) fetch(ctx context.Context, origin string) (*robotsRules, time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", origin+"/robots.txt", nil)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to create robots.txt request: %w", err)
+	}
+
+	resp, err := c.fetcher.doAPI(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, 0, ctx.Err()
+		}
+		// Sem robots.txt acessível: não impedir a importação, mas tentar de novo em breve
+		log.Warnf("Failed to fetch %s/robots.txt, allowing: %v", origin, err)
+		return &robotsRules{}, robotsErrorTTL, nil
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 500:
+		log.Warnf("%s/robots.txt returned %d, treating site as disallowed", origin, resp.StatusCode)
+		return &robotsRules{disallowAll: true}, robotsErrorTTL, nil
+	case resp.StatusCode >= 400:
+		return &robotsRules{}, robotsTTL, nil
+	case resp.StatusCode != http.StatusOK:
+		return &robotsRules{}, robotsErrorTTL, nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxRobotsBytes))
+	if err != nil {
+		return &robotsRules{}, robotsErrorTTL, nil
+	}
+	return parseRobots(body, robotsAgent), robotsTTL, nil
+}
+
+// parseRobots lê as regras do grupo do agente informado; sem grupo específico,
+// usa o grupo "*". Grupos com o mesmo agente são combinados.
+func parseRobots(body []byte, agent string) *robotsRules {
+	specific := &robotsRules{}
+	wildcard := &robotsRules{}
+	foundSpecific := false
+
+	var current []*robotsRules // Grupos a que as regras seguintes se aplicam
+	inAgents := false          // Linhas User-agent consecutivas formam o mesmo grupo
+
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	scanner.Buffer(make([]byte, 0, 64<<10), maxRobotsBytes)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "user-agent":
+			if !inAgents {
+				current = nil
+			}
+			inAgents = true
+			name := strings.ToLower(value)
+			switch {
+			case name == "*":
+				current = append(current, wildcard)
+			case name == agent || strings.HasPrefix(name, agent+"/"):
+				current = append(current, specific)
+				foundSpecific = true
+			}
+		case "allow", "disallow":
+			inAgents = false
+			if value == "" {
+				continue // "Disallow:" vazio não bloqueia nada
+			}
+			for _, group := range current {
+				group.rules = append(group.rules, robotsRule{allow: key == "allow", pattern: value})
+			}
+		case "crawl-delay":
+			inAgents = false
+			if seconds, err := strconv.ParseFloat(value, 64); err == nil && seconds > 0 {
+				for _, group := range current {
+					group.crawlDelay = time.Duration(seconds * float64(time.Second))
+				}
+			}
+		default:
+			inAgents = false
+		}
+	}
+
+	if foundSpecific {
+		return specific
+	}
+	return wildcard
+}
+
+// allows aplica a regra mais específica (padrão mais longo); em empate, Allow vence
+func (r *robotsRules) allows(path string) bool {
+	if r.disallowAll {
+		return false
+	}
+	if path == "/robots.txt" {
+		return true
+	}
+
+	allowed := true
+	bestLength := -1
+	for _, rule := range r.rules {
+		if !robotsPatternMatches(rule.pattern, path) {
+			continue
+		}
+		length := len(rule.pattern)
+		if length > bestLength || (length == bestLength && rule.allow) {
+			bestLength = length
+			allowed = rule.allow
+		}
+	}
+	return allowed
+}
+
+// robotsPatternMatches compara o caminho com o padrão, que casa pelo prefixo e
+// aceita * (qualquer sequência) e $ (fim do caminho)
+func robotsPatternMatches(pattern, path string) bool {
+	anchored := strings.HasSuffix(pattern, "$")
+	pattern = strings.TrimSuffix(pattern, "$")
+
+	parts := strings.Split(pattern, "*")
+	if !strings.HasPrefix(path, parts[0]) {
+		return false
+	}
+	rest := path[len(parts[0]):]
+
+	for i, part := range parts[1:] {
+		last := i == len(parts)-2
+		if last && anchored {
+			return strings.HasSuffix(rest, part)
+		}
+		idx := strings.Index(rest, part)
+		if idx < 0 {
+			return false
+		}
+		rest = rest[idx+len(part):]
+	}
+
+	return !anchored || rest == ""
+}
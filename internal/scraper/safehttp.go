@@ -0,0 +1,166 @@
+package scraper
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+)
+
+// As URLs importadas vêm de emails e do usuário, então o servidor não pode ser
+// usado para alcançar a rede interna (SSRF): o endereço é verificado depois da
+// resolução DNS, em cada conexão, o que vale também para os redirects.
+
+const (
+	// maxBodyBytes limita o tamanho de cada página baixada (após descompressão)
+	maxBodyBytes = 10 << 20
+
+	// maxRedirects limita a cadeia de redirects de cada requisição
+	maxRedirects = 5
+
+	// requestTimeout limita cada requisição; fetchBudget limita a importação inteira,
+	// somando todas as estratégias do extrator
+	requestTimeout = 30 * time.Second
+	fetchBudget    = 90 * time.Second
+)
+
+// errBlockedAddress indica destino na rede interna (loopback, privada, link-local...)
+var errBlockedAddress = errors.New("destination address not allowed")
+
+// errBodyTooLarge indica resposta maior que maxBodyBytes
+var errBodyTooLarge = fmt.Errorf("response body exceeds %d bytes", maxBodyBytes)
+
+// blockedPrefixes são as faixas especiais que não podem ser acessadas, além das
+// cobertas por netip.Addr (loopback, privada, link-local, multicast e não especificada)
+var blockedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),       // "Esta rede"
+	netip.MustParsePrefix("100.64.0.0/10"),   // CGNAT
+	netip.MustParsePrefix("192.0.0.0/24"),    // Atribuições de protocolo IETF
+	netip.MustParsePrefix("192.0.2.0/24"),    // Documentação
+	netip.MustParsePrefix("198.18.0.0/15"),   // Benchmark
+	netip.MustParsePrefix("198.51.100.0/24"), // Documentação
+	netip.MustParsePrefix("203.0.113.0/24"),  // Documentação
+	netip.MustParsePrefix("240.0.0.0/4"),     // Reservada (inclui broadcast)
+	netip.MustParsePrefix("64:ff9b::/96"),    // NAT64 (pode mapear endereços internos)
+	netip.MustParsePrefix("64:ff9b:1::/48"),  // NAT64 local
+	netip.MustParsePrefix("2001:db8::/32"),   // Documentação
+	netip.MustParsePrefix("2002::/16"),       // 6to4 (embute um IPv4 qualquer)
+}
+
+// isBlockedAddress informa se o IP pertence a uma faixa interna ou reservada
+func isBlockedAddress(addr netip.Addr) bool {
+	addr = addr.Unmap() // ::ffff:127.0.0.1 é 127.0.0.1
+
+	if !addr.IsValid() || addr.IsLoopback() || addr.IsPrivate() || addr.IsUnspecified() ||
+		addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() ||
+		addr.IsInterfaceLocalMulticast() || addr.IsMulticast() {
+		return true
+	}
+	for _, prefix := range blockedPrefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// guardedControl recusa a conexão quando o endereço já resolvido é interno
+func guardedControl(network, address string, conn syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return fmt.Errorf("%w: %s", errBlockedAddress, address)
+	}
+	if isBlockedAddress(addrPort.Addr()) {
+		return fmt.Errorf("%w: %s", errBlockedAddress, addrPort.Addr())
+	}
+	return nil
+}
+
+// safeTransport é compartilhado por todos os clientes do scraper (reaproveita conexões).
+// Proxy fica desativado: com proxy a verificação veria só o endereço dele.
+var safeTransport = &http.Transport{
+	Proxy: nil,
+	DialContext: (&net.Dialer{
+		Timeout:   10 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control:   guardedControl,
+	}).DialContext,
+	TLSClientConfig: &tls.Config{
+		MinVersion: tls.VersionTLS12,
+	},
+	TLSHandshakeTimeout:    10 * time.Second,
+	ResponseHeaderTimeout:  20 * time.Second,
+	MaxIdleConns:           100,
+	MaxIdleConnsPerHost:    10,
+	IdleConnTimeout:        90 * time.Second,
+	MaxResponseHeaderBytes: 1 << 20,
+}
+
+// NewSafeClient cria um cliente HTTP que só acessa endereços públicos, com no
+// máximo maxRedirects redirects (apenas para http/https). Também é usado pelo cache
+// de miniaturas, que baixa imagens de URLs vindas dos emails.
+func NewSafeClient(timeout time.Duration) *http.Client {
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: safeTransport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= maxRedirects {
+				return fmt.Errorf("too many redirects")
+			}
+			if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+				return fmt.Errorf("redirect to unsupported scheme %q", req.URL.Scheme)
+			}
+			// IP literal interno: falhar antes de tentar conectar
+			if addr, err := netip.ParseAddr(req.URL.Hostname()); err == nil && isBlockedAddress(addr) {
+				return fmt.Errorf("%w: redirect to %s", errBlockedAddress, addr)
+			}
+			// Copiar headers para o redirect
+			for key, val := range via[0].Header {
+				req.Header[key] = val
+			}
+			return nil
+		},
+	}
+}
+
+// readLimited lê o corpo inteiro, falhando se passar de maxBodyBytes
+func readLimited(r io.Reader) ([]byte, error) {
+	body, err := io.ReadAll(io.LimitReader(r, maxBodyBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(body) > maxBodyBytes {
+		return nil, errBodyTooLarge
+	}
+	return body, nil
+}
+
+// isHTMLContentType verifica se o Content-Type é de uma página HTML
+func isHTMLContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "text/html" || mediaType == "application/xhtml+xml"
+}
+
+// isPDFContentType verifica se o Content-Type é de um documento PDF
+func isPDFContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/pdf" || mediaType == "application/x-pdf"
+}
+
+// isOctetStream verifica se o servidor não declarou um tipo útil
+func isOctetStream(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && (mediaType == "application/octet-stream" || mediaType == "binary/octet-stream")
+}
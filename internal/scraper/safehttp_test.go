@@ -0,0 +1,89 @@
+package scraper
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestIsBlockedAddress(t *testing.T) {
+	tests := []struct {
+		addr    string
+		blocked bool
+	}{
+		{"127.0.0.1", true},
+		{"127.8.8.8", true},
+		{"10.1.2.3", true},
+		{"172.16.0.1", true},
+		{"192.168.1.1", true},
+		{"169.254.169.254", true},
+		{"100.64.0.1", true},
+		{"0.0.0.0", true},
+		{"255.255.255.255", true},
+		{"224.0.0.1", true},
+		{"::1", true},
+		{"::", true},
+		{"fe80::1", true},
+		{"fd00::1", true},
+		{"::ffff:127.0.0.1", true},
+		{"::ffff:169.254.169.254", true},
+		{"64:ff9b::a9fe:a9fe", true},
+		{"8.8.8.8", false},
+		{"151.101.1.69", false},
+		{"2606:4700::6810:84e5", false},
+	}
+
+	for _, tt := range tests {
+		if got := isBlockedAddress(netip.MustParseAddr(tt.addr)); got != tt.blocked {
+			t.Errorf("isBlockedAddress(%s) = %v, want %v", tt.addr, got, tt.blocked)
+		}
+	}
+}
+
+func TestSafeClientRefusesLoopback(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("internal"))
+	}))
+	defer server.Close()
+
+	// Também pelo nome: a verificação acontece depois da resolução DNS
+	for _, target := range []string{server.URL, strings.Replace(server.URL, "127.0.0.1", "localhost", 1)} {
+		resp, err := NewSafeClient(5 * time.Second).Get(target)
+		if err == nil {
+			resp.Body.Close()
+			t.Fatalf("request to %s succeeded, want blocked", target)
+		}
+		if !errors.Is(err, errBlockedAddress) {
+			t.Errorf("request to %s: got %v, want errBlockedAddress", target, err)
+		}
+	}
+}
+
+func TestReadLimited(t *testing.T) {
+	if _, err := readLimited(strings.NewReader(strings.Repeat("a", maxBodyBytes))); err != nil {
+		t.Errorf("body at the limit: %v", err)
+	}
+	if _, err := readLimited(strings.NewReader(strings.Repeat("a", maxBodyBytes+1))); !errors.Is(err, errBodyTooLarge) {
+		t.Errorf("body over the limit: got %v, want errBodyTooLarge", err)
+	}
+}
+
+func TestIsHTMLContentType(t *testing.T) {
+	for contentType, want := range map[string]bool{
+		"text/html":                       true,
+		"text/html; charset=utf-8":        true,
+		"application/xhtml+xml":           true,
+		"application/pdf":                 false,
+		"image/png":                       false,
+		"application/json":                false,
+		"text/html-sandboxed; charset=x=": false,
+	} {
+		if got := isHTMLContentType(contentType); got != want {
+			t.Errorf("isHTMLContentType(%q) = %v, want %v", contentType, got, want)
+		}
+	}
+}
@@ -0,0 +1,273 @@
+package scraper
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// O conteúdo salvo na lista de leitura é exibido com dangerouslySetInnerHTML, então
+// todo HTML extraído passa por uma allowlist: só as tags, atributos e esquemas de URL
+// abaixo sobrevivem. Tags desconhecidas são desembrulhadas (o texto é mantido) e as
+// perigosas são removidas com todo o conteúdo.
+
+// allowedTags lista as tags mantidas e os atributos permitidos em cada uma
+var allowedTags = map[string]map[string]bool{
+	"a":          {"href": true, "title": true},
+	"abbr":       {"title": true},
+	"article":    {},
+	"b":          {},
+	"blockquote": {"cite": true},
+	"br":         {},
+	"caption":    {},
+	"cite":       {},
+	"code":       {"class": true},
+	"col":        {"span": true},
+	"colgroup":   {"span": true},
+	"dd":         {},
+	"del":        {"cite": true, "datetime": true},
+	"details":    {"open": true},
+	"dfn":        {},
+	"div":        {},
+	"dl":         {},
+	"dt":         {},
+	"em":         {},
+	"figcaption": {},
+	"figure":     {},
+	"h1":         {},
+	"h2":         {},
+	"h3":         {},
+	"h4":         {},
+	"h5":         {},
+	"h6":         {},
+	"hr":         {},
+	"i":          {},
+	"img":        {"src": true, "alt": true, "title": true, "width": true, "height": true},
+	"ins":        {"cite": true, "datetime": true},
+	"kbd":        {},
+	"li":         {"value": true},
+	"mark":       {},
+	"ol":         {"start": true, "reversed": true, "type": true},
+	"p":          {},
+	"pre":        {"class": true},
+	"q":          {"cite": true},
+	"s":          {},
+	"samp":       {},
+	"section":    {},
+	"small":      {},
+	"span":       {"class": true},
+	"strong":     {},
+	"sub":        {},
+	"summary":    {},
+	"sup":        {},
+	"table":      {},
+	"tbody":      {},
+	"td":         {"colspan": true, "rowspan": true},
+	"tfoot":      {},
+	"th":         {"colspan": true, "rowspan": true, "scope": true},
+	"thead":      {},
+	"time":       {"datetime": true},
+	"tr":         {},
+	"u":          {},
+	"ul":         {},
+	"var":        {},
+}
+
+// globalAttributes são permitidos em qualquer tag mantida
+var globalAttributes = map[string]bool{"lang": true, "dir": true}
+
+// droppedTags são removidas junto com todo o conteúdo
+var droppedTags = map[string]bool{
+	"script": true, "style": true, "noscript": true, "template": true,
+	"iframe": true, "frame": true, "frameset": true, "object": true, "embed": true, "applet": true,
+	"form": true, "input": true, "button": true, "select": true, "textarea": true, "option": true,
+	"svg": true, "math": true, "canvas": true, "audio": true, "video": true, "source": true, "track": true,
+	"head": true, "title": true, "meta": true, "link": true, "base": true,
+	"dialog": true, "portal": true, "xmp": true, "plaintext": true, "noembed": true, "noframes": true,
+}
+
+// urlAttributes contêm URLs: são resolvidas para absolutas e têm o esquema validado
+var urlAttributes = map[string]bool{"href": true, "src": true, "cite": true}
+
+// codeClassPattern mantém só as classes de linguagem usadas no realce de sintaxe
+var codeClassPattern = regexp.MustCompile(`^(language|lang|highlight)-[A-Za-z0-9_+#-]+$`)
+
+// dataImagePattern aceita imagens embutidas apenas em formatos raster
+var dataImagePattern = regexp.MustCompile(`^data:image/(png|jpeg|jpg|gif|webp);base64,[A-Za-z0-9+/=\s]+$`)
+
+// numericPattern valida atributos numéricos (colspan, width...)
+var numericPattern = regexp.MustCompile(`^[0-9]{1,4}%?$`)
+
+// sanitizeHTML reduz o HTML ao subconjunto seguro da allowlist, resolvendo URLs
+// relativas contra a URL da página
+func sanitizeHTML(content string, pageURL *url.URL) string {
+	if content == "" {
+		return ""
+	}
+
+	body := &html.Node{Type: html.ElementNode, Data: "body", DataAtom: atom.Body}
+	nodes, err := html.ParseFragment(strings.NewReader(content), body)
+	if err != nil {
+		log.Warnf("Failed to parse content for sanitization: %v", err)
+		return html.EscapeString(content)
+	}
+
+	var out strings.Builder
+	for _, node := range nodes {
+		for _, clean := range sanitizeNode(node, pageURL) {
+			if err := html.Render(&out, clean); err != nil {
+				log.Warnf("Failed to render sanitized content: %v", err)
+				return ""
+			}
+		}
+	}
+	return out.String()
+}
+
+// sanitizeNode devolve a cópia segura do nó: zero nós (removido), o próprio
+// elemento limpo, ou os filhos limpos (tag desembrulhada)
+func sanitizeNode(node *html.Node, pageURL *url.URL) []*html.Node {
+	switch node.Type {
+	case html.TextNode:
+		return []*html.Node{{Type: html.TextNode, Data: node.Data}}
+	case html.ElementNode:
+	case html.DocumentNode:
+		return sanitizeChildren(node, pageURL)
+	default:
+		// Comentários, doctype e afins
+		return nil
+	}
+
+	// Elementos em namespace (svg, math) já caem em droppedTags; demais namespaces também
+	tag := strings.ToLower(node.Data)
+	if droppedTags[tag] || node.Namespace != "" {
+		return nil
+	}
+
+	allowedAttrs, ok := allowedTags[tag]
+	if !ok {
+		return sanitizeChildren(node, pageURL)
+	}
+
+	clean := &html.Node{Type: html.ElementNode, Data: tag, DataAtom: atom.Lookup([]byte(tag))}
+	for _, attr := range node.Attr {
+		key := strings.ToLower(attr.Key)
+		if attr.Namespace != "" || (!allowedAttrs[key] && !globalAttributes[key]) {
+			continue
+		}
+		if value, ok := sanitizeAttribute(tag, key, attr.Val, pageURL); ok {
+			clean.Attr = append(clean.Attr, html.Attribute{Key: key, Val: value})
+		}
+	}
+
+	switch tag {
+	case "img":
+		// Imagem sem src válido não tem o que mostrar
+		if !hasAttribute(clean, "src") {
+			return nil
+		}
+	case "a":
+		if !hasAttribute(clean, "href") {
+			return sanitizeChildren(node, pageURL)
+		}
+		clean.Attr = append(clean.Attr,
+			html.Attribute{Key: "target", Val: "_blank"},
+			html.Attribute{Key: "rel", Val: "noopener noreferrer nofollow"},
+		)
+	}
+
+	for _, child := range sanitizeChildren(node, pageURL) {
+		clean.AppendChild(child)
+	}
+	return []*html.Node{clean}
+}
+
+// sanitizeChildren limpa os filhos do nó, na ordem
+func sanitizeChildren(node *html.Node, pageURL *url.URL) []*html.Node {
+	var children []*html.Node
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		children = append(children, sanitizeNode(child, pageURL)...)
+	}
+	return children
+}
+
+// sanitizeAttribute valida o valor do atributo; false descarta o atributo
+func sanitizeAttribute(tag, key, value string, pageURL *url.URL) (string, bool) {
+	value = strings.TrimSpace(value)
+
+	switch {
+	case urlAttributes[key]:
+		return sanitizeURL(value, pageURL, tag == "img" && key == "src")
+	case key == "class":
+		// Só classes de linguagem em <pre>/<code> e de token (ver highlight.go) em <span>
+		var classes []string
+		for _, class := range strings.Fields(value) {
+			if tag == "span" {
+				if mapped, ok := gitHubTokenClasses[class]; ok {
+					class = mapped
+				}
+				if tokenClasses[class] {
+					classes = append(classes, class)
+				}
+			} else if codeClassPattern.MatchString(class) {
+				classes = append(classes, class)
+			}
+		}
+		return strings.Join(classes, " "), len(classes) > 0
+	case key == "width" || key == "height" || key == "colspan" || key == "rowspan" ||
+		key == "span" || key == "start" || key == "value":
+		return value, numericPattern.MatchString(value)
+	case key == "type":
+		return value, value == "1" || value == "a" || value == "A" || value == "i" || value == "I"
+	case key == "dir":
+		return value, value == "ltr" || value == "rtl" || value == "auto"
+	case key == "scope":
+		return value, value == "row" || value == "col" || value == "rowgroup" || value == "colgroup"
+	}
+	return value, true
+}
+
+// sanitizeURL resolve a URL contra a página e aceita apenas http(s), mailto
+// (em links) e imagens raster embutidas (em img src)
+func sanitizeURL(value string, pageURL *url.URL, imageSource bool) (string, bool) {
+	if value == "" {
+		return "", false
+	}
+
+	if strings.HasPrefix(strings.ToLower(value), "data:") {
+		return value, imageSource && dataImagePattern.MatchString(value)
+	}
+
+	// url.Parse recusa caracteres de controle, o que já descarta "java\tscript:"
+	parsed, err := url.Parse(value)
+	if err != nil {
+		return "", false
+	}
+	if pageURL != nil {
+		parsed = pageURL.ResolveReference(parsed)
+	}
+
+	switch parsed.Scheme {
+	case "http", "https":
+		if parsed.Host == "" {
+			return "", false
+		}
+		return parsed.String(), true
+	case "mailto":
+		return parsed.String(), !imageSource
+	}
+	return "", false
+}
+
+// hasAttribute verifica se o nó tem o atributo
+func hasAttribute(node *html.Node, key string) bool {
+	for _, attr := range node.Attr {
+		if attr.Key == key {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,86 @@
+package scraper
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestSanitizeHTML(t *testing.T) {
+	pageURL, _ := url.Parse("https://blog.example.com/posts/article.html")
+
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"plain paragraph", `<p>Hello <strong>world</strong></p>`, `<p>Hello <strong>world</strong></p>`},
+		{"script", `<p>a</p><script>alert(1)</script>`, `<p>a</p>`},
+		{"script uppercase", `<SCRIPT SRC=//evil.example/x.js></SCRIPT>b`, `b`},
+		{"event handler", `<img src="x.png" onerror="alert(1)">`, `<img src="https://blog.example.com/posts/x.png"/>`},
+		{"unlisted handler", `<p onpointerenter="alert(1)" onanimationstart="x">t</p>`, `<p>t</p>`},
+		{"javascript href", `<a href="javascript:alert(1)">x</a>`, `x`},
+		{"javascript href mixed case", `<a href="JaVaScRiPt:alert(1)">x</a>`, `x`},
+		{"javascript href entities", `<a href="&#106;avascript:alert(1)">x</a>`, `x`},
+		{"javascript href tab", "<a href=\"java\tscript:alert(1)\">x</a>", `x`},
+		{"javascript href leading space", `<a href="  javascript:alert(1)">x</a>`, `x`},
+		{"vbscript href", `<a href="vbscript:msgbox(1)">x</a>`, `x`},
+		{"data href", `<a href="data:text/html;base64,PHNjcmlwdD5hbGVydCgxKTwvc2NyaXB0Pg==">x</a>`, `x`},
+		{"data svg image", `<img src="data:image/svg+xml;base64,PHN2Zz48L3N2Zz4=">`, ``},
+		{"data png image", `<img src="data:image/png;base64,iVBORw0KGgo=">`, `<img src="data:image/png;base64,iVBORw0KGgo="/>`},
+		{"iframe", `<iframe src="https://evil.example"></iframe>ok`, `ok`},
+		{"object and embed", `<object data="x.swf"><embed src="x.swf"></object>ok`, `ok`},
+		{"form", `<form action="https://evil.example"><input name="p"><button>Go</button></form>ok`, `ok`},
+		{"style tag", `<style>body{background:url(javascript:alert(1))}</style>ok`, `ok`},
+		{"style attribute", `<p style="background:url(javascript:alert(1))">t</p>`, `<p>t</p>`},
+		{"svg", `<svg><script>alert(1)</script><a xlink:href="javascript:alert(1)">x</a></svg>ok`, `ok`},
+		{"svg onload", `<svg onload=alert(1)>`, ``},
+		{"math", `<math><mtext><a href="javascript:alert(1)">x</a></mtext></math>ok`, `ok`},
+		{"meta refresh", `<meta http-equiv="refresh" content="0;url=javascript:alert(1)">ok`, `ok`},
+		{"base", `<base href="https://evil.example/">ok`, `ok`},
+		{"comment", `<!--<script>alert(1)</script>-->ok`, `ok`},
+		{"conditional comment", `<!--[if IE]><script>alert(1)</script><![endif]-->ok`, `ok`},
+		{"unknown tag unwrapped", `<font color="red"><custom-el onclick="x">text</custom-el></font>`, `text`},
+		{"template", `<template><img src=x onerror=alert(1)></template>ok`, `ok`},
+		{"noscript", `<noscript><p title="</noscript><img src=x onerror=alert(1)>"></noscript>`, `<img src="https://blog.example.com/posts/x"/>&#34;&gt;`},
+		{"escaped text stays text", `<p>&lt;script&gt;alert(1)&lt;/script&gt;</p>`, `<p>&lt;script&gt;alert(1)&lt;/script&gt;</p>`},
+		{"attribute breakout", `<img src="x.png" alt='"><script>alert(1)</script>'>`, `<img src="https://blog.example.com/posts/x.png" alt="&#34;&gt;&lt;script&gt;alert(1)&lt;/script&gt;"/>`},
+		{"id and class removed", `<div id="header" class="x" data-x="1">t</div>`, `<div>t</div>`},
+		{"code language class kept", `<pre class="chroma"><code class="language-go evil">x := 1</code></pre>`, `<pre><code class="language-go">x := 1</code></pre>`},
+		{"token classes kept", `<span class="tok-keyword evil">func</span><span class="pl-c">// c</span><span class="x">y</span>`, `<span class="tok-keyword">func</span><span class="tok-comment">// c</span><span>y</span>`},
+		{"relative link", `<a href="../about">About</a>`, `<a href="https://blog.example.com/about" target="_blank" rel="noopener noreferrer nofollow">About</a>`},
+		{"protocol-relative image", `<img src="//cdn.example.com/a.png" alt="A">`, `<img src="https://cdn.example.com/a.png" alt="A"/>`},
+		{"fragment link", `<a href="#section">S</a>`, `<a href="https://blog.example.com/posts/article.html#section" target="_blank" rel="noopener noreferrer nofollow">S</a>`},
+		{"mailto link", `<a href="mailto:me@example.com">mail</a>`, `<a href="mailto:me@example.com" target="_blank" rel="noopener noreferrer nofollow">mail</a>`},
+		{"mailto image", `<img src="mailto:me@example.com">`, ``},
+		{"target overridden", `<a href="/x" target="_self" rel="opener">x</a>`, `<a href="https://blog.example.com/x" target="_blank" rel="noopener noreferrer nofollow">x</a>`},
+		{"invalid width", `<img src="a.png" width="100;background:red">`, `<img src="https://blog.example.com/posts/a.png"/>`},
+		{"table", `<table><tr><td colspan="2" onclick="x">c</td></tr></table>`, `<table><tbody><tr><td colspan="2">c</td></tr></tbody></table>`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := sanitizeHTML(tt.in, pageURL)
+			if got != tt.want {
+				t.Errorf("sanitizeHTML(%q)\n got: %s\nwant: %s", tt.in, got, tt.want)
+			}
+
+			// Nenhum vetor pode sobreviver, independentemente do formato exato da saída
+			lower := strings.ToLower(got)
+			for _, forbidden := range []string{"<script", "javascript:", "vbscript:", "onerror", "onload", "<iframe", "<svg", "<form", "style=", "<style"} {
+				if strings.Contains(lower, forbidden) {
+					t.Errorf("output still contains %q: %s", forbidden, got)
+				}
+			}
+		})
+	}
+}
+
+func TestSanitizeHTMLIdempotent(t *testing.T) {
+	pageURL, _ := url.Parse("https://blog.example.com/")
+	in := `<p>Read <a href="/docs">the docs</a> <img src="a.png" alt="x"></p><pre><code class="language-go">fmt.Println()</code></pre>`
+	once := sanitizeHTML(in, pageURL)
+	if twice := sanitizeHTML(once, pageURL); twice != once {
+		t.Errorf("sanitizing twice changed the output:\n%s\n%s", once, twice)
+	}
+}
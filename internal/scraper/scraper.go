@@ -1,17 +1,12 @@
 package scraper
 
 import (
-	"compress/gzip"
-	"crypto/tls"
-	"encoding/json"
+	"bytes"
+	"context"
 	"fmt"
-	"io"
-	"math/rand"
-	"net/http"
 	"net/url"
 	"regexp"
 	"strings"
-	"time"
 
 	"github.com/PuerkitoBio/goquery"
 	"github.com/sirupsen/logrus"
@@ -19,343 +14,137 @@ import (
 
 var log = logrus.New()
 
-// Lista de User-Agents para rotação
-var userAgents = []string{
-	"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
-	"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
-	"Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:121.0) Gecko/20100101 Firefox/121.0",
-	"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.2 Safari/605.1.15",
-	"Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
-}
-
 // ArticleContent representa o conteúdo extraído de um artigo
 type ArticleContent struct {
-	Title       string
-	Content     string // HTML do conteúdo principal
-	ContentType string // "html" ou "text"
+	Title        string
+	Content      string // HTML do conteúdo principal
+	ContentType  string // "html" ou "text"
+	CanonicalURL string // rel=canonical ou og:url da página, quando declarado
+	Metadata     ArticleMetadata
+	Attachment   *Attachment // Arquivo original, quando o artigo não é uma página (PDF)
+
+	baseURL      *url.URL // URL final da página, para resolver links relativos na sanitização
+	mediumPostID string   // ID do post quando a página é servida pelo Medium
 }
 
-// getRandomUserAgent retorna um User-Agent aleatório
-func getRandomUserAgent() string {
-	return userAgents[rand.Intn(len(userAgents))]
-}
-
-// createHTTPClient cria um cliente HTTP otimizado para scraping
-func createHTTPClient() *http.Client {
-	transport := &http.Transport{
-		TLSClientConfig: &tls.Config{
-			InsecureSkipVerify: false,
-			MinVersion:         tls.VersionTLS12,
-		},
-		MaxIdleConns:        100,
-		MaxIdleConnsPerHost: 10,
-		IdleConnTimeout:     90 * time.Second,
-		DisableCompression:  false,
-	}
-
-	return &http.Client{
-		Timeout:   45 * time.Second,
-		Transport: transport,
-		CheckRedirect: func(req *http.Request, via []*http.Request) error {
-			if len(via) >= 10 {
-				return fmt.Errorf("too many redirects")
-			}
-			// Copiar headers para o redirect
-			for key, val := range via[0].Header {
-				req.Header[key] = val
-			}
-			return nil
-		},
-	}
+// Attachment é o arquivo original do artigo, guardado junto do item da lista
+type Attachment struct {
+	Filename    string
+	ContentType string
+	Data        []byte
 }
 
 // FetchArticleContent busca e extrai o conteúdo principal de um artigo
 func FetchArticleContent(originalURL string) (*ArticleContent, error) {
-	log.Infof("Fetching article content from: %s", originalURL)
-
-	// Detectar o tipo de site e usar estratégia apropriada
-	parsedURL, err := url.Parse(originalURL)
-	if err != nil {
-		return nil, fmt.Errorf("invalid URL: %w", err)
-	}
-
-	host := strings.ToLower(parsedURL.Host)
-
-	// Estratégias específicas por site
-	if strings.Contains(host, "medium.com") || strings.Contains(host, "towardsdatascience.com") ||
-		strings.Contains(host, "levelup.gitconnected.com") || strings.Contains(host, "betterprogramming.pub") {
-		return fetchMediumArticle(originalURL)
-	}
-
-	if strings.Contains(host, "dev.to") {
-		return fetchDevToArticle(originalURL)
-	}
-
-	if strings.Contains(host, "github.com") {
-		return fetchGitHubContent(originalURL)
-	}
-
-	if strings.Contains(host, "substack.com") || strings.Contains(host, ".substack.com") {
-		return fetchSubstackArticle(originalURL)
-	}
-
-	// Fallback para scraping genérico
-	return fetchGenericArticle(originalURL)
+	return FetchArticleContentContext(context.Background(), originalURL)
 }
 
-// fetchMediumArticle busca artigo do Medium usando técnicas avançadas
-func fetchMediumArticle(articleURL string) (*ArticleContent, error) {
-	log.Infof("Using Medium-specific strategy for: %s", articleURL)
-
-	// Tentar primeiro o endpoint de exportação do Medium (formato texto limpo)
-	// Medium tem um endpoint ?format=json que às vezes funciona
-	client := createHTTPClient()
-
-	// Estratégia 1: Tentar via Freedium (proxy que remove paywall)
-	freediumURL := strings.Replace(articleURL, "medium.com", "freedium.cfd", 1)
-	freediumURL = strings.Replace(freediumURL, "towardsdatascience.com", "freedium.cfd", 1)
-
-	content, err := tryFetchWithHeaders(client, freediumURL, map[string]string{
-		"User-Agent":      getRandomUserAgent(),
-		"Accept":          "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8",
-		"Accept-Language": "en-US,en;q=0.9",
-		"Accept-Encoding": "gzip, deflate",
-		"Connection":      "keep-alive",
-		"Referer":         "https://www.google.com/",
-	})
-
-	if err == nil && content != nil && len(content.Content) > 500 {
-		log.Info("Successfully fetched via Freedium proxy")
-		return content, nil
-	}
-
-	// Estratégia 2: Tentar scribe.rip (outro proxy para Medium)
-	scribeURL := strings.Replace(articleURL, "medium.com", "scribe.rip", 1)
-	scribeURL = strings.Replace(scribeURL, "towardsdatascience.com", "scribe.rip", 1)
-
-	content, err = tryFetchWithHeaders(client, scribeURL, map[string]string{
-		"User-Agent":      getRandomUserAgent(),
-		"Accept":          "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8",
-		"Accept-Language": "en-US,en;q=0.9",
-		"Referer":         "https://www.google.com/",
-	})
-
-	if err == nil && content != nil && len(content.Content) > 500 {
-		log.Info("Successfully fetched via Scribe.rip proxy")
-		return content, nil
-	}
-
-	// Estratégia 3: Tentar direto com headers de cache do Google
-	content, err = tryFetchWithHeaders(client, articleURL, map[string]string{
-		"User-Agent":                getRandomUserAgent(),
-		"Accept":                    "text/html,application/xhtml+xml,application/xml;q=0.9,image/avif,image/webp,image/apng,*/*;q=0.8",
-		"Accept-Language":           "en-US,en;q=0.9",
-		"Accept-Encoding":           "gzip, deflate, br",
-		"Connection":                "keep-alive",
-		"Upgrade-Insecure-Requests": "1",
-		"Sec-Fetch-Dest":            "document",
-		"Sec-Fetch-Mode":            "navigate",
-		"Sec-Fetch-Site":            "cross-site",
-		"Sec-Fetch-User":            "?1",
-		"Cache-Control":             "max-age=0",
-		"Referer":                   "https://www.google.com/",
-	})
-
-	if err == nil && content != nil && len(content.Content) > 200 {
-		return content, nil
-	}
-
-	// Estratégia 4: Usar Google Cache
-	googleCacheURL := fmt.Sprintf("https://webcache.googleusercontent.com/search?q=cache:%s", url.QueryEscape(articleURL))
-	content, err = tryFetchWithHeaders(client, googleCacheURL, map[string]string{
-		"User-Agent": getRandomUserAgent(),
-		"Accept":     "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8",
-	})
-
-	if err == nil && content != nil && len(content.Content) > 500 {
-		log.Info("Successfully fetched via Google Cache")
-		return content, nil
-	}
-
-	return nil, fmt.Errorf("could not fetch Medium article after trying multiple strategies")
+// FetchArticleContentContext busca o artigo com o extrator registrado para o site,
+// respeitando o cancelamento do contexto
+func FetchArticleContentContext(ctx context.Context, originalURL string) (*ArticleContent, error) {
+	log.Infof("Fetching article content from: %s", originalURL)
+	return defaultRegistry.Fetch(ctx, originalURL)
 }
 
-// fetchDevToArticle busca artigo do Dev.to
-func fetchDevToArticle(articleURL string) (*ArticleContent, error) {
-	log.Infof("Using Dev.to-specific strategy for: %s", articleURL)
-
-	client := createHTTPClient()
+// ReextractArticleContent extrai de novo o artigo da página em cache, sem acessar
+// a rede; ErrNotCached quando a página não está no cache
+func ReextractArticleContent(originalURL string) (*ArticleContent, error) {
+	return defaultRegistry.Reextract(originalURL)
+}
 
-	// Dev.to geralmente funciona bem com headers simples
-	content, err := tryFetchWithHeaders(client, articleURL, map[string]string{
-		"User-Agent":      getRandomUserAgent(),
-		"Accept":          "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8",
-		"Accept-Language": "en-US,en;q=0.9",
-		"Accept-Encoding": "gzip, deflate",
-		"Connection":      "keep-alive",
-	})
+// tryFetchWithHeaders tenta buscar conteúdo com headers específicos
+func tryFetchWithHeaders(ctx context.Context, targetURL string, headers map[string]string, extract contentFunc) (*ArticleContent, error) {
+	page, err := defaultFetcher.fetchPage(ctx, targetURL, headers)
+	if err != nil {
+		return nil, err
+	}
 
+	content, err := articleFromPage(page, extract)
 	if err != nil {
 		return nil, err
 	}
 
+	log.Infof("Successfully extracted article content (%d chars) from: %s", len(content.Content), targetURL)
 	return content, nil
 }
 
-// fetchGitHubContent busca conteúdo do GitHub (README, arquivos, etc)
-func fetchGitHubContent(githubURL string) (*ArticleContent, error) {
-	log.Infof("Using GitHub-specific strategy for: %s", githubURL)
-
-	client := createHTTPClient()
-	parsedURL, _ := url.Parse(githubURL)
-	path := parsedURL.Path
-
-	// Verificar se é um repositório (para pegar o README)
-	pathParts := strings.Split(strings.Trim(path, "/"), "/")
-
-	if len(pathParts) >= 2 {
-		owner := pathParts[0]
-		repo := pathParts[1]
-
-		// Tentar buscar README via API do GitHub (não tem rate limit tão restrito para leitura)
-		readmeAPIURL := fmt.Sprintf("https://api.github.com/repos/%s/%s/readme", owner, repo)
-
-		req, err := http.NewRequest("GET", readmeAPIURL, nil)
-		if err == nil {
-			req.Header.Set("Accept", "application/vnd.github.html+json")
-			req.Header.Set("User-Agent", "Gmail-Scanner-Bot/1.0")
-
-			resp, err := client.Do(req)
-			if err == nil && resp.StatusCode == http.StatusOK {
-				defer resp.Body.Close()
-				body, _ := io.ReadAll(resp.Body)
-
-				// A API retorna JSON com o conteúdo em base64 ou HTML
-				var result map[string]interface{}
-				if json.Unmarshal(body, &result) == nil {
-					if htmlContent, ok := result["content"].(string); ok {
-						// Decodificar base64 se necessário
-						return &ArticleContent{
-							Title:       fmt.Sprintf("%s/%s README", owner, repo),
-							Content:     htmlContent,
-							ContentType: "html",
-						}, nil
-					}
-				}
-			}
-		}
+// articleFromPage extrai o artigo da página baixada; PDFs têm extração própria (pdf.go)
+func articleFromPage(page *fetchedPage, extract contentFunc) (*ArticleContent, error) {
+	if isPDFContentType(page.Header.Get("Content-Type")) {
+		return pdfArticle(page)
 	}
-
-	// Fallback: scraping normal
-	content, err := tryFetchWithHeaders(client, githubURL, map[string]string{
-		"User-Agent":      getRandomUserAgent(),
-		"Accept":          "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8",
-		"Accept-Language": "en-US,en;q=0.9",
-	})
-
-	return content, err
+	return extractArticle(page.Body, page.URL, extract)
 }
 
-// fetchSubstackArticle busca artigo do Substack
-func fetchSubstackArticle(articleURL string) (*ArticleContent, error) {
-	log.Infof("Using Substack-specific strategy for: %s", articleURL)
-
-	client := createHTTPClient()
-
-	// Substack geralmente permite acesso ao conteúdo público
-	content, err := tryFetchWithHeaders(client, articleURL, map[string]string{
-		"User-Agent":      getRandomUserAgent(),
-		"Accept":          "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8",
-		"Accept-Language": "en-US,en;q=0.9",
-		"Accept-Encoding": "gzip, deflate",
-		"Connection":      "keep-alive",
-		"Referer":         "https://substack.com/",
-	})
-
+// extractArticle extrai título, metadados e conteúdo principal do HTML da página
+// (baixado agora ou lido do cache)
+func extractArticle(body []byte, pageURL *url.URL, extract contentFunc) (*ArticleContent, error) {
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(body))
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to parse HTML: %w", err)
 	}
 
-	return content, nil
-}
+	// Extrair título
+	title := extractTitle(doc)
 
-// fetchGenericArticle usa scraping genérico
-func fetchGenericArticle(articleURL string) (*ArticleContent, error) {
-	log.Infof("Using generic strategy for: %s", articleURL)
+	// URL canônica declarada pela página (antes de remover elementos do documento)
+	canonicalURL := extractCanonicalURL(doc, pageURL)
 
-	client := createHTTPClient()
+	// Metadados (JSON-LD fica em <script>, removido junto com os elementos indesejados)
+	metadata := extractMetadata(doc, pageURL)
 
-	content, err := tryFetchWithHeaders(client, articleURL, map[string]string{
-		"User-Agent":                getRandomUserAgent(),
-		"Accept":                    "text/html,application/xhtml+xml,application/xml;q=0.9,image/webp,*/*;q=0.8",
-		"Accept-Language":           "en-US,en;q=0.9,pt-BR;q=0.8",
-		"Accept-Encoding":           "gzip, deflate",
-		"Connection":                "keep-alive",
-		"Upgrade-Insecure-Requests": "1",
-	})
+	// Publicações do Medium em domínio próprio se revelam pelas meta tags do app
+	mediumPostID := detectMediumPost(doc)
 
-	return content, err
-}
+	// Extrair conteúdo principal
+	content := extractMainContent(doc, extract)
 
-// tryFetchWithHeaders tenta buscar conteúdo com headers específicos
-func tryFetchWithHeaders(client *http.Client, targetURL string, headers map[string]string) (*ArticleContent, error) {
-	req, err := http.NewRequest("GET", targetURL, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+	if content == "" {
+		return nil, fmt.Errorf("could not extract article content")
 	}
 
-	for key, value := range headers {
-		req.Header.Set(key, value)
-	}
+	return &ArticleContent{
+		Title:        title,
+		Content:      content,
+		ContentType:  "html",
+		CanonicalURL: canonicalURL,
+		Metadata:     metadata,
+		baseURL:      pageURL,
+		mediumPostID: mediumPostID,
+	}, nil
+}
 
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch: %w", err)
+// extractCanonicalURL lê <link rel="canonical"> ou og:url, resolvendo URLs relativas
+func extractCanonicalURL(doc *goquery.Document, pageURL *url.URL) string {
+	candidates := []string{
+		doc.Find("link[rel='canonical']").First().AttrOr("href", ""),
+		doc.Find("meta[property='og:url']").First().AttrOr("content", ""),
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("bad status code: %d", resp.StatusCode)
-	}
+	for _, candidate := range candidates {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "" {
+			continue
+		}
 
-	// Ler o corpo (com suporte a gzip)
-	var reader io.Reader = resp.Body
-	if resp.Header.Get("Content-Encoding") == "gzip" {
-		gzReader, err := gzip.NewReader(resp.Body)
+		ref, err := url.Parse(candidate)
 		if err != nil {
-			return nil, fmt.Errorf("failed to create gzip reader: %w", err)
+			continue
+		}
+		resolved := pageURL.ResolveReference(ref)
+		if resolved.Scheme != "http" && resolved.Scheme != "https" {
+			continue
 		}
-		defer gzReader.Close()
-		reader = gzReader
-	}
-
-	body, err := io.ReadAll(reader)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
-	}
-
-	doc, err := goquery.NewDocumentFromReader(strings.NewReader(string(body)))
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse HTML: %w", err)
-	}
-
-	// Extrair título
-	title := extractTitle(doc)
 
-	// Extrair conteúdo principal
-	content := extractMainContent(doc, targetURL)
+		// Páginas que apontam o canonical para a home não identificam o artigo
+		if strings.Trim(resolved.Path, "/") == "" && resolved.RawQuery == "" {
+			continue
+		}
 
-	if content == "" {
-		return nil, fmt.Errorf("could not extract article content")
+		return resolved.String()
 	}
 
-	log.Infof("Successfully extracted article content (%d chars) from: %s", len(content), targetURL)
-
-	return &ArticleContent{
-		Title:       title,
-		Content:     content,
-		ContentType: "html",
-	}, nil
+	return ""
 }
 
 // extractTitle extrai o título do artigo
@@ -401,30 +190,15 @@ func extractTitle(doc *goquery.Document) string {
 	return strings.TrimSpace(titleText)
 }
 
-// extractMainContent extrai o conteúdo principal do artigo
-func extractMainContent(doc *goquery.Document, pageURL string) string {
+// contentFunc localiza o HTML do conteúdo principal na página de um site
+type contentFunc func(doc *goquery.Document) string
+
+// extractMainContent extrai o conteúdo principal do artigo com a função do extrator
+func extractMainContent(doc *goquery.Document, extract contentFunc) string {
 	// Remover elementos indesejados primeiro
 	removeUnwantedElements(doc)
 
-	var content string
-	host := strings.ToLower(pageURL)
-
-	// Estratégias específicas por domínio
-	if strings.Contains(host, "medium.com") || strings.Contains(host, "towardsdatascience.com") ||
-		strings.Contains(host, "freedium") || strings.Contains(host, "scribe.rip") ||
-		strings.Contains(host, "levelup.gitconnected.com") || strings.Contains(host, "betterprogramming.pub") {
-		content = extractMediumContent(doc)
-	} else if strings.Contains(host, "dev.to") {
-		content = extractDevToContent(doc)
-	} else if strings.Contains(host, "github.com") {
-		content = extractGitHubHTMLContent(doc)
-	} else if strings.Contains(host, "substack.com") {
-		content = extractSubstackContent(doc)
-	} else {
-		content = extractGenericContent(doc)
-	}
-
-	return cleanContent(content)
+	return cleanContent(extract(doc))
 }
 
 // removeUnwantedElements remove elementos que não fazem parte do conteúdo principal
@@ -451,149 +225,6 @@ func removeUnwantedElements(doc *goquery.Document) {
 	}
 }
 
-// extractMediumContent extrai conteúdo do Medium e proxies
-func extractMediumContent(doc *goquery.Document) string {
-	// Tentar seletores específicos do Scribe.rip (mais limpo)
-	if content, _ := doc.Find(".main-content article").Html(); content != "" && len(content) > 200 {
-		return content
-	}
-
-	// Freedium
-	if content, _ := doc.Find(".main-content").Html(); content != "" && len(content) > 200 {
-		return content
-	}
-
-	// Medium original - section com o artigo
-	if content, _ := doc.Find("article section").Html(); content != "" && len(content) > 200 {
-		return content
-	}
-
-	// Medium - article tag
-	if content, _ := doc.Find("article").Html(); content != "" && len(content) > 200 {
-		return content
-	}
-
-	// Tentar pegar por parágrafos do Medium
-	var paragraphs []string
-	doc.Find("article p, article h1, article h2, article h3, article pre, article code, article ul, article ol, article blockquote, article figure").Each(func(i int, s *goquery.Selection) {
-		if html, _ := s.Html(); html != "" {
-			tagName := goquery.NodeName(s)
-			paragraphs = append(paragraphs, fmt.Sprintf("<%s>%s</%s>", tagName, html, tagName))
-		}
-	})
-
-	if len(paragraphs) > 3 {
-		return strings.Join(paragraphs, "\n")
-	}
-
-	return ""
-}
-
-// extractDevToContent extrai conteúdo do Dev.to
-func extractDevToContent(doc *goquery.Document) string {
-	// Seletor principal do Dev.to
-	if content, _ := doc.Find("#article-body").Html(); content != "" {
-		return content
-	}
-
-	if content, _ := doc.Find(".crayons-article__body").Html(); content != "" {
-		return content
-	}
-
-	if content, _ := doc.Find(".crayons-article__main").Html(); content != "" {
-		return content
-	}
-
-	// Fallback
-	if content, _ := doc.Find("article").Html(); content != "" {
-		return content
-	}
-
-	return ""
-}
-
-// extractGitHubHTMLContent extrai conteúdo HTML do GitHub
-func extractGitHubHTMLContent(doc *goquery.Document) string {
-	// README renderizado
-	if content, _ := doc.Find(".markdown-body").First().Html(); content != "" {
-		return content
-	}
-
-	// Box do README
-	if content, _ := doc.Find("#readme .Box-body").Html(); content != "" {
-		return content
-	}
-
-	// Arquivo markdown
-	if content, _ := doc.Find("[data-target='readme-toc.content']").Html(); content != "" {
-		return content
-	}
-
-	// Issues/PRs
-	if content, _ := doc.Find(".comment-body").First().Html(); content != "" {
-		return content
-	}
-
-	return ""
-}
-
-// extractSubstackContent extrai conteúdo do Substack
-func extractSubstackContent(doc *goquery.Document) string {
-	// Conteúdo do post
-	if content, _ := doc.Find(".body.markup").Html(); content != "" {
-		return content
-	}
-
-	if content, _ := doc.Find(".post-content").Html(); content != "" {
-		return content
-	}
-
-	if content, _ := doc.Find(".available-content").Html(); content != "" {
-		return content
-	}
-
-	// Fallback
-	if content, _ := doc.Find("article").Html(); content != "" {
-		return content
-	}
-
-	return ""
-}
-
-// extractGenericContent tenta extrair conteúdo de forma genérica
-func extractGenericContent(doc *goquery.Document) string {
-	// Ordem de prioridade para encontrar o conteúdo principal
-	selectors := []string{
-		"article",
-		"[role='main']",
-		"main",
-		".post-content",
-		".article-content",
-		".entry-content",
-		".post-body",
-		".article-body",
-		".story-body",
-		".content-body",
-		"#content",
-		"#main-content",
-		".main-content",
-		".content",
-	}
-
-	for _, selector := range selectors {
-		if content, _ := doc.Find(selector).First().Html(); content != "" && len(content) > 500 {
-			return content
-		}
-	}
-
-	// Última tentativa: pegar o body inteiro (limitado)
-	if content, _ := doc.Find("body").Html(); content != "" {
-		return content
-	}
-
-	return ""
-}
-
 // cleanContent limpa o HTML extraído
 func cleanContent(content string) string {
 	if content == "" {
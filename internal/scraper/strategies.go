@@ -0,0 +1,101 @@
+package scraper
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// demoteAfterFailures falhas seguidas rebaixam a estratégia para o fim da fila
+	demoteAfterFailures = 3
+
+	// demotionPeriod é quanto tempo, após a última falha, a estratégia fica rebaixada;
+	// depois disso ela volta à posição configurada e tem outra chance
+	demotionPeriod = 30 * time.Minute
+)
+
+// StrategyStats são os resultados acumulados de uma estratégia de busca (um espelho
+// ou o acesso direto)
+type StrategyStats struct {
+	Name                string    `json:"name"`
+	Successes           int       `json:"successes"`
+	Failures            int       `json:"failures"`
+	SuccessRate         float64   `json:"success_rate"`
+	ConsecutiveFailures int       `json:"consecutive_failures"`
+	LastSuccess         time.Time `json:"last_success"`
+	LastFailure         time.Time `json:"last_failure"`
+	Demoted             bool      `json:"demoted"`
+}
+
+// strategyTracker registra sucesso e falha de cada estratégia e reordena a fila,
+// rebaixando as que estão falhando
+type strategyTracker struct {
+	mu    sync.Mutex
+	stats map[string]*StrategyStats
+	now   func() time.Time
+}
+
+func newStrategyTracker() *strategyTracker {
+	return &strategyTracker{stats: make(map[string]*StrategyStats), now: time.Now}
+}
+
+// record contabiliza o resultado de uma tentativa
+func (t *strategyTracker) record(name string, ok bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	s, found := t.stats[name]
+	if !found {
+		s = &StrategyStats{Name: name}
+		t.stats[name] = s
+	}
+	if ok {
+		s.Successes++
+		s.ConsecutiveFailures = 0
+		s.LastSuccess = t.now()
+	} else {
+		s.Failures++
+		s.ConsecutiveFailures++
+		s.LastFailure = t.now()
+	}
+	s.SuccessRate = float64(s.Successes) / float64(s.Successes+s.Failures)
+}
+
+// demotedLocked informa se a estratégia está rebaixada (chamado com o lock)
+func (t *strategyTracker) demotedLocked(name string) bool {
+	s, ok := t.stats[name]
+	return ok && s.ConsecutiveFailures >= demoteAfterFailures && t.now().Sub(s.LastFailure) < demotionPeriod
+}
+
+// order devolve as estratégias na ordem configurada, com as rebaixadas no fim
+func (t *strategyTracker) order(names []string) []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	ordered := make([]string, 0, len(names))
+	var demoted []string
+	for _, name := range names {
+		if t.demotedLocked(name) {
+			demoted = append(demoted, name)
+			continue
+		}
+		ordered = append(ordered, name)
+	}
+	return append(ordered, demoted...)
+}
+
+// snapshot copia as estatísticas, ordenadas por nome
+func (t *strategyTracker) snapshot() []StrategyStats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	stats := make([]StrategyStats, 0, len(t.stats))
+	for name, s := range t.stats {
+		copied := *s
+		copied.Demoted = t.demotedLocked(name)
+		stats = append(stats, copied)
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Name < stats[j].Name })
+	return stats
+}
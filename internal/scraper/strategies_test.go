@@ -0,0 +1,35 @@
+package scraper
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStrategyTrackerDemotionExpires(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	tracker := newStrategyTracker()
+	tracker.now = func() time.Time { return now }
+
+	for i := 0; i < demoteAfterFailures; i++ {
+		tracker.record("mirror", false)
+	}
+	tracker.record("direct", true)
+
+	if got := strings.Join(tracker.order([]string{"mirror", "direct"}), ","); got != "direct,mirror" {
+		t.Errorf("order after failures = %s, want direct,mirror", got)
+	}
+
+	// Passado o período de rebaixamento, a ordem configurada volta a valer
+	now = now.Add(demotionPeriod + time.Minute)
+	if got := strings.Join(tracker.order([]string{"mirror", "direct"}), ","); got != "mirror,direct" {
+		t.Errorf("order after demotion period = %s, want mirror,direct", got)
+	}
+
+	// Um sucesso zera as falhas seguidas
+	now = now.Add(-demotionPeriod)
+	tracker.record("mirror", true)
+	if got := strings.Join(tracker.order([]string{"mirror", "direct"}), ","); got != "mirror,direct" {
+		t.Errorf("order after success = %s, want mirror,direct", got)
+	}
+}
@@ -0,0 +1,57 @@
+package scraper
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+func init() {
+	Register(substackExtractor{})
+}
+
+// substackExtractor busca posts das newsletters hospedadas em *.substack.com
+type substackExtractor struct{}
+
+func (substackExtractor) Name() string          { return "substack" }
+func (substackExtractor) Priority() int         { return 10 }
+func (substackExtractor) Match(u *url.URL) bool { return hostMatches(u, "substack.com") }
+
+func (substackExtractor) Fetch(ctx context.Context, articleURL string) (*ArticleContent, error) {
+	// Substack geralmente permite acesso ao conteúdo público
+	return tryFetchWithHeaders(ctx, articleURL, map[string]string{
+		"Accept":          "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8",
+		"Accept-Language": "en-US,en;q=0.9",
+		"Accept-Encoding": "gzip, deflate",
+		"Connection":      "keep-alive",
+		"Referer":         "https://substack.com/",
+	}, extractSubstackContent)
+}
+
+func (substackExtractor) ExtractContent(doc *goquery.Document) string {
+	return extractSubstackContent(doc)
+}
+
+// extractSubstackContent extrai conteúdo do Substack
+func extractSubstackContent(doc *goquery.Document) string {
+	// Conteúdo do post
+	if content, _ := doc.Find(".body.markup").Html(); content != "" {
+		return content
+	}
+
+	if content, _ := doc.Find(".post-content").Html(); content != "" {
+		return content
+	}
+
+	if content, _ := doc.Find(".available-content").Html(); content != "" {
+		return content
+	}
+
+	// Fallback
+	if content, _ := doc.Find("article").Html(); content != "" {
+		return content
+	}
+
+	return ""
+}
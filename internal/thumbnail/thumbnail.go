@@ -0,0 +1,237 @@
+package thumbnail
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif"
+	"image/jpeg"
+	_ "image/png"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gustavoflandal/gmail-scanner/internal/scraper"
+	"github.com/sirupsen/logrus"
+)
+
+var log = logrus.New()
+
+const (
+	// DefaultWidth é a largura padrão das miniaturas
+	DefaultWidth = 400
+
+	// maxImageBytes limita o download de cada imagem
+	maxImageBytes = 8 << 20
+
+	// maxImagePixels recusa imagens cuja decodificação ocuparia memória demais
+	maxImagePixels = 40_000_000
+
+	// minImageSide descarta pixels de rastreamento e ícones que passaram pela extração
+	minImageSide = 16
+
+	// failureTTL evita baixar de novo, a cada requisição, imagens que falharam
+	failureTTL = 24 * time.Hour
+
+	// lockStripes é o número de locks compartilhados pelas imagens (ver lockFor)
+	lockStripes = 256
+)
+
+// Cache guarda em disco cópias reduzidas (JPEG) das imagens dos artigos, para
+// que a interface não precise carregar as imagens direto dos sites de origem
+type Cache struct {
+	dir    string
+	width  int
+	client *http.Client
+
+	locks [lockStripes]sync.Mutex // Escolhido pelo hash da imagem
+}
+
+// New cria o cache no diretório informado; width <= 0 usa DefaultWidth
+func New(dir string, width int) (*Cache, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create thumbnail directory: %w", err)
+	}
+	if width <= 0 {
+		width = DefaultWidth
+	}
+
+	return &Cache{
+		dir:    dir,
+		width:  width,
+		client: scraper.NewSafeClient(20 * time.Second), // Só endereços públicos (URLs vêm dos emails)
+	}, nil
+}
+
+// Path retorna o arquivo da miniatura da imagem, baixando e reduzindo na primeira vez
+func (c *Cache) Path(imageURL string) (string, error) {
+	key := cacheKey(imageURL)
+	path := filepath.Join(c.dir, key+".jpg")
+	failed := filepath.Join(c.dir, key+".failed")
+
+	// Uma requisição por imagem; as demais esperam e reaproveitam o arquivo
+	lock := c.lockFor(key)
+	lock.Lock()
+	defer lock.Unlock()
+
+	if _, err := os.Stat(path); err == nil {
+		return path, nil
+	}
+	if info, err := os.Stat(failed); err == nil && time.Since(info.ModTime()) < failureTTL {
+		return "", fmt.Errorf("thumbnail recently failed for %s", imageURL)
+	}
+
+	if err := c.create(imageURL, path); err != nil {
+		if writeErr := os.WriteFile(failed, []byte(err.Error()), 0644); writeErr != nil {
+			log.Warnf("Failed to record thumbnail failure: %v", writeErr)
+		}
+		return "", err
+	}
+
+	os.Remove(failed)
+	return path, nil
+}
+
+// create baixa a imagem, reduz para a largura configurada e grava como JPEG
+func (c *Cache) create(imageURL, path string) error {
+	req, err := http.NewRequest(http.MethodGet, imageURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create image request: %w", err)
+	}
+	req.Header.Set("User-Agent", "Mozilla/5.0 (compatible; Gmail-Scanner/1.0)")
+	req.Header.Set("Accept", "image/jpeg,image/png,image/gif,image/*;q=0.8")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to download image: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("image request returned status %d", resp.StatusCode)
+	}
+	if contentType := resp.Header.Get("Content-Type"); contentType != "" && !strings.HasPrefix(contentType, "image/") {
+		return fmt.Errorf("unexpected content type %q", contentType)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxImageBytes))
+	if err != nil {
+		return fmt.Errorf("failed to read image: %w", err)
+	}
+
+	// Conferir as dimensões antes de decodificar (imagens pequenas no disco podem
+	// ocupar gigabytes na memória)
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to decode image: %w", err)
+	}
+	if config.Width < minImageSide || config.Height < minImageSide {
+		return fmt.Errorf("image too small (%dx%d)", config.Width, config.Height)
+	}
+	if config.Width*config.Height > maxImagePixels {
+		return fmt.Errorf("image too large (%dx%d)", config.Width, config.Height)
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	dst := resize(src, c.width)
+
+	// Gravar em arquivo temporário e renomear para nunca servir uma miniatura pela metade
+	tmp, err := os.CreateTemp(c.dir, "thumb-*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create thumbnail file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := jpeg.Encode(tmp, dst, &jpeg.Options{Quality: 80}); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to encode thumbnail: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write thumbnail: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to store thumbnail: %w", err)
+	}
+
+	log.Infof("Created thumbnail for %s", imageURL)
+	return nil
+}
+
+// lockFor retorna o lock da imagem. O conjunto é fixo (um mapa por URL cresceria
+// sem limite): imagens diferentes podem dividir um lock, o que só as serializa.
+func (c *Cache) lockFor(key string) *sync.Mutex {
+	stripe, _ := strconv.ParseUint(key[:2], 16, 8)
+	return &c.locks[stripe%lockStripes]
+}
+
+// cacheKey deriva o nome do arquivo da URL da imagem
+func cacheKey(imageURL string) string {
+	sum := sha256.Sum256([]byte(imageURL))
+	return hex.EncodeToString(sum[:16])
+}
+
+// resize reduz a imagem para a largura informada mantendo a proporção, pela
+// média das áreas de origem (suficiente para miniaturas, sem dependências externas).
+// Imagens menores que a largura só têm o fundo transparente achatado.
+func resize(src image.Image, width int) *image.RGBA {
+	bounds := src.Bounds()
+	srcW, srcH := bounds.Dx(), bounds.Dy()
+	if srcW <= width {
+		width = srcW
+	}
+	height := srcH * width / srcW
+	if height < 1 {
+		height = 1
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		y0 := bounds.Min.Y + y*srcH/height
+		y1 := bounds.Min.Y + (y+1)*srcH/height
+		if y1 <= y0 {
+			y1 = y0 + 1
+		}
+		for x := 0; x < width; x++ {
+			x0 := bounds.Min.X + x*srcW/width
+			x1 := bounds.Min.X + (x+1)*srcW/width
+			if x1 <= x0 {
+				x1 = x0 + 1
+			}
+
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, ca := src.At(sx, sy).RGBA()
+					r += uint64(cr)
+					g += uint64(cg)
+					b += uint64(cb)
+					a += uint64(ca)
+					n++
+				}
+			}
+
+			// JPEG não tem transparência: compor sobre fundo branco
+			alpha := a / n
+			white := uint64(0xffff) - alpha
+			dst.Set(x, y, color.RGBA64{
+				R: uint16(r/n + white),
+				G: uint16(g/n + white),
+				B: uint16(b/n + white),
+				A: 0xffff,
+			})
+		}
+	}
+	return dst
+}
@@ -0,0 +1,124 @@
+package unsubscribe
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gustavoflandal/gmail-scanner/internal/scraper"
+)
+
+// oneClickBody é o corpo exigido pela RFC 8058
+const oneClickBody = "List-Unsubscribe=One-Click"
+
+// Options são as formas de descadastro anunciadas nos cabeçalhos do email
+type Options struct {
+	HTTPURL  string `json:"http_url,omitempty"`
+	Mailto   string `json:"mailto,omitempty"`
+	OneClick bool   `json:"one_click"` // HTTPURL aceita POST sem interação (RFC 8058)
+}
+
+// Available indica se há alguma forma de descadastro
+func (o Options) Available() bool {
+	return o.HTTPURL != "" || o.Mailto != ""
+}
+
+// Parse interpreta "List-Unsubscribe: <mailto:...>, <https://...>" e
+// "List-Unsubscribe-Post: List-Unsubscribe=One-Click"
+func Parse(header, post string) Options {
+	var opts Options
+
+	for _, part := range entries(header) {
+		parsed, err := url.Parse(part)
+		if err != nil {
+			continue
+		}
+
+		switch strings.ToLower(parsed.Scheme) {
+		case "mailto":
+			if opts.Mailto == "" {
+				opts.Mailto = part
+			}
+		case "https", "http":
+			if opts.HTTPURL == "" {
+				opts.HTTPURL = part
+			}
+		}
+	}
+
+	// A RFC 8058 só vale para URLs HTTPS acompanhadas do cabeçalho Post
+	opts.OneClick = strings.EqualFold(strings.TrimSpace(post), oneClickBody) &&
+		strings.HasPrefix(strings.ToLower(opts.HTTPURL), "https://")
+
+	return opts
+}
+
+// entries extrai as URLs entre <...> do List-Unsubscribe (RFC 2369). Vírgulas
+// são válidas dentro das URLs, então o cabeçalho não é dividido por elas.
+func entries(header string) []string {
+	var urls []string
+	for {
+		start := strings.IndexByte(header, '<')
+		if start < 0 {
+			break
+		}
+		end := strings.IndexByte(header[start+1:], '>')
+		if end < 0 {
+			break
+		}
+		// Espaços e quebras de linha dentro dos <> são ignorados (RFC 2369)
+		if entry := strings.Join(strings.Fields(header[start+1:start+1+end]), ""); entry != "" {
+			urls = append(urls, entry)
+		}
+		header = header[start+1+end+1:]
+	}
+	return urls
+}
+
+// Client executa o descadastro one-click
+type Client struct {
+	client *http.Client
+}
+
+// New cria um cliente com timeout curto. A URL vem do cabeçalho do email, então
+// usa o cliente do scraper (só endereços públicos) e só segue redirects HTTPS.
+func New() *Client {
+	client := scraper.NewSafeClient(15 * time.Second)
+	checkRedirect := client.CheckRedirect
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if req.URL.Scheme != "https" {
+			return fmt.Errorf("unsubscribe redirect to non-https URL %s", req.URL.Redacted())
+		}
+		return checkRedirect(req, via)
+	}
+	return &Client{client: client}
+}
+
+// OneClick envia o POST da RFC 8058 para a URL de descadastro
+func (c *Client) OneClick(target string) error {
+	req, err := http.NewRequest(http.MethodPost, target, strings.NewReader(oneClickBody))
+	if err != nil {
+		return fmt.Errorf("failed to create unsubscribe request: %w", err)
+	}
+	// A RFC 8058 exige HTTPS
+	if req.URL.Scheme != "https" {
+		return fmt.Errorf("unsubscribe URL must use https")
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("User-Agent", "Mozilla/5.0 (compatible; Gmail-Scanner/1.0)")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send unsubscribe request: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unsubscribe request returned status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package unsubscribe
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name, header, post string
+		want               Options
+	}{
+		{
+			name:   "mailto and https with one-click",
+			header: "<mailto:leave@list.example?subject=unsubscribe>, <https://list.example/u?id=1>",
+			post:   "List-Unsubscribe=One-Click",
+			want:   Options{HTTPURL: "https://list.example/u?id=1", Mailto: "mailto:leave@list.example?subject=unsubscribe", OneClick: true},
+		},
+		{
+			name:   "commas inside the URL",
+			header: "<https://list.example/u?lists=1,2,3&t=a,b>, <mailto:leave@list.example>",
+			want:   Options{HTTPURL: "https://list.example/u?lists=1,2,3&t=a,b", Mailto: "mailto:leave@list.example"},
+		},
+		{
+			name:   "folded header",
+			header: "<https://list.example/u?id=1,\r\n 2>",
+			want:   Options{HTTPURL: "https://list.example/u?id=1,2"},
+		},
+		{
+			name:   "one-click needs https",
+			header: "<http://list.example/u>",
+			post:   "List-Unsubscribe=One-Click",
+			want:   Options{HTTPURL: "http://list.example/u"},
+		},
+		{
+			name:   "first of each kind wins",
+			header: "<https://a.example/u>, <https://b.example/u>",
+			want:   Options{HTTPURL: "https://a.example/u"},
+		},
+		{
+			name:   "text outside angle brackets is ignored",
+			header: "https://list.example/u, (comment) <ftp://list.example/u>",
+			want:   Options{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Parse(tt.header, tt.post); got != tt.want {
+				t.Errorf("Parse() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOneClickRefusesInternalAddresses(t *testing.T) {
+	hit := false
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hit = true
+	}))
+	defer server.Close()
+
+	// O servidor de teste escuta em 127.0.0.1: o cliente protegido não conecta
+	if err := New().OneClick(server.URL + "/unsubscribe"); err == nil {
+		t.Error("one-click to a loopback address succeeded")
+	}
+	if err := New().OneClick("http://example.com/unsubscribe"); err == nil {
+		t.Error("one-click to an http URL succeeded")
+	}
+	if hit {
+		t.Error("request reached the internal server")
+	}
+}
@@ -0,0 +1,172 @@
+package urlnorm
+
+import (
+	"net"
+	"net/url"
+	"strings"
+)
+
+// trackingParams são parâmetros de query removidos de todas as URLs
+var trackingParams = []string{
+	"utm_source", "utm_medium", "utm_campaign", "utm_term", "utm_content",
+	"ref", "source", "mc_cid", "mc_eid",
+	"fbclid", "gclid", "dclid",
+	"_ga", "_gl",
+	"oly_enc_id", "oly_anon_id",
+	"vero_id", "vero_conv",
+	"spm", "share_token",
+	"si", "feature",
+}
+
+// ampParams são parâmetros que só indicam a variante AMP da página
+var ampParams = []string{"amp", "amp_js_v", "amp_gsa", "usqp", "_js_v", "outputtype"}
+
+// hostPrefixes são subdomínios de variante (mobile, AMP, www) ignorados na chave
+var hostPrefixes = []string{"www.", "m.", "mobile.", "amp."}
+
+// Normalize remove parâmetros de tracking, fragmento e barra final, põe o host
+// em minúsculas e troca URLs de cache AMP pela URL de origem. O resultado
+// continua sendo uma URL acessível, usada como url do artigo.
+func Normalize(u *url.URL) string {
+	normalized := unwrapAMPCache(u)
+
+	normalized.Scheme = strings.ToLower(normalized.Scheme)
+	normalized.Host = normalizeHost(normalized.Scheme, normalized.Host)
+
+	// Remover parâmetros de tracking
+	query := normalized.Query()
+	for _, param := range trackingParams {
+		query.Del(param)
+	}
+
+	// Se não sobrou nenhum parâmetro, remover o "?" (Encode ordena os parâmetros)
+	if len(query) == 0 {
+		normalized.RawQuery = ""
+	} else {
+		normalized.RawQuery = query.Encode()
+	}
+
+	// Remover fragmento (#)
+	normalized.Fragment = ""
+
+	// Remover barra final desnecessária
+	normalized.Path = strings.TrimSuffix(normalized.Path, "/")
+	normalized.RawPath = ""
+
+	return normalized.String()
+}
+
+// Key gera a chave de deduplicação: além de Normalize, ignora http vs https,
+// www./m./mobile./amp. no host e as variantes AMP do caminho e da query.
+// Não é uma URL para acesso, apenas para comparação.
+func Key(u *url.URL) string {
+	parsed, err := url.Parse(Normalize(u))
+	if err != nil {
+		return u.String()
+	}
+
+	if parsed.Scheme == "http" {
+		parsed.Scheme = "https"
+	}
+	parsed.Host = stripVariantPrefixes(normalizeHost("https", parsed.Host))
+	parsed.Path = stripAMPPath(parsed.Path)
+
+	query := parsed.Query()
+	for key := range query {
+		for _, param := range ampParams {
+			if strings.EqualFold(key, param) {
+				query.Del(key)
+			}
+		}
+	}
+	parsed.RawQuery = query.Encode()
+
+	return parsed.String()
+}
+
+// KeyString é Key para URLs em texto; URLs inválidas viram a própria string
+func KeyString(rawURL string) string {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || u.Host == "" {
+		return rawURL
+	}
+	return Key(u)
+}
+
+// normalizeHost põe o host em minúsculas e remove ponto final e porta padrão
+func normalizeHost(scheme, host string) string {
+	host = strings.ToLower(host)
+
+	hostname, port, err := net.SplitHostPort(host)
+	if err != nil {
+		return strings.TrimSuffix(host, ".")
+	}
+
+	hostname = strings.TrimSuffix(hostname, ".")
+	if (scheme == "http" && port == "80") || (scheme == "https" && port == "443") {
+		return hostname
+	}
+	return net.JoinHostPort(hostname, port)
+}
+
+// stripVariantPrefixes remove www./m./mobile./amp., mantendo ao menos um domínio com ponto
+func stripVariantPrefixes(host string) string {
+	for changed := true; changed; {
+		changed = false
+		for _, prefix := range hostPrefixes {
+			rest := strings.TrimPrefix(host, prefix)
+			if rest != host && strings.Contains(rest, ".") {
+				host = rest
+				changed = true
+			}
+		}
+	}
+	return host
+}
+
+// stripAMPPath remove marcadores AMP do caminho: /amp, /amp/ no início e .amp antes da extensão
+func stripAMPPath(path string) string {
+	path = strings.TrimSuffix(path, "/amp")
+	if strings.HasPrefix(path, "/amp/") {
+		path = strings.TrimPrefix(path, "/amp")
+	}
+	path = strings.Replace(path, ".amp.", ".", 1)
+	return strings.TrimSuffix(path, ".amp")
+}
+
+// unwrapAMPCache converte URLs do cache AMP do Google para a URL de origem:
+// www.google.com/amp/s/example.com/a e example-com.cdn.ampproject.org/c/s/example.com/a
+func unwrapAMPCache(u *url.URL) url.URL {
+	host := strings.ToLower(u.Hostname())
+	path := u.Path
+
+	var rest string
+	switch {
+	case (host == "www.google.com" || host == "google.com") && strings.HasPrefix(path, "/amp/"):
+		rest = strings.TrimPrefix(path, "/amp/")
+	case strings.HasSuffix(host, ".cdn.ampproject.org"):
+		for _, prefix := range []string{"/c/", "/v/", "/i/"} {
+			if strings.HasPrefix(path, prefix) {
+				rest = strings.TrimPrefix(path, prefix)
+				break
+			}
+		}
+	}
+
+	if rest == "" {
+		return *u
+	}
+
+	scheme := "http"
+	if strings.HasPrefix(rest, "s/") {
+		scheme = "https"
+		rest = strings.TrimPrefix(rest, "s/")
+	}
+
+	origin, err := url.Parse(scheme + "://" + rest)
+	if err != nil || origin.Host == "" {
+		return *u
+	}
+	origin.RawQuery = u.RawQuery
+	return *origin
+}
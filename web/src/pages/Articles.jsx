@@ -548,9 +548,20 @@ function Articles() {
                         {formatNewsletterName(link.newsletter)}
                       </td>
                       <td className="px-6 py-4 text-sm text-gray-900 max-w-md">
-                        <span className="line-clamp-2" title={link.title || link.url}>
-                          {link.title || 'Sem título'}
-                        </span>
+                        <div className="flex items-center gap-3">
+                          {link.image_url && (
+                            <img
+                              src={apiService.getArticleThumbnailUrl(link.id)}
+                              alt={link.image_alt || ''}
+                              loading="lazy"
+                              className="h-10 w-16 flex-shrink-0 rounded object-cover bg-gray-100"
+                              onError={(e) => { e.currentTarget.style.display = 'none'; }}
+                            />
+                          )}
+                          <span className="line-clamp-2" title={link.title || link.url}>
+                            {link.title || 'Sem título'}
+                          </span>
+                        </div>
                       </td>
                       <td className="px-6 py-4 whitespace-nowrap text-sm">
                         <div className="flex items-center gap-2">
@@ -118,6 +118,41 @@ export const apiService = {
   },
 
   // Estatísticas
+  getNewsletters: async () => {
+    const response = await api.get('/newsletters');
+    return response.data;
+  },
+
+  createNewsletter: async (newsletter) => {
+    const response = await api.post('/newsletters', newsletter);
+    return response.data;
+  },
+
+  updateNewsletter: async (id, changes) => {
+    const response = await api.put(`/newsletters/${id}`, changes);
+    return response.data;
+  },
+
+  deleteNewsletter: async (id) => {
+    const response = await api.delete(`/newsletters/${id}`);
+    return response.data;
+  },
+
+  mergeNewsletters: async (id, ids) => {
+    const response = await api.post(`/newsletters/${id}/merge`, { ids });
+    return response.data;
+  },
+
+  getNewsletterRanking: async (order = 'asc') => {
+    const response = await api.get(`/newsletters/ranking?order=${order}`);
+    return response.data;
+  },
+
+  unsubscribeNewsletter: async (id) => {
+    const response = await api.post(`/newsletters/${id}/unsubscribe`);
+    return response.data;
+  },
+
   getStats: async () => {
     const response = await api.get('/stats');
     return response.data;
@@ -153,6 +188,83 @@ export const apiService = {
     return response.data;
   },
 
+  setArticlesStatus: async (ids, status) => {
+    const response = await api.post('/articles/status', { ids, status });
+    return response.data;
+  },
+
+  setArticlesStarred: async (ids, starred) => {
+    const response = await api.post('/articles/star', { ids, starred });
+    return response.data;
+  },
+
+  importArticles: async (format, file) => {
+    const formData = new FormData();
+    formData.append('file', file);
+    const response = await api.post(`/articles/import?format=${format}`, formData, { timeout: 300000 });
+    return response.data;
+  },
+
+  // Tags
+  getTags: async () => {
+    const response = await api.get('/tags');
+    return response.data;
+  },
+
+  updateArticlesTags: async (ids, add = [], remove = []) => {
+    const response = await api.post('/articles/tags', { ids, add, remove });
+    return response.data;
+  },
+
+  // URL da miniatura local (o cookie de sessão autentica a tag <img>)
+  getArticleThumbnailUrl: (id) => `${API_BASE}/articles/${id}/thumbnail`,
+
+  getArticleMentions: async (id) => {
+    const response = await api.get(`/articles/${id}/mentions`);
+    return response.data;
+  },
+
+  mergeArticles: async (keepId, ids) => {
+    const response = await api.post('/articles/merge', { keep_id: keepId, ids });
+    return response.data;
+  },
+
+  dedupeArticles: async () => {
+    const response = await api.post('/articles/dedupe');
+    return response.data;
+  },
+
+  deleteTag: async (name) => {
+    const response = await api.delete(`/tags/${encodeURIComponent(name)}`);
+    return response.data;
+  },
+
+  // Regras
+  getRules: async () => {
+    const response = await api.get('/rules');
+    return response.data;
+  },
+
+  createRule: async (rule) => {
+    const response = await api.post('/rules', rule);
+    return response.data;
+  },
+
+  updateRule: async (id, rule) => {
+    const response = await api.put(`/rules/${id}`, rule);
+    return response.data;
+  },
+
+  deleteRule: async (id) => {
+    const response = await api.delete(`/rules/${id}`);
+    return response.data;
+  },
+
+  previewRule: async (rule) => {
+    const response = await api.post('/rules/preview', rule);
+    return response.data;
+  },
+
   // Lista de Leitura (NoSQL)
   importToReadingList: async (article) => {
     const response = await api.post('/reading-list/import', article);
@@ -169,15 +281,27 @@ export const apiService = {
     return response.data;
   },
 
-  getAllFromReadingList: async () => {
-    const response = await api.get('/reading-list');
+  getAllFromReadingList: async (query = '') => {
+    const response = await api.get('/reading-list', { params: query ? { q: query } : {} });
     return response.data;
   },
 
+  getReadingListAttachmentUrl: (id) => `${API_BASE}/reading-list/${id}/attachment`,
+
   getImportedIDs: async () => {
     const response = await api.get('/reading-list/imported-ids');
     return response.data;
   },
+
+  reextractReadingListArticle: async (id) => {
+    const response = await api.post(`/reading-list/${id}/reextract`);
+    return response.data;
+  },
+
+  reextractReadingList: async () => {
+    const response = await api.post('/reading-list/reextract');
+    return response.data;
+  },
 };
 
 export default api;